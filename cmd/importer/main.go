@@ -0,0 +1,99 @@
+// Command importer loads apartments and residents from a CSV or XLSX file, the same way as
+// POST /v1/apartment/import. It uses the backend's environment: DB_DSN, GMAIL_USERNAME,
+// GMAIL_PASSWORD and MESSAGING_* for invitations.
+//
+// Example:
+//
+//	go run ./cmd/importer -file residents.xlsx -dry-run
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/messaging"
+	"github.com/podpivasniki1488/assyl-backend/internal/service"
+)
+
+func main() {
+	file := flag.String("file", "", "path to a .csv or .xlsx file")
+	dryRun := flag.Bool("dry-run", false, "only print what would change")
+	invite := flag.Bool("invite", false, "tell newly created residents how to sign in")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		log.Fatal("DB_DSN is not set")
+	}
+
+	debug := os.Getenv("DEBUG") == "true"
+
+	// nothing is sent without -invite, so the provider is only needed then
+	var messagingRepo messaging.MessagingRepo = messaging.NewFake()
+	if *invite {
+		messagingRepo, err = messaging.NewMessagingRepo(messaging.Config{
+			Provider:              os.Getenv("MESSAGING_PROVIDER"),
+			Debug:                 debug,
+			WhatsAppURL:           os.Getenv("WHATSAPP_URL"),
+			WhatsAppToken:         os.Getenv("WHATSAPP_TOKEN"),
+			WhatsAppPhoneNumberID: os.Getenv("WHATSAPP_PHONE_NUMBER_ID"),
+			SMSGatewayURL:         os.Getenv("SMS_GATEWAY_URL"),
+			SMSAPIKey:             os.Getenv("SMS_API_KEY"),
+			SMSSender:             os.Getenv("SMS_SENDER"),
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// the import touches only Postgres and notifications, so Mongo and OIDC are not needed
+	repo := repository.NewRepository(
+		repository.MustInitDb(dsn),
+		nil,
+		messagingRepo,
+		nil,
+		debug,
+		os.Getenv("GMAIL_USERNAME"),
+		os.Getenv("GMAIL_PASSWORD"),
+	)
+
+	format := model.ImportFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), "."))
+
+	// uuid.Nil: memberships created from the command line have no approving user
+	report, err := service.NewImportService(repo).ImportResidents(context.Background(), uuid.Nil, format, data, model.ImportOptions{
+		DryRun:          *dryRun,
+		SendInvitations: *invite,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	if err = enc.Encode(report); err != nil {
+		log.Fatal(err)
+	}
+
+	if len(report.Errors) > 0 {
+		os.Exit(1)
+	}
+}
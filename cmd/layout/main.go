@@ -0,0 +1,86 @@
+// Command layout creates the apartments of an entrance from a JSON layout, the same way as
+// POST /v1/apartment/layout. It uses the backend's DB_DSN.
+//
+// The layout file:
+//
+//	{
+//	  "building_id": "…", "entrance": 1,
+//	  "from_floor": 2, "to_floor": 16, "from_door": 1, "to_door": 6,
+//	  "exceptions": [{"floor": 2, "from_door": 1, "to_door": 4}, {"floor": 9, "skip": true}]
+//	}
+//
+// Example:
+//
+//	go run ./cmd/layout -file entrance1.json -dry-run
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"github.com/podpivasniki1488/assyl-backend/internal/service"
+)
+
+func main() {
+	file := flag.String("file", "", "path to a .json layout")
+	dryRun := flag.Bool("dry-run", false, "only print what would change")
+	prune := flag.Bool("prune", false, "delete unbound apartments of the entrance that are not in the layout")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var layout model.ApartmentLayout
+	if err = json.Unmarshal(data, &layout); err != nil {
+		log.Fatal(err)
+	}
+
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		log.Fatal("DB_DSN is not set")
+	}
+
+	// the layout touches only Postgres, so Mongo, messaging and OIDC are not needed
+	repo := repository.NewRepository(
+		repository.MustInitDb(dsn),
+		nil,
+		nil,
+		nil,
+		os.Getenv("DEBUG") == "true",
+		"",
+		"",
+	)
+
+	// uuid.Nil: deletions made from the command line have no acting user in the audit log
+	report, err := service.NewLayoutService(repo).GenerateLayout(context.Background(), uuid.Nil, layout, model.LayoutOptions{
+		DryRun: *dryRun,
+		Prune:  *prune,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	if err = enc.Encode(report); err != nil {
+		log.Fatal(err)
+	}
+
+	if len(report.Bound) > 0 {
+		os.Exit(1)
+	}
+}
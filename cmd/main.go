@@ -5,16 +5,21 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"math/rand"
+	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
+	// the venue timezone must load in images without system zoneinfo
+	_ "time/tzdata"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/podpivasniki1488/assyl-backend/internal/delivery"
 	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/messaging"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/oidc"
 	"github.com/podpivasniki1488/assyl-backend/internal/service"
 	"github.com/podpivasniki1488/assyl-backend/pkg"
 	"github.com/redis/go-redis/v9"
@@ -56,8 +61,6 @@ func main() {
 		}
 	}()
 
-	rand.NewSource(time.Now().UnixNano())
-
 	cfg := mustReadConfig()
 
 	shutdownOTel, err := setupOTelSDK(ctx)
@@ -100,15 +103,94 @@ func main() {
 
 	e := echo.New()
 
+	ipExtractor, err := newIPExtractor(cfg.TrustedProxies)
+	if err != nil {
+		panic(err)
+	}
+
+	// c.RealIP() feeds the per-ip login limits and session records, X-Forwarded-For
+	// is only honoured when it comes through one of the configured proxies
+	e.IPExtractor = ipExtractor
+
 	hub := pkg.NewHub()
 
 	db := repository.MustInitDb(cfg.DBDSN)
 
-	repo := repository.NewRepository(db, mongoClient, debug, cfg.GmailUsername, cfg.GmailPassword)
+	messagingRepo, err := messaging.NewMessagingRepo(messaging.Config{
+		Provider:              cfg.MessagingProvider,
+		Debug:                 cfg.Debug,
+		WhatsAppURL:           cfg.WhatsAppURL,
+		WhatsAppToken:         cfg.WhatsAppToken,
+		WhatsAppPhoneNumberID: cfg.WhatsAppPhoneNumberID,
+		SMSGatewayURL:         cfg.SMSGatewayURL,
+		SMSAPIKey:             cfg.SMSAPIKey,
+		SMSSender:             cfg.SMSSender,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	oidcProviders, err := oidc.ParseProviders(cfg.OIDCProviders)
+	if err != nil {
+		panic(err)
+	}
+
+	oidcRepo, err := oidc.NewOIDCRepo(oidcProviders)
+	if err != nil {
+		panic(err)
+	}
+
+	repo := repository.NewRepository(
+		db,
+		mongoClient,
+		messagingRepo,
+		oidcRepo,
+		debug,
+		cfg.GmailUsername,
+		cfg.GmailPassword,
+	)
+
+	// chat message cleanups of erasures that failed in Mongo, EraseUser retries them as well
+	if _, err = repo.ErasureRepo.RetryPending(ctx); err != nil {
+		logger.Error("failed to retry pending erasures", "error", err)
+	}
+
+	jwtKeys, err := pkg.NewJWTKeySet(pkg.JWTConfig{
+		Algorithm:           cfg.JwtAlgorithm,
+		Secret:              cfg.JwtSecretKey,
+		SigningKeyPEM:       cfg.JwtSigningKey,
+		VerificationKeysPEM: cfg.JwtVerificationKeys,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	venueTZ, err := time.LoadLocation(cfg.VenueTimezone)
+	if err != nil {
+		panic(err)
+	}
 
-	srv := service.NewService(repo, redisClient, logger, hub, cfg.JwtSecretKey)
+	totpSecretBox, err := pkg.NewSecretBox(cfg.TotpEncryptionKey)
+	if err != nil {
+		panic(err)
+	}
 
-	d := delivery.NewDelivery(logger, e, srv, cfg.JwtSecretKey)
+	srv := service.NewService(repo, redisClient, logger, hub, cfg.OtpPepper, jwtKeys, service.TwoFactorConfig{
+		Issuer:            cfg.TotpIssuer,
+		RequiredForAdmins: cfg.TotpRequiredForAdmins,
+		SecretBox:         totpSecretBox,
+	}, venueTZ)
+
+	sealed, err := srv.TwoFactor.SealLegacySecrets(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	if sealed > 0 {
+		logger.Info("encrypted plain TOTP secrets", "count", sealed)
+	}
+
+	d := delivery.NewDelivery(logger, e, srv, jwtKeys)
 
 	port := cfg.HttpPort
 
@@ -135,12 +217,56 @@ func mustReadConfig() Config {
 		RedisUsername: os.Getenv("REDIS_USERNAME"),
 		RedisPassword: os.Getenv("REDIS_PASSWORD"),
 		JwtSecretKey:  os.Getenv("JWT_SECRET"),
+		JwtAlgorithm:  os.Getenv("JWT_ALG"),
 		DBDSN:         os.Getenv("DB_DSN"),
 		Debug:         os.Getenv("DEBUG") == "true",
 		GmailUsername: os.Getenv("GMAIL_USERNAME"),
 		GmailPassword: os.Getenv("GMAIL_PASSWORD"),
 		HttpPort:      os.Getenv("PORT"),
 		MongoDSN:      os.Getenv("MONGO_DSN"),
+
+		JwtSigningKey:       os.Getenv("JWT_SIGNING_KEY"),
+		JwtVerificationKeys: os.Getenv("JWT_VERIFICATION_KEYS"),
+
+		// HMAC key for OTP and recovery code hashes, kept apart from JWT_SECRET so the jwt keys can be rotated
+		// freely. Changing it invalidates pending OTP codes and every recovery code.
+		OtpPepper: os.Getenv("OTP_PEPPER"),
+
+		MessagingProvider:     os.Getenv("MESSAGING_PROVIDER"),
+		WhatsAppURL:           os.Getenv("WHATSAPP_URL"),
+		WhatsAppToken:         os.Getenv("WHATSAPP_TOKEN"),
+		WhatsAppPhoneNumberID: os.Getenv("WHATSAPP_PHONE_NUMBER_ID"),
+		SMSGatewayURL:         os.Getenv("SMS_GATEWAY_URL"),
+		SMSAPIKey:             os.Getenv("SMS_API_KEY"),
+		SMSSender:             os.Getenv("SMS_SENDER"),
+
+		TotpIssuer:            os.Getenv("TOTP_ISSUER"),
+		TotpRequiredForAdmins: os.Getenv("TOTP_REQUIRED_FOR_ADMINS") == "true",
+		// base64 of 32 random bytes (openssl rand -base64 32), TOTP secrets are encrypted with it
+		TotpEncryptionKey: os.Getenv("TOTP_ENCRYPTION_KEY"),
+
+		// JSON array, e.g. [{"name":"google","issuer":"https://accounts.google.com","client_id":"...",
+		// "client_secret":"...","redirect_url":"https://.../v1/auth/oidc/google/callback","scopes":["email","profile"]}]
+		OIDCProviders: os.Getenv("OIDC_PROVIDERS"),
+
+		// comma separated CIDRs of the reverse proxies in front of the app, e.g. 10.0.0.0/8,192.168.1.10/32.
+		// Empty means the app is reached directly and X-Forwarded-For is ignored.
+		TrustedProxies: os.Getenv("TRUSTED_PROXIES"),
+
+		// IANA name, slot times and reservation days are in it
+		VenueTimezone: os.Getenv("VENUE_TIMEZONE"),
+	}
+
+	if cfg.JwtAlgorithm == "" {
+		cfg.JwtAlgorithm = pkg.JWTAlgHS256
+	}
+
+	if cfg.TotpIssuer == "" {
+		cfg.TotpIssuer = "Assyl"
+	}
+
+	if cfg.VenueTimezone == "" {
+		cfg.VenueTimezone = "Asia/Almaty"
 	}
 
 	if err := validator.New().Struct(&cfg); err != nil {
@@ -155,12 +281,62 @@ type Config struct {
 	RedisUsername string `validate:"required"`
 	RedisPassword string `validate:"required"`
 	DBDSN         string `validate:"required"`
-	JwtSecretKey  string `validate:"required"`
+	JwtSecretKey  string `validate:"required_if=JwtAlgorithm HS256"`
+	JwtAlgorithm  string `validate:"oneof=HS256 RS256 EdDSA"`
 	Debug         bool
 	GmailUsername string `validate:"required"`
 	GmailPassword string `validate:"required"`
 	HttpPort      string `validate:"required"`
 	MongoDSN      string `validate:"required"`
+
+	JwtSigningKey       string `validate:"required_unless=JwtAlgorithm HS256"`
+	JwtVerificationKeys string
+
+	OtpPepper string `validate:"required"`
+
+	MessagingProvider     string `validate:"required,oneof=whatsapp sms fake"`
+	WhatsAppURL           string
+	WhatsAppToken         string `validate:"required_if=MessagingProvider whatsapp"`
+	WhatsAppPhoneNumberID string `validate:"required_if=MessagingProvider whatsapp"`
+	SMSGatewayURL         string `validate:"required_if=MessagingProvider sms"`
+	SMSAPIKey             string `validate:"required_if=MessagingProvider sms"`
+	SMSSender             string
+
+	TotpIssuer            string
+	TotpRequiredForAdmins bool
+	TotpEncryptionKey     string `validate:"required,base64"`
+
+	OIDCProviders string
+
+	TrustedProxies string
+
+	VenueTimezone string `validate:"timezone"`
+}
+
+// newIPExtractor trusts X-Forwarded-For only from the given proxy ranges. Without them
+// the client ip is the address of the connection.
+func newIPExtractor(trustedProxies string) (echo.IPExtractor, error) {
+	if strings.TrimSpace(trustedProxies) == "" {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	// only the listed ranges are trusted, not echo's defaults (loopback, link-local, private networks)
+	opts := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	for _, cidr := range strings.Split(trustedProxies, ",") {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", cidr, err)
+		}
+
+		opts = append(opts, echo.TrustIPRange(ipNet))
+	}
+
+	return echo.ExtractIPFromXFFHeader(opts...), nil
 }
 
 // setupOTelSDK bootstraps the OpenTelemetry pipeline.
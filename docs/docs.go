@@ -15,6 +15,26 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Публичные ключи для проверки access токенов другими сервисами (RFC 7517).\nВ режиме HS256 список ключей пуст.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "JSON Web Key Set",
+                "responses": {
+                    "200": {
+                        "description": "Набор ключей",
+                        "schema": {
+                            "$ref": "#/definitions/pkg.JWKS"
+                        }
+                    }
+                }
+            }
+        },
         "/apartment": {
             "get": {
                 "security": [
@@ -22,38 +42,69 @@ const docTemplate = `{
                         "JWT": []
                     }
                 ],
-                "description": "Возвращает квартиру по этажу и номеру двери.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает квартиры постранично, отсортированные по этажу и номеру двери.\nС правом apartment.manage (ADMIN, GOD) доступны все фильтры, а у каждой квартиры есть список жильцов.\nОстальные пользователи могут только найти квартиру по адресу: этаж и номер двери обязательны,\nблок и подъезд уточняют поиск, если в комплексе несколько блоков или подъездов.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "apartment"
                 ],
-                "summary": "Get apartment",
+                "summary": "List apartments",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Страница (с 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (до 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID блока",
+                        "name": "building_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер подъезда",
+                        "name": "entrance",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Этаж",
                         "name": "floor",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
                         "type": "integer",
                         "description": "Номер двери",
                         "name": "door_number",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true - только с владельцем, false - только без владельца",
+                        "name": "bound",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID владельца",
+                        "name": "owner_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Квартира найдена",
+                        "description": "Успех",
                         "schema": {
-                            "$ref": "#/definitions/http.DefaultResponse-model_Apartment"
+                            "$ref": "#/definitions/http.DefaultResponse-model_Page-model_ApartmentDetails"
                         }
                     },
                     "400": {
@@ -68,8 +119,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
-                    "404": {
-                        "description": "Квартира не найдена",
+                    "403": {
+                        "description": "Фильтры доступны только администраторам",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
@@ -90,9 +141,10 @@ const docTemplate = `{
                         "JWT": []
                     }
                 ],
-                "description": "Привязывает квартиру к текущему авторизованному пользователю.",
+                "description": "Создаёт заявку текущего пользователя на владение квартирой. К заявке можно приложить документ,\nподтверждающий право собственности (PDF, JPEG или PNG до 5 МБ). Без документа можно отправить те же поля в JSON.\nВладельцем пользователь станет после одобрения заявки администратором.\nС правом apartment.bind_any (ADMIN, GOD) квартира без владельца привязывается сразу, заявка сохраняется одобренной,\nостальные ожидающие заявки на неё отклоняются. Квартиру с владельцем привязать нельзя никому.\nЧтобы вступить в чужую квартиру, подайте заявку через /apartment/{id}/members.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
@@ -100,27 +152,37 @@ const docTemplate = `{
                 "tags": [
                     "apartment"
                 ],
-                "summary": "Bind apartment to user",
+                "summary": "Request apartment binding",
                 "parameters": [
                     {
-                        "description": "Bind apartment request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http.bindApartmentRequest"
-                        }
+                        "type": "string",
+                        "description": "ID квартиры",
+                        "name": "apartment_id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Комментарий для администратора",
+                        "name": "comment",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Подтверждающий документ",
+                        "name": "proof",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Квартира успешно привязана к пользователю",
+                    "201": {
+                        "description": "Заявка создана",
                         "schema": {
-                            "$ref": "#/definitions/http.DefaultResponse-string"
+                            "$ref": "#/definitions/http.DefaultResponse-model_BindingRequest"
                         }
                     },
                     "400": {
-                        "description": "Невалидный запрос (невалидный JSON или тело)",
+                        "description": "Невалидный запрос или документ",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
@@ -138,7 +200,7 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "Квартира уже привязана к пользователю",
+                        "description": "Квартира уже привязана или заявка уже подана",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
@@ -152,40 +214,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/apartment/create": {
-            "post": {
+        "/apartment/bind-requests": {
+            "get": {
                 "security": [
                     {
                         "JWT": []
                     }
                 ],
-                "description": "Создаёт новую квартиру.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает заявки на привязку квартир, старые первыми. Для очереди на рассмотрение передайте status=pending.\nТребуется право apartment.bind_review (ADMIN, GOD).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "apartment"
                 ],
-                "summary": "Create apartment",
+                "summary": "Binding request queue",
                 "parameters": [
                     {
-                        "description": "Create apartment request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http.createApartmentRequest"
-                        }
+                        "type": "integer",
+                        "description": "Страница (с 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (до 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Статус (pending, approved, rejected)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID квартиры",
+                        "name": "apartment_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Квартира успешно создана",
+                    "200": {
+                        "description": "Успех",
                         "schema": {
-                            "$ref": "#/definitions/http.DefaultResponse-string"
+                            "$ref": "#/definitions/http.DefaultResponse-model_Page-model_BindingRequest"
                         }
                     },
                     "400": {
@@ -200,8 +280,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
-                    "409": {
-                        "description": "Квартира уже существует (конфликт)",
+                    "403": {
+                        "description": "Недостаточно прав",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
@@ -215,33 +295,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/confirm": {
-            "post": {
-                "description": "Подтверждает пользователя по OTP-коду.",
-                "consumes": [
-                    "application/json"
+        "/apartment/bind-requests/my": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
                 ],
+                "description": "Возвращает заявки текущего пользователя на привязку квартир с их статусом и причиной отказа.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "apartment"
                 ],
-                "summary": "Confirm registration",
+                "summary": "My binding requests",
                 "parameters": [
                     {
-                        "description": "Confirm request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http.confirmRequest"
-                        }
+                        "type": "integer",
+                        "description": "Страница (с 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (до 100)",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Подтверждение прошло успешно"
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_Page-model_BindingRequest"
+                        }
                     },
                     "400": {
                         "description": "Невалидный запрос",
@@ -249,6 +337,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
@@ -258,85 +352,63 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/login": {
+        "/apartment/bind-requests/{id}/approve": {
             "post": {
-                "description": "Заходит по логину отдавая jwt токен.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "JWT": []
+                    }
                 ],
+                "description": "Одобряет заявку: пользователь становится владельцем квартиры, гость получает роль INHABITANT.\nОстальные ожидающие заявки на эту квартиру отклоняются. Пользователи получают уведомления.\nТребуется право apartment.bind_review (ADMIN, GOD).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "apartment"
                 ],
-                "summary": "Login into account",
+                "summary": "Approve binding request",
                 "parameters": [
                     {
-                        "description": "Login request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http.loginRequest"
-                        }
+                        "type": "string",
+                        "description": "ID заявки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успех",
+                        "description": "Заявка одобрена",
                         "schema": {
-                            "$ref": "#/definitions/http.DefaultResponse-http_loginResponse"
+                            "$ref": "#/definitions/http.DefaultResponse-model_BindingRequest"
                         }
                     },
                     "400": {
-                        "description": "Невалидный запрос",
+                        "description": "Невалидный ID",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
-                    "500": {
-                        "description": "Внутренняя ошибка сервера",
+                    "401": {
+                        "description": "Неавторизован",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
-                    }
-                }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "Регистрирует нового пользователя и отправляет OTP на указанный username (телефон/почта).",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Register new user",
-                "parameters": [
-                    {
-                        "description": "Register request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
                         "schema": {
-                            "$ref": "#/definitions/http.registerRequest"
+                            "$ref": "#/definitions/http.DefaultResponse-error"
                         }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Успешная регистрация",
+                    },
+                    "404": {
+                        "description": "Заявка не найдена",
                         "schema": {
-                            "$ref": "#/definitions/http.DefaultResponse-string"
+                            "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
-                    "400": {
-                        "description": "Невалидный запрос",
+                    "409": {
+                        "description": "Заявка уже рассмотрена или у квартиры уже есть владелец",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
@@ -350,76 +422,75 @@ const docTemplate = `{
                 }
             }
         },
-        "/channel": {
+        "/apartment/bind-requests/{id}/proof": {
             "get": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "JWT": []
                     }
                 ],
-                "description": "Returns channel messages within the time period.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Скачивает документ, приложенный к заявке. Доступно автору заявки и пользователям с правом apartment.bind_review.",
                 "produces": [
+                    "application/pdf",
+                    "image/jpeg",
+                    "image/png",
                     "application/json"
                 ],
                 "tags": [
-                    "channel"
+                    "apartment"
                 ],
-                "summary": "Get channel messages",
+                "summary": "Download binding proof",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "2025-12-01T00:00:00Z",
-                        "description": "From datetime (RFC3339)",
-                        "name": "from",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "2025-12-02T00:00:00Z",
-                        "description": "To datetime (RFC3339)",
-                        "name": "to",
-                        "in": "query",
+                        "description": "ID заявки",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Документ",
                         "schema": {
-                            "$ref": "#/definitions/http.DefaultResponse-array_model_ChannelMessage"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Невалидный ID",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Заявка или документ не найдены",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/apartment/bind-requests/{id}/reject": {
             "post": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "JWT": []
                     }
                 ],
-                "description": "Sends a message to the channel. Only ADMIN or GOD can send.",
+                "description": "Отклоняет заявку с указанием причины, пользователь получает уведомление с этой причиной.\nТребуется право apartment.bind_review (ADMIN, GOD).",
                 "consumes": [
                     "application/json"
                 ],
@@ -427,47 +498,66 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "channel"
+                    "apartment"
                 ],
-                "summary": "Send channel message",
+                "summary": "Reject binding request",
                 "parameters": [
                     {
-                        "description": "Message payload",
-                        "name": "payload",
+                        "type": "string",
+                        "description": "ID заявки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина отказа",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/http.sendChannelMessage"
+                            "$ref": "#/definitions/http.rejectBindingRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "Заявка отклонена",
                         "schema": {
-                            "$ref": "#/definitions/http.DefaultResponse-string"
+                            "$ref": "#/definitions/http.DefaultResponse-model_BindingRequest"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Невалидный запрос",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Неавторизован",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
                     "403": {
-                        "description": "Forbidden",
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Заявка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "Заявка уже рассмотрена",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
@@ -475,14 +565,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/feedback": {
-            "get": {
+        "/apartment/create": {
+            "post": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "JWT": []
                     }
                 ],
-                "description": "Returns feedbacks list (only ADMIN or GOD)",
+                "description": "Создаёт новую квартиру в подъезде блока. building_id и entrance можно не указывать,\nпока в комплексе один блок и один подъезд. Требуется право apartment.create (ADMIN, GOD).",
                 "consumes": [
                     "application/json"
                 ],
@@ -490,109 +580,132 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "feedback"
+                    "apartment"
                 ],
-                "summary": "Get feedbacks",
+                "summary": "Create apartment",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "date-time",
-                        "example": "2026-01-01T00:00:00Z",
-                        "description": "Created from (RFC3339)",
-                        "name": "created_at_from",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "format": "date-time",
-                        "example": "2026-01-02T00:00:00Z",
-                        "description": "Created to (RFC3339)",
-                        "name": "created_at_to",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Text filter",
-                        "name": "text",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Feedback type",
-                        "name": "feedback_type",
-                        "in": "query"
+                        "description": "Create apartment request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.createApartmentRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Квартира успешно создана",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-string"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Невалидный запрос",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Блок или подъезд не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "Квартира уже существует (конфликт)",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/apartment/import": {
             "post": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "JWT": []
                     }
                 ],
-                "description": "Creates feedback message from authorized user",
+                "description": "Загружает таблицу CSV или XLSX с колонками floor, door (обязательные) и building/block, entrance, first_name, last_name,\nphone/email, role (необязательные). Блок и подъезд должны уже существовать, их можно не указывать, пока они однозначны.\nКвартиры и жильцы создаются или обновляются, повторный импорт ничего не меняет.\nНовые жильцы создаются без пароля и задают его через «Забыли пароль»; с send_invitations=true им приходит сообщение об этом.\ndry_run=true только возвращает список изменений. Если в файле есть ошибки, ничего не применяется.\nИмпорт не меняет владельцев: строка owner для квартиры с другим владельцем и строка текущего владельца с другой ролью —\nошибки. Жилец может быть указан только в одной квартире.\nТребуется право apartment.import (ADMIN, GOD).",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "feedback"
+                    "apartment"
                 ],
-                "summary": "Create feedback",
+                "summary": "Import apartments and residents",
                 "parameters": [
                     {
-                        "description": "Feedback payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http.createFeedbackRequest"
-                        }
+                        "type": "file",
+                        "description": "Файл .csv или .xlsx",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только показать изменения",
+                        "name": "dry_run",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Отправить приглашения новым жильцам",
+                        "name": "send_invitations",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Отчёт об импорте",
                         "schema": {
-                            "$ref": "#/definitions/http.DefaultResponse-string"
+                            "$ref": "#/definitions/http.DefaultResponse-model_ImportReport"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Невалидный файл",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
@@ -600,55 +713,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/order": {
+        "/apartment/invitations": {
             "get": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "JWT": []
                     }
                 ],
-                "description": "Возвращает список заказов по фильтрам. Требуется JWT. Роль берётся из токена/контекста.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает приглашения квартиры (без самих кодов).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "apartment"
                 ],
-                "summary": "Get orders",
+                "summary": "List apartment invitations",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Order ID (uuid)",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID (uuid)",
-                        "name": "user_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Order type (string, must match protopb.OrderType enum name)",
-                        "name": "order_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search by text",
-                        "name": "text",
-                        "in": "query"
+                        "description": "ID квартиры",
+                        "name": "apartment_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Успех",
                         "schema": {
-                            "$ref": "#/definitions/http.DefaultResponse-array_model_Order"
+                            "$ref": "#/definitions/http.DefaultResponse-array_model_Invitation"
                         }
                     },
                     "400": {
@@ -658,7 +751,19 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Не авторизован / некорректный user_id в токене",
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Квартира не найдена",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
@@ -674,10 +779,10 @@ const docTemplate = `{
             "post": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "JWT": []
                     }
                 ],
-                "description": "Создаёт заявку/заказ от имени авторизованного пользователя. OrderType должен существовать в enum protopb.OrderType.",
+                "description": "Создаёт код приглашения в квартиру. Код показывается один раз.\nАдмины могут приглашать в любую квартиру, владельцы и совладельцы — только в свою.\nrole — роль приглашённого в квартире (co_owner, tenant, family), по умолчанию family.\nexpires_in_hours: 0 — по умолчанию (7 дней), -1 — без срока действия.",
                 "consumes": [
                     "application/json"
                 ],
@@ -685,25 +790,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "apartment"
                 ],
-                "summary": "Create order",
+                "summary": "Create apartment invitation",
                 "parameters": [
                     {
-                        "description": "Create order request",
+                        "description": "Create invitation request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/http.createOrderRequest"
+                            "$ref": "#/definitions/http.createInvitationRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Успех (No Content)",
+                    "201": {
+                        "description": "Приглашение создано",
                         "schema": {
-                            "$ref": "#/definitions/http.DefaultResponse-string"
+                            "$ref": "#/definitions/http.DefaultResponse-model_CreatedInvitation"
                         }
                     },
                     "400": {
@@ -713,13 +818,19 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Не авторизован / некорректный user_id в токене",
+                        "description": "Неавторизован",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
-                    "422": {
-                        "description": "Невалидный order_type",
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Квартира не найдена",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
@@ -733,14 +844,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/reservation": {
-            "get": {
+        "/apartment/invitations/redeem": {
+            "post": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "JWT": []
                     }
                 ],
-                "description": "Возвращает список бронирований текущего пользователя за период (from-to).",
+                "description": "Привязывает текущего пользователя к квартире по коду приглашения и повышает GUEST до INHABITANT.\nНовая роль попадает в токен после /auth/refresh.",
                 "consumes": [
                     "application/json"
                 ],
@@ -748,36 +859,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reservation"
+                    "apartment"
                 ],
-                "summary": "Get user reservations",
+                "summary": "Redeem apartment invitation",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "2026-01-07",
-                        "description": "Datetime (YYYY-MM-DD)",
-                        "name": "start_date",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "2026-01-07",
-                        "description": "Datetime (YYYY-MM-DD)",
-                        "name": "end_date",
-                        "in": "query",
-                        "required": true
+                        "description": "Redeem request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.redeemInvitationRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Успех",
-                        "schema": {
-                            "$ref": "#/definitions/http.DefaultResponse-array_model_CinemaReservation"
-                        }
+                    "204": {
+                        "description": "Квартира привязана"
                     },
                     "400": {
-                        "description": "Невалидный запрос",
+                        "description": "Невалидный, истёкший или исчерпанный код",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
@@ -788,12 +889,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
-                    "403": {
-                        "description": "Нет доступа",
-                        "schema": {
-                            "$ref": "#/definitions/http.DefaultResponse-error"
-                        }
-                    },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
@@ -801,41 +896,35 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/apartment/invitations/{id}": {
+            "delete": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "JWT": []
                     }
                 ],
-                "description": "Создаёт бронирование на указанный период для текущего пользователя.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Отзывает приглашение, код перестаёт работать.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reservation"
+                    "apartment"
                 ],
-                "summary": "Create reservation",
+                "summary": "Revoke apartment invitation",
                 "parameters": [
                     {
-                        "description": "Create reservation request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http.createReservationRequest"
-                        }
+                        "type": "string",
+                        "description": "ID приглашения",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Успех",
-                        "schema": {
-                            "$ref": "#/definitions/http.DefaultResponse-http_makeReservationResponse"
-                        }
+                    "204": {
+                        "description": "Приглашение отозвано"
                     },
                     "400": {
                         "description": "Невалидный запрос",
@@ -850,7 +939,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Нет доступа",
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Приглашение не найдено",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
@@ -864,36 +959,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/reservation/approve": {
-            "patch": {
+        "/apartment/layout": {
+            "post": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "JWT": []
                     }
                 ],
-                "description": "Одобряет бронирование по reservation_id. Доступно только администратору.",
+                "description": "Создаёт квартиры подъезда по описанию планировки: на каждом этаже от from_floor до to_floor двери от from_door до to_door,\nв exceptions можно задать другие двери для отдельного этажа или пропустить его (skip). Существующие квартиры не меняются,\nповторный запуск ничего не создаёт. В отчёте квартиры разделены на созданные, существующие и лишние (есть в подъезде, но не в планировке).\nС prune=true лишние квартиры удаляются. Квартиры с владельцем или жильцами не удаляются никогда: если такие есть среди лишних,\nони перечислены в bound и ничего не применяется. dry_run=true только возвращает отчёт.\nБлок и подъезд можно не указывать, пока они однозначны. Требуется право apartment.manage (ADMIN, GOD).",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reservation"
+                    "apartment"
                 ],
-                "summary": "Approve reservation",
+                "summary": "Generate apartments from a layout",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "reservation_id",
-                        "name": "reservation_id",
-                        "in": "query",
-                        "required": true
+                        "description": "Планировка",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.layoutRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Одобрено"
+                    "200": {
+                        "description": "Отчёт",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_LayoutReport"
+                        }
                     },
                     "400": {
-                        "description": "Невалидный запрос",
+                        "description": "Невалидная планировка",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
@@ -905,7 +1008,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Только админ может одобрять",
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Блок или подъезд не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "К удаляемой квартире привязаны пользователи",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
@@ -919,67 +1034,175 @@ const docTemplate = `{
                 }
             }
         },
-        "/reservation/free-slots": {
+        "/apartment/memberships": {
             "get": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "JWT": []
                     }
                 ],
-                "description": "Возвращает список свободных временных интервалов в заданном диапазоне.\nСвободный слот — это интервал времени, не пересекающийся ни с одной резервацией.",
+                "description": "Возвращает квартиры текущего пользователя вместе с заявками на вступление и их статусами.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reservation"
+                    "apartment"
                 ],
-                "summary": "Get free time slots",
+                "summary": "List my memberships",
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-array_model_ApartmentMember"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/apartment/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Возвращает квартиру и её подтверждённых жильцов. Требуется право apartment.manage (ADMIN, GOD).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "apartment"
+                ],
+                "summary": "Get apartment with residents",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "2026-01-07",
-                        "description": "Datetime (YYYY-MM-DD)",
-                        "name": "date",
-                        "in": "query",
+                        "description": "ID квартиры",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of free time intervals",
+                        "description": "Успех",
                         "schema": {
-                            "$ref": "#/definitions/http.DefaultResponse-http_getFreeSlotsResponse"
+                            "$ref": "#/definitions/http.DefaultResponse-model_ApartmentDetails"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Невалидный ID",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Квартира не найдена",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     }
                 }
-            }
-        },
-        "/user": {
+            },
             "delete": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "JWT": []
+                    }
+                ],
+                "description": "Удаляет квартиру вместе с заявками на вступление и приглашениями.\nКвартиру с владельцем или жильцами удалить нельзя, сначала отвяжите её.\nТребуется право apartment.manage (ADMIN, GOD).",
+                "tags": [
+                    "apartment"
+                ],
+                "summary": "Delete apartment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID квартиры",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Квартира удалена"
+                    },
+                    "400": {
+                        "description": "Невалидный ID",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Квартира не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "К квартире привязаны пользователи",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "JWT": []
                     }
                 ],
-                "description": "Удаляет пользователя по username. Доступно только ролям ADMIN и GOD. Нельзя удалить самого себя.",
+                "description": "Меняет адрес квартиры (блок, подъезд, этаж, номер двери) и её характеристики: площадь, число комнат,\nкадастровый номер, тип (residential, commercial) и кладовую. Меняются только переданные поля.\nЕсли указан только подъезд, блок остаётся прежним. Пустой кадастровый номер удаляет его.\nИзменение записывается в журнал квартиры.\nТребуется право apartment.manage (ADMIN, GOD).",
                 "consumes": [
                     "application/json"
                 ],
@@ -987,23 +1210,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "apartment"
                 ],
-                "summary": "Delete user (admin/god only)",
+                "summary": "Update apartment",
                 "parameters": [
                     {
-                        "description": "Delete user request",
+                        "type": "string",
+                        "description": "ID квартиры",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые значения",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/http.deleteUserRequest"
+                            "$ref": "#/definitions/http.updateApartmentRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Пользователь удалён"
+                    "200": {
+                        "description": "Квартира обновлена",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_Apartment"
+                        }
                     },
                     "400": {
                         "description": "Невалидный запрос",
@@ -1012,13 +1245,25 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Не авторизован / некорректный токен",
+                        "description": "Неавторизован",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
                     },
                     "403": {
-                        "description": "Недостаточно прав / попытка удалить себя",
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Квартира, блок или подъезд не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "Квартира с таким адресом или кадастровым номером уже существует",
                         "schema": {
                             "$ref": "#/definitions/http.DefaultResponse-error"
                         }
@@ -1031,482 +1276,6335 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "http.DefaultResponse-array_model_ChannelMessage": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.ChannelMessage"
-                    }
-                },
-                "error_message": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                }
-            }
         },
-        "http.DefaultResponse-array_model_CinemaReservation": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.CinemaReservation"
+        "/apartment/{id}/audit": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
                     }
-                },
-                "error_message": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                }
-            }
-        },
-        "http.DefaultResponse-array_model_Order": {
-            "type": "object",
-            "properties": {
+                ],
+                "description": "Возвращает журнал изменений квартиры (изменение, удаление, отвязка), новые записи первыми.\nЖурнал доступен и после удаления квартиры. Требуется право apartment.manage (ADMIN, GOD).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "apartment"
+                ],
+                "summary": "Apartment audit log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID квартиры",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Страница (с 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (до 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_Page-model_ApartmentAudit"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/apartment/{id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Возвращает жильцов квартиры. Владельцы, совладельцы и админы видят также заявки на вступление,\nостальные подтверждённые жильцы — только подтверждённых.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "apartment"
+                ],
+                "summary": "List apartment members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID квартиры",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-array_model_ApartmentMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не состоит в квартире",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Квартира не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Создаёт заявку текущего пользователя на вступление в квартиру с ролью co_owner, tenant или family.\nЗаявку подтверждает владелец или совладелец квартиры (или админ). Отклонённую заявку можно подать снова.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "apartment"
+                ],
+                "summary": "Ask to join an apartment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID квартиры",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Membership request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.membershipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Заявка создана",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_ApartmentMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Квартира не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "Пользователь уже состоит в квартире или заявка уже подана",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/apartment/{id}/members/{user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Удаляет жильца из квартиры или отзывает заявку. Жилец может выйти из квартиры сам,\nостальных удаляют владелец, совладелец или админ. Владельца удалить нельзя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "apartment"
+                ],
+                "summary": "Remove apartment member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID квартиры",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Жилец удалён"
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Квартира или жилец не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "Нельзя удалить владельца",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Меняет роль жильца (co_owner, tenant, family). Роль владельца так не меняется — для этого квартира привязывается заново.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "apartment"
+                ],
+                "summary": "Change member role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID квартиры",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.membershipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Роль изменена",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_ApartmentMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Квартира или жилец не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "Нельзя изменить роль владельца",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/apartment/{id}/members/{user_id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Подтверждает заявку на вступление. role можно не указывать — тогда остаётся роль из заявки.\nПользователь получает квартиру, GUEST повышается до INHABITANT (новая роль попадает в токен после /auth/refresh).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "apartment"
+                ],
+                "summary": "Approve membership request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID квартиры",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Approve request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/http.approveMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Заявка подтверждена",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_ApartmentMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Квартира или заявка не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "Заявка уже рассмотрена",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/apartment/{id}/members/{user_id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Отклоняет заявку на вступление, пользователь получает уведомление.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "apartment"
+                ],
+                "summary": "Reject membership request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID квартиры",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Заявка отклонена"
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Квартира или заявка не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "Заявка уже рассмотрена",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/apartment/{id}/ownership-history": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Возвращает, кто и когда владел квартирой, новые записи первыми. У текущего владельца нет ended_at.\nИстория доступна и после удаления квартиры. Требуется право apartment.manage (ADMIN, GOD).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "apartment"
+                ],
+                "summary": "Apartment ownership history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID квартиры",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-array_model_ApartmentOwnership"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный ID",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/apartment/{id}/unbind": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Отвязывает от квартиры владельца и всех жильцов: очищает владельца квартиры и квартиру у пользователей,\nудаляет членства и отзывает действующие приглашения. Жильцы получают уведомление.\nТребуется право apartment.manage (ADMIN, GOD).",
+                "tags": [
+                    "apartment"
+                ],
+                "summary": "Unbind apartment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID квартиры",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Квартира отвязана"
+                    },
+                    "400": {
+                        "description": "Невалидный ID",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Квартира не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отключает 2FA по текущему коду или резервному коду. Недоступно, если 2FA обязательна для роли.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Disable 2FA",
+                "parameters": [
+                    {
+                        "description": "Code request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.twoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "2FA отключена"
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный код",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "2FA обязательна для роли",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "423": {
+                        "description": "Аккаунт временно заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/enable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Подтверждает подключение 2FA первым кодом из приложения и возвращает резервные коды.\nРезервные коды показываются один раз.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Enable 2FA",
+                "parameters": [
+                    {
+                        "description": "Code request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.twoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Резервные коды",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-array_string"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный код",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "2FA уже включена",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Генерирует TOTP-секрет и otpauth URI. 2FA включается только после /auth/2fa/enable.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Start 2FA enrollment",
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_TotpEnrollment"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "2FA уже включена",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выдаёт новые резервные коды, старые перестают работать.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Regenerate 2FA recovery codes",
+                "parameters": [
+                    {
+                        "description": "Code request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.twoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Резервные коды",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-array_string"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный код",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "423": {
+                        "description": "Аккаунт временно заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/setup": {
+            "post": {
+                "description": "Для входа с two_factor=setup: возвращает секрет и otpauth URI для приложения-аутентификатора.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Start mandatory 2FA enrollment during login",
+                "parameters": [
+                    {
+                        "description": "Setup request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.setupTwoFactorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_TotpEnrollment"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Challenge не найден или истёк",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "2FA уже включена",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/verify": {
+            "post": {
+                "description": "Принимает challenge_token из /auth/login и TOTP-код (или резервный код) и выдаёт токены.\nЕсли вход начался с two_factor=setup, код подтверждает подключение 2FA и в ответе приходят резервные коды.\nНеверные коды учитываются вместе с неверными паролями и могут заблокировать аккаунт.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Finish login with the second factor",
+                "parameters": [
+                    {
+                        "description": "Verify request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.verifyTwoFactorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-http_loginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный код или challenge истёк",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "423": {
+                        "description": "Аккаунт временно заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много попыток входа",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/confirm": {
+            "post": {
+                "description": "Подтверждает пользователя по OTP-коду.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Confirm registration",
+                "parameters": [
+                    {
+                        "description": "Confirm request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.confirmRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Подтверждение прошло успешно"
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Заходит по логину отдавая jwt токен.\nПосле нескольких неудачных попыток вход временно замедляется, а затем аккаунт блокируется на 30 минут.\nЕсли включена двухфакторная аутентификация, вместо токенов возвращаются two_factor и challenge_token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login into account",
+                "parameters": [
+                    {
+                        "description": "Login request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.loginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-http_loginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный логин или пароль",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь отключён администратором",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "423": {
+                        "description": "Аккаунт временно заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много попыток входа",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Завершает текущую сессию: access и refresh токены сессии перестают работать.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Logout",
+                "responses": {
+                    "204": {
+                        "description": "Сессия завершена"
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/providers": {
+            "get": {
+                "description": "Возвращает имена настроенных OpenID Connect провайдеров.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "List external identity providers",
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-array_string"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/callback": {
+            "get": {
+                "description": "Обменивает код авторизации на ID токен, связывает внешний аккаунт с пользователем\n(или создаёт нового с ролью GUEST) и выдаёт токены. Ответ такой же, как у /auth/login, включая шаг 2FA.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "External identity provider callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя провайдера",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код авторизации",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "State из /start",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ошибка от провайдера",
+                        "name": "error",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-http_loginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос или state истёк",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Вход у провайдера не удался",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь отключён администратором",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Провайдер не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/start": {
+            "get": {
+                "description": "Возвращает URL провайдера (authorization code + PKCE), на который нужно отправить пользователя.\nПосле входа провайдер вернёт пользователя на /auth/oidc/{provider}/callback.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Start login via external identity provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя провайдера",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-http_oidcStartResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Провайдер не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/otp/resend": {
+            "post": {
+                "description": "Отправляет новый OTP-код. Повторная отправка возможна не чаще раза в минуту.\npurpose: registration, password_reset, login.\nОтвет одинаковый вне зависимости от того, существует ли пользователь и есть ли что отправлять.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Resend OTP code",
+                "parameters": [
+                    {
+                        "description": "Resend request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.resendOtpRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Код отправлен"
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком частые запросы",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password/forgot": {
+            "post": {
+                "description": "Отправляет одноразовый код для сброса пароля на email или телефон пользователя.\nОтвет одинаковый вне зависимости от того, существует ли пользователь.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Forgot password",
+                "parameters": [
+                    {
+                        "description": "Forgot password request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.forgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Код отправлен"
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком частые запросы",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password/reset": {
+            "post": {
+                "description": "Устанавливает новый пароль по коду из forgot. Все активные сессии пользователя завершаются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Reset password request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.resetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Пароль изменён"
+                    },
+                    "400": {
+                        "description": "Невалидный запрос или неверный код",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много попыток",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Обменивает refresh токен на новую пару токенов. Старый refresh токен становится недействительным.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refresh tokens",
+                "parameters": [
+                    {
+                        "description": "Refresh request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.refreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_TokenPair"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Refresh токен недействителен или сессия отозвана",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Регистрирует нового пользователя и отправляет OTP на указанный username (телефон/почта).\nС кодом приглашения пользователь сразу привязывается к квартире и получает роль INHABITANT.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register new user",
+                "parameters": [
+                    {
+                        "description": "Register request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.registerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешная регистрация",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-string"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/building": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Возвращает блоки жилого комплекса с их подъездами.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "List buildings",
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-array_model_BuildingDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Создаёт блок жилого комплекса. Название блока уникально. Требуется право building.manage (ADMIN, GOD).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Create building",
+                "parameters": [
+                    {
+                        "description": "Блок",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.buildingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Блок создан",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_Building"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "Блок с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/building/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Удаляет блок вместе с подъездами. Блок с квартирами удалить нельзя.\nТребуется право building.manage (ADMIN, GOD).",
+                "tags": [
+                    "building"
+                ],
+                "summary": "Delete building",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID блока",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Блок удалён"
+                    },
+                    "400": {
+                        "description": "Невалидный ID",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Блок не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "В блоке есть квартиры",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Меняет название и адрес блока. Требуется право building.manage (ADMIN, GOD).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Update building",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID блока",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Блок",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.buildingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Блок обновлён",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_Building"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Блок не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "Блок с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/building/{id}/entrances": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Добавляет подъезд в блок. Номер подъезда уникален в пределах блока.\nТребуется право building.manage (ADMIN, GOD).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "building"
+                ],
+                "summary": "Create entrance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID блока",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Подъезд",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.entranceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Подъезд создан",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_Entrance"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Блок не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "Подъезд с таким номером уже есть",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/building/{id}/entrances/{entrance_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Удаляет подъезд без квартир. Требуется право building.manage (ADMIN, GOD).",
+                "tags": [
+                    "building"
+                ],
+                "summary": "Delete entrance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID блока",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID подъезда",
+                        "name": "entrance_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Подъезд удалён"
+                    },
+                    "400": {
+                        "description": "Невалидный ID",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Подъезд не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "В подъезде есть квартиры",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/channel": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns channel messages within the time period. With building_id only announcements\nfor the whole complex and for that building are returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channel"
+                ],
+                "summary": "Get channel messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2025-12-01T00:00:00Z",
+                        "description": "From datetime (RFC3339)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-12-02T00:00:00Z",
+                        "description": "To datetime (RFC3339)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Building ID",
+                        "name": "building_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-array_model_ChannelMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sends a message to the channel. Only ADMIN or GOD can send. Set building_id to address\nonly the residents of one building.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channel"
+                ],
+                "summary": "Send channel message",
+                "parameters": [
+                    {
+                        "description": "Message payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.sendChannelMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/feedback": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns feedbacks list (only ADMIN or GOD)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feedback"
+                ],
+                "summary": "Get feedbacks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "example": "2026-01-01T00:00:00Z",
+                        "description": "Created from (RFC3339)",
+                        "name": "created_at_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "example": "2026-01-02T00:00:00Z",
+                        "description": "Created to (RFC3339)",
+                        "name": "created_at_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text filter",
+                        "name": "text",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Feedback type",
+                        "name": "feedback_type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates feedback message from authorized user",
+                "tags": [
+                    "feedback"
+                ],
+                "summary": "Create feedback",
+                "parameters": [
+                    {
+                        "description": "Feedback payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.createFeedbackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/order": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает список заказов по фильтрам. Требуется JWT. Роль берётся из токена/контекста.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Get orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID (uuid)",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID (uuid)",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order type (string, must match protopb.OrderType enum name)",
+                        "name": "order_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by text",
+                        "name": "text",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-array_model_Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован / некорректный user_id в токене",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт заявку/заказ от имени авторизованного пользователя. OrderType должен существовать в enum protopb.OrderType.\nЗаказ оформляется на квартиру пользователя, поэтому доступен только подтверждённым жильцам квартиры.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Create order",
+                "parameters": [
+                    {
+                        "description": "Create order request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.createOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Успех (No Content)",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-string"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован / некорректный user_id в токене",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не является жильцом квартиры",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "422": {
+                        "description": "Невалидный order_type",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservation": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает список бронирований текущего пользователя за период (from-to).\nДни считаются в таймзоне кинотеатра, время бронирований возвращается в ней же.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "Get user reservations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2026-01-07",
+                        "description": "Datetime (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-01-07",
+                        "description": "Datetime (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-array_model_CinemaReservation"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт бронирование на указанный период для текущего пользователя.\ndate — календарный день в таймзоне кинотеатра.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "Create reservation",
+                "parameters": [
+                    {
+                        "description": "Create reservation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.createReservationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-http_makeReservationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservation/approve": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Одобряет бронирование по reservation_id. Доступно только администратору.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "Approve reservation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "reservation_id",
+                        "name": "reservation_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Одобрено"
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Только админ может одобрять",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservation/free-slots": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает список свободных временных интервалов в заданном диапазоне.\nСвободный слот — это интервал времени, не пересекающийся ни с одной резервацией.\nДата и время слотов указаны в таймзоне кинотеатра (VENUE_TIMEZONE, по умолчанию Asia/Almaty).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "Get free time slots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2026-01-07",
+                        "description": "Datetime (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of free time intervals",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-http_getFreeSlotsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservation/slot-templates": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Возвращает все шаблоны слотов кинотеатра, включая неактивные, по порядку position.\nТребуется право slot.manage (ADMIN, GOD).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "List slot templates",
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-array_model_SlotTemplate"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Создаёт шаблон слота. Время указывается как HH:MM в таймзоне кинотеатра, конец позже начала.\nАктивный шаблон не может пересекаться по времени с другими активными. Слоты по новому шаблону\nпоявляются при следующем запросе дня. Требуется право slot.manage (ADMIN, GOD).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "Create slot template",
+                "parameters": [
+                    {
+                        "description": "Шаблон",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.createSlotTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Шаблон создан",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_SlotTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "Код или позиция заняты, либо время пересекается",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservation/slot-templates/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Удаляет шаблон слота вместе со свободными будущими слотами. Забронированные слоты и брони остаются.\nТребуется право slot.manage (ADMIN, GOD).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "Delete slot template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID шаблона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Шаблон удалён",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_SlotTemplateChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный ID",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Шаблон не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Меняет шаблон слота, меняются только переданные поля. Изменение сразу применяется к ещё не начавшимся слотам:\nсвободные получают новое время и позицию, забронированные сохраняют время брони (их число в reserved_slots).\nСвободные слоты, которые с новым временем пересекаются с бронью того же дня, отключаются (disabled_slots, дни в conflict_dates).\nДеактивация удаляет свободные будущие слоты шаблона, забронированные остаются.\nТребуется право slot.manage (ADMIN, GOD).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "Update slot template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID шаблона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые значения",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.updateSlotTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Шаблон обновлён",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_SlotTemplateChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Шаблон не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "Код или позиция заняты, либо время пересекается",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает пользователей постранично с фильтрами. Пароли не возвращаются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "List users (admin/god only)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Страница (с 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (до 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Роль (GUEST, INHABITANT, ADMIN, GOD)",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Подтверждён ли пользователь",
+                        "name": "is_approved",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Отключён ли пользователь администратором",
+                        "name": "is_disabled",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID квартиры",
+                        "name": "apartment_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID блока",
+                        "name": "building_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Тип username (1 - none, 2 - email, 3 - phone)",
+                        "name": "username_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поиск по username, имени и фамилии",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_Page-http_userResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет пользователя по username. Доступно только ролям ADMIN и GOD. Нельзя удалить самого себя.\nmode=delete (по умолчанию) удаляет пользователя вместе с бронями, заявками, отзывами, постами и сообщениями чатов.\nmode=anonymize оставляет историю, но стирает персональные данные пользователя.\nВ обоих режимах пользователь выходит из чатов, освобождает квартиры и теряет все сессии. В ответе — отчёт об удалённом.\nmessages_pending=true означает, что сообщения чатов ещё не удалены: удаление повторится автоматически.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Delete user (admin/god only)",
+                "parameters": [
+                    {
+                        "description": "Delete user request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.deleteUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отчёт об удалении",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_ErasureReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован / некорректный токен",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав / попытка удалить себя",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/approval": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "is_approved=true подтверждает пользователя и снимает отключение (disabled_at).\nis_approved=false отключает пользователя: все сессии завершаются, войти нельзя, пока администратор\nне подтвердит его снова. Подтверждение кода, сброс пароля и вход через OIDC отключение не снимают.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Approve or disapprove user (admin/god only)",
+                "parameters": [
+                    {
+                        "description": "Change approval request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.changeUserApprovalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Готово"
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав / попытка изменить себя",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/exports/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Статус фоновой выгрузки. Доступен владельцу данных, тому, кто запросил выгрузку, и администраторам.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get export status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID выгрузки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_ExportJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный ID",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Выгрузка не найдена или истекла",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/exports/{id}/download": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Скачивает готовый ZIP фоновой выгрузки. Архив хранится 24 часа.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Download export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID выгрузки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ZIP-архив",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный ID",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Выгрузка не найдена или истекла",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "409": {
+                        "description": "Выгрузка ещё не готова",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/lockouts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает аккаунты, временно заблокированные после множества неудачных попыток входа.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "List locked accounts (admin/god only)",
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-array_model_LoginLockout"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Снимает блокировку входа и сбрасывает счётчик неудачных попыток.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Clear account lockout (admin/god only)",
+                "parameters": [
+                    {
+                        "description": "Clear lockout request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.clearLockoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Блокировка снята"
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает профиль текущего пользователя вместе с привязанной квартирой (этаж/дверь).\nПароль и секреты 2FA не возвращаются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get own profile",
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_Profile"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет имя, фамилию и аватар. Изменяются только переданные поля, пустой avatar_url удаляет аватар.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update own profile",
+                "parameters": [
+                    {
+                        "description": "Update profile request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.updateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_Profile"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает ZIP с JSON-файлами: профиль, привязка к квартире, брони, заявки, отзывы, посты в канале и сообщения чатов.\nЕсли данных много, архив собирается в фоне: возвращается 202 с задачей, статус — /user/exports/{id}, скачивание — download_url.",
+                "produces": [
+                    "application/zip",
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Export own personal data",
+                "responses": {
+                    "200": {
+                        "description": "ZIP-архив",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "202": {
+                        "description": "Архив собирается в фоне",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_ExportJob"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет пароль по текущему паролю. Остальные сессии пользователя завершаются, текущая остаётся.\nНеверный текущий пароль учитывается как неудачная попытка входа.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change own password",
+                "parameters": [
+                    {
+                        "description": "Change password request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.changePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Пароль изменён"
+                    },
+                    "400": {
+                        "description": "Невалидный запрос / неверный текущий пароль",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "423": {
+                        "description": "Аккаунт временно заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много попыток",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает активные сессии пользователя: устройство, IP, user agent и время последней активности.\nСессия, из которой пришёл запрос, помечена current=true.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "List own sessions",
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-array_model_Session"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Завершает все сессии пользователя, кроме текущей.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Revoke all other sessions",
+                "responses": {
+                    "204": {
+                        "description": "Сессии завершены"
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Завершает одну сессию пользователя, например на потерянном устройстве. Её токены перестают работать сразу.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Revoke a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID сессии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Сессия завершена"
+                    },
+                    "400": {
+                        "description": "Невалидный ID",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Сессия не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/username": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отправляет OTP-код на новый email или телефон. Требуется текущий пароль.\nЛогин меняется только после подтверждения кода через /user/me/username/confirm.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Start username change",
+                "parameters": [
+                    {
+                        "description": "Username change request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.startUsernameChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Код отправлен"
+                    },
+                    "400": {
+                        "description": "Невалидный запрос / неверный пароль / логин занят",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "423": {
+                        "description": "Аккаунт временно заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "429": {
+                        "description": "Код уже отправлен недавно",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/username/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Подтверждает новый логин кодом из OTP. Остальные сессии завершаются, на старый адрес уходит уведомление.\nТекущую сессию нужно обновить через /auth/refresh, чтобы токен содержал новый логин.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Confirm username change",
+                "parameters": [
+                    {
+                        "description": "Confirm request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.confirmUsernameChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успех",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_Profile"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный или просроченный код / логин занят",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много попыток",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/role": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет роль пользователя. Назначать или снимать роли ADMIN и GOD может только GOD.\nПри понижении роли все сессии пользователя завершаются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change user role (admin/god only)",
+                "parameters": [
+                    {
+                        "description": "Change role request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.changeUserRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Роль изменена"
+                    },
+                    "400": {
+                        "description": "Невалидный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав / попытка изменить себя",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "То же, что /user/me/export, но для любого пользователя по ID.",
+                "produces": [
+                    "application/zip",
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Export user's personal data (admin/god only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ZIP-архив",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "202": {
+                        "description": "Архив собирается в фоне",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-model_ExportJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный ID",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "401": {
+                        "description": "Не авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/http.DefaultResponse-error"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "http.DefaultResponse-array_model_ApartmentMember": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ApartmentMember"
+                    }
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-array_model_ApartmentOwnership": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ApartmentOwnership"
+                    }
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-array_model_BuildingDetails": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BuildingDetails"
+                    }
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-array_model_ChannelMessage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ChannelMessage"
+                    }
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-array_model_CinemaReservation": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CinemaReservation"
+                    }
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-array_model_Invitation": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Invitation"
+                    }
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-array_model_LoginLockout": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LoginLockout"
+                    }
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-array_model_Order": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Order"
+                    }
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-array_model_Session": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Session"
+                    }
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-array_model_SlotTemplate": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SlotTemplate"
+                    }
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-array_string": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-error": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-http_getFreeSlotsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/http.getFreeSlotsResponse"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-http_loginResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/http.loginResponse"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-http_makeReservationResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/http.makeReservationResponse"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-http_oidcStartResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/http.oidcStartResponse"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_Apartment": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Apartment"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_ApartmentDetails": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.ApartmentDetails"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_ApartmentMember": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.ApartmentMember"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_BindingRequest": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.BindingRequest"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_Building": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Building"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_CreatedInvitation": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.CreatedInvitation"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_Entrance": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Entrance"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_ErasureReport": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.ErasureReport"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_ExportJob": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.ExportJob"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_ImportReport": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.ImportReport"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_LayoutReport": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.LayoutReport"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_Page-http_userResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Page-http_userResponse"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_Page-model_ApartmentAudit": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Page-model_ApartmentAudit"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_Page-model_ApartmentDetails": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Page-model_ApartmentDetails"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_Page-model_BindingRequest": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Page-model_BindingRequest"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_Profile": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Profile"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_SlotTemplate": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.SlotTemplate"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_SlotTemplateChange": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.SlotTemplateChange"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_TokenPair": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.TokenPair"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-model_TotpEnrollment": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.TotpEnrollment"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DefaultResponse-string": {
+            "type": "object",
+            "properties": {
                 "data": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.approveMemberRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "description": "Role overrides the requested role, optional",
+                    "type": "string",
+                    "enum": [
+                        "co_owner",
+                        "tenant",
+                        "family"
+                    ]
+                }
+            }
+        },
+        "http.buildingRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "http.changePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 8
+                }
+            }
+        },
+        "http.changeUserApprovalRequest": {
+            "type": "object",
+            "required": [
+                "is_approved",
+                "username"
+            ],
+            "properties": {
+                "is_approved": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.changeUserRoleRequest": {
+            "type": "object",
+            "required": [
+                "role",
+                "username"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.clearLockoutRequest": {
+            "type": "object",
+            "required": [
+                "username"
+            ],
+            "properties": {
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.confirmRequest": {
+            "type": "object",
+            "required": [
+                "otp_code",
+                "username"
+            ],
+            "properties": {
+                "otp_code": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.confirmUsernameChangeRequest": {
+            "type": "object",
+            "required": [
+                "otp_code"
+            ],
+            "properties": {
+                "otp_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.createApartmentRequest": {
+            "type": "object",
+            "required": [
+                "door_num",
+                "floor"
+            ],
+            "properties": {
+                "building_id": {
+                    "type": "string"
+                },
+                "door_num": {
+                    "type": "integer"
+                },
+                "entrance": {
+                    "type": "integer"
+                },
+                "floor": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.createFeedbackRequest": {
+            "type": "object",
+            "required": [
+                "feedback_type",
+                "message"
+            ],
+            "properties": {
+                "feedback_type": {
+                    "$ref": "#/definitions/protopb.FeedbackType"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.createInvitationRequest": {
+            "type": "object",
+            "required": [
+                "apartment_id"
+            ],
+            "properties": {
+                "apartment_id": {
+                    "type": "string"
+                },
+                "expires_in_hours": {
+                    "type": "integer",
+                    "minimum": -1
+                },
+                "max_uses": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "role": {
+                    "description": "Role of the invited user in the household, family by default",
+                    "type": "string",
+                    "enum": [
+                        "co_owner",
+                        "tenant",
+                        "family"
+                    ]
+                }
+            }
+        },
+        "http.createOrderRequest": {
+            "type": "object",
+            "properties": {
+                "order_type": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.createReservationRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "people_num",
+                "time_slots"
+            ],
+            "properties": {
+                "date": {
+                    "description": "2026-01-17",
+                    "type": "string"
+                },
+                "people_num": {
+                    "type": "integer"
+                },
+                "time_slots": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "http.createSlotTemplateRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "end_time",
+                "position",
+                "start_time"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "description": "IsActive defaults to true",
+                    "type": "boolean"
+                },
+                "position": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.deleteUserRequest": {
+            "type": "object",
+            "required": [
+                "username"
+            ],
+            "properties": {
+                "mode": {
+                    "description": "Mode is \"delete\" (default) or \"anonymize\"",
+                    "enum": [
+                        "delete",
+                        "anonymize"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ErasureMode"
+                        }
+                    ]
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.entranceRequest": {
+            "type": "object",
+            "required": [
+                "number"
+            ],
+            "properties": {
+                "number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.forgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "username"
+            ],
+            "properties": {
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.getFreeSlotsResponse": {
+            "type": "object",
+            "properties": {
+                "free_pairs": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer",
+                            "format": "int32"
+                        }
+                    }
+                },
+                "free_slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DailySlot"
+                    }
+                }
+            }
+        },
+        "http.layoutFloorRequest": {
+            "type": "object",
+            "required": [
+                "floor"
+            ],
+            "properties": {
+                "floor": {
+                    "type": "integer"
+                },
+                "from_door": {
+                    "type": "integer"
+                },
+                "skip": {
+                    "type": "boolean"
+                },
+                "to_door": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.layoutRequest": {
+            "type": "object",
+            "required": [
+                "from_door",
+                "from_floor",
+                "to_door",
+                "to_floor"
+            ],
+            "properties": {
+                "building_id": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "entrance": {
+                    "type": "integer"
+                },
+                "exceptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.layoutFloorRequest"
+                    }
+                },
+                "from_door": {
+                    "type": "integer"
+                },
+                "from_floor": {
+                    "type": "integer"
+                },
+                "prune": {
+                    "type": "boolean"
+                },
+                "to_door": {
+                    "type": "integer"
+                },
+                "to_floor": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.loginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "device": {
+                    "description": "Device is an optional label shown in the session list, e.g. \"Kitchen tablet\"",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.loginResponse": {
+            "type": "object",
+            "properties": {
+                "access_expires_at": {
+                    "type": "string"
+                },
+                "access_token": {
+                    "type": "string"
+                },
+                "challenge_token": {
+                    "type": "string"
+                },
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "refresh_expires_at": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Token duplicates AccessToken for clients that predate refresh tokens",
+                    "type": "string"
+                },
+                "two_factor": {
+                    "description": "TwoFactor is \"verify\" or \"setup\" when the login must be finished via /auth/2fa/verify",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.TwoFactorStep"
+                        }
+                    ]
+                },
+                "user": {
+                    "$ref": "#/definitions/http.userResponse"
+                }
+            }
+        },
+        "http.makeReservationResponse": {
+            "type": "object",
+            "properties": {
+                "free_visit_left": {
+                    "type": "integer"
+                },
+                "is_free": {
+                    "type": "boolean"
+                },
+                "is_success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "http.membershipRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "co_owner",
+                        "tenant",
+                        "family"
+                    ]
+                }
+            }
+        },
+        "http.oidcStartResponse": {
+            "type": "object",
+            "properties": {
+                "authorization_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.redeemInvitationRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.refreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.registerRequest": {
+            "type": "object",
+            "required": [
+                "first_name",
+                "last_name",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "first_name": {
+                    "type": "string"
+                },
+                "invitation_code": {
+                    "description": "InvitationCode optionally binds the new user to an apartment",
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 8
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3
+                }
+            }
+        },
+        "http.rejectBindingRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000
+                }
+            }
+        },
+        "http.resendOtpRequest": {
+            "type": "object",
+            "required": [
+                "purpose",
+                "username"
+            ],
+            "properties": {
+                "purpose": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.resetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "otp_code",
+                "username"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 8
+                },
+                "otp_code": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.sendChannelMessage": {
+            "type": "object",
+            "required": [
+                "message"
+            ],
+            "properties": {
+                "building_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.setupTwoFactorRequest": {
+            "type": "object",
+            "required": [
+                "challenge_token"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.startUsernameChangeRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Username is the new email or phone number in E.164 format",
+                    "type": "string"
+                }
+            }
+        },
+        "http.twoFactorCodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.updateApartmentRequest": {
+            "type": "object",
+            "properties": {
+                "area": {
+                    "type": "number",
+                    "maximum": 10000
+                },
+                "building_id": {
+                    "type": "string"
+                },
+                "cadastral_number": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "door_number": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "entrance": {
+                    "type": "integer"
+                },
+                "floor": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "rooms": {
+                    "type": "integer",
+                    "maximum": 20
+                },
+                "storage_unit": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "type": {
+                    "enum": [
+                        "residential",
+                        "commercial"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ApartmentType"
+                        }
+                    ]
+                }
+            }
+        },
+        "http.updateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "description": "AvatarURL is an http(s) link, an empty string removes the avatar",
+                    "type": "string",
+                    "maxLength": 512
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 1
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 1
+                }
+            }
+        },
+        "http.updateSlotTemplateRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 1
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "position": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.userResponse": {
+            "type": "object",
+            "properties": {
+                "apartment_id": {
+                    "type": "string"
+                },
+                "disabled_at": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_approved": {
+                    "type": "boolean"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "username_type": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.verifyTwoFactorRequest": {
+            "type": "object",
+            "required": [
+                "challenge_token",
+                "code"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "code": {
+                    "description": "Code is a 6-digit TOTP code or a recovery code",
+                    "type": "string"
+                },
+                "device": {
+                    "description": "Device is an optional label shown in the session list",
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "model.Apartment": {
+            "type": "object",
+            "properties": {
+                "area": {
+                    "description": "Area is the total area in m², 0 while unknown",
+                    "type": "number"
+                },
+                "cadastral_number": {
+                    "type": "string"
+                },
+                "door_number": {
+                    "type": "integer"
+                },
+                "entrance_id": {
+                    "description": "EntranceID is empty only for rows created before buildings existed, they are backfilled on start",
+                    "type": "string"
+                },
+                "floor": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "string"
+                },
+                "rooms": {
+                    "type": "integer"
+                },
+                "storage_unit": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/model.ApartmentType"
+                }
+            }
+        },
+        "model.ApartmentAudit": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/model.ApartmentAuditAction"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "apartment_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "details": {
+                    "description": "Details is a human readable description of the change, e.g. \"floor: 3 -\u003e 4\"",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ApartmentAuditAction": {
+            "type": "string",
+            "enum": [
+                "update",
+                "delete",
+                "unbind"
+            ],
+            "x-enum-varnames": [
+                "ApartmentAuditUpdate",
+                "ApartmentAuditDelete",
+                "ApartmentAuditUnbind"
+            ]
+        },
+        "model.ApartmentDetails": {
+            "type": "object",
+            "properties": {
+                "area": {
+                    "description": "Area is the total area in m², 0 while unknown",
+                    "type": "number"
+                },
+                "building": {
+                    "type": "string"
+                },
+                "building_id": {
+                    "type": "string"
+                },
+                "cadastral_number": {
+                    "type": "string"
+                },
+                "door_number": {
+                    "type": "integer"
+                },
+                "entrance": {
+                    "type": "integer"
+                },
+                "entrance_id": {
+                    "description": "EntranceID is empty only for rows created before buildings existed, they are backfilled on start",
+                    "type": "string"
+                },
+                "floor": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "string"
+                },
+                "residents": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Order"
+                        "$ref": "#/definitions/model.ApartmentResident"
+                    }
+                },
+                "rooms": {
+                    "type": "integer"
+                },
+                "storage_unit": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/model.ApartmentType"
+                }
+            }
+        },
+        "model.ApartmentMember": {
+            "type": "object",
+            "properties": {
+                "apartment_id": {
+                    "type": "string"
+                },
+                "approved_by": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/model.MemberRole"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.MemberStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ApartmentOwnership": {
+            "type": "object",
+            "properties": {
+                "apartment_id": {
+                    "type": "string"
+                },
+                "ended_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ApartmentResident": {
+            "type": "object",
+            "properties": {
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/model.MemberRole"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ApartmentType": {
+            "type": "string",
+            "enum": [
+                "residential",
+                "commercial"
+            ],
+            "x-enum-varnames": [
+                "ApartmentTypeResidential",
+                "ApartmentTypeCommercial"
+            ]
+        },
+        "model.BindingRequest": {
+            "type": "object",
+            "properties": {
+                "apartment_id": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "has_proof": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason is why the request was rejected",
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.BindingStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BindingStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "BindingStatusPending",
+                "BindingStatusApproved",
+                "BindingStatusRejected"
+            ]
+        },
+        "model.Building": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BuildingDetails": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entrances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Entrance"
                     }
                 },
-                "error_message": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChannelMessage": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "building_id": {
+                    "description": "BuildingID limits the announcement to one building, nil means the whole complex",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CinemaReservation": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "string"
                 },
-                "status": {
+                "is_approved": {
+                    "type": "boolean"
+                },
+                "people_num": {
+                    "type": "integer"
+                },
+                "phone_num": {
+                    "type": "string"
+                },
+                "slot_type": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "http.DefaultResponse-error": {
+        "model.CreatedInvitation": {
             "type": "object",
             "properties": {
-                "data": {},
-                "error_message": {
+                "apartment_id": {
                     "type": "string"
                 },
-                "status": {
+                "code": {
+                    "description": "Code is returned only once, when the invitation is created",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "expires_at": {
                     "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_uses": {
+                    "type": "integer"
+                },
+                "member_role": {
+                    "description": "MemberRole is the household role the invited user gets, never owner",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.MemberRole"
+                        }
+                    ]
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "used_count": {
+                    "type": "integer"
                 }
             }
         },
-        "http.DefaultResponse-http_getFreeSlotsResponse": {
+        "model.DailySlot": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/http.getFreeSlotsResponse"
+                "end_at": {
+                    "type": "string"
                 },
-                "error_message": {
+                "id": {
+                    "type": "integer"
+                },
+                "is_enabled": {
+                    "type": "boolean"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "slot_date": {
                     "type": "string"
                 },
-                "status": {
+                "start_at": {
                     "type": "string"
+                },
+                "template_id": {
+                    "type": "integer"
                 }
             }
         },
-        "http.DefaultResponse-http_loginResponse": {
+        "model.Entrance": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/http.loginResponse"
+                "building_id": {
+                    "type": "string"
                 },
-                "error_message": {
+                "created_at": {
                     "type": "string"
                 },
-                "status": {
+                "id": {
                     "type": "string"
+                },
+                "number": {
+                    "type": "integer"
                 }
             }
         },
-        "http.DefaultResponse-http_makeReservationResponse": {
+        "model.ErasureMode": {
+            "type": "string",
+            "enum": [
+                "anonymize",
+                "delete"
+            ],
+            "x-enum-varnames": [
+                "ErasureModeAnonymize",
+                "ErasureModeDelete"
+            ]
+        },
+        "model.ErasureReport": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/http.makeReservationResponse"
+                "apartments_released": {
+                    "type": "integer"
                 },
-                "error_message": {
-                    "type": "string"
+                "binding_requests": {
+                    "type": "integer"
                 },
-                "status": {
+                "channel_messages": {
+                    "type": "integer"
+                },
+                "chat_participants": {
+                    "type": "integer"
+                },
+                "feedbacks": {
+                    "type": "integer"
+                },
+                "identities": {
+                    "type": "integer"
+                },
+                "invitations": {
+                    "type": "integer"
+                },
+                "memberships": {
+                    "type": "integer"
+                },
+                "messages": {
+                    "type": "integer"
+                },
+                "messages_pending": {
+                    "description": "MessagesPending means the chat messages could not be deleted yet, the cleanup is retried later",
+                    "type": "boolean"
+                },
+                "mode": {
+                    "$ref": "#/definitions/model.ErasureMode"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "recovery_codes": {
+                    "type": "integer"
+                },
+                "reservations": {
+                    "type": "integer"
+                },
+                "sessions_revoked": {
+                    "type": "boolean"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "http.DefaultResponse-model_Apartment": {
+        "model.ExportJob": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/model.Apartment"
+                "completed_at": {
+                    "type": "string"
                 },
-                "error_message": {
+                "created_at": {
+                    "type": "string"
+                },
+                "download_url": {
+                    "description": "DownloadURL is set once the archive is ready",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "requested_by": {
                     "type": "string"
                 },
                 "status": {
+                    "$ref": "#/definitions/model.ExportStatus"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "http.DefaultResponse-string": {
+        "model.ExportStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "ready",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "ExportStatusPending",
+                "ExportStatusReady",
+                "ExportStatusFailed"
+            ]
+        },
+        "model.ImportAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "invite"
+            ],
+            "x-enum-varnames": [
+                "ImportActionCreate",
+                "ImportActionUpdate",
+                "ImportActionInvite"
+            ]
+        },
+        "model.ImportChange": {
             "type": "object",
             "properties": {
-                "data": {
+                "action": {
+                    "$ref": "#/definitions/model.ImportAction"
+                },
+                "detail": {
                     "type": "string"
                 },
-                "error_message": {
+                "entity": {
                     "type": "string"
                 },
-                "status": {
+                "key": {
                     "type": "string"
+                },
+                "line": {
+                    "type": "integer"
                 }
             }
         },
-        "http.bindApartmentRequest": {
+        "model.ImportCounts": {
             "type": "object",
-            "required": [
-                "apartment_id"
-            ],
             "properties": {
-                "apartment_id": {
-                    "type": "string"
+                "created": {
+                    "type": "integer"
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
                 }
             }
         },
-        "http.confirmRequest": {
+        "model.ImportReport": {
             "type": "object",
-            "required": [
-                "otp_code",
-                "username"
-            ],
             "properties": {
-                "otp_code": {
-                    "type": "string"
+                "apartments": {
+                    "$ref": "#/definitions/model.ImportCounts"
                 },
-                "username": {
-                    "type": "string"
+                "applied": {
+                    "type": "boolean"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ImportChange"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ImportRowError"
+                    }
+                },
+                "invitations_sent": {
+                    "type": "integer"
+                },
+                "memberships": {
+                    "$ref": "#/definitions/model.ImportCounts"
+                },
+                "residents": {
+                    "$ref": "#/definitions/model.ImportCounts"
+                },
+                "rows": {
+                    "type": "integer"
                 }
             }
         },
-        "http.createApartmentRequest": {
+        "model.ImportRowError": {
             "type": "object",
-            "required": [
-                "door_num",
-                "floor"
-            ],
             "properties": {
-                "door_num": {
+                "line": {
                     "type": "integer"
                 },
-                "floor": {
-                    "type": "integer"
+                "message": {
+                    "type": "string"
                 }
             }
         },
-        "http.createFeedbackRequest": {
+        "model.Invitation": {
             "type": "object",
-            "required": [
-                "feedback_type",
-                "message"
-            ],
             "properties": {
-                "feedback_type": {
-                    "$ref": "#/definitions/protopb.FeedbackType"
+                "apartment_id": {
+                    "type": "string"
                 },
-                "message": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_uses": {
+                    "type": "integer"
+                },
+                "member_role": {
+                    "description": "MemberRole is the household role the invited user gets, never owner",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.MemberRole"
+                        }
+                    ]
+                },
+                "revoked_at": {
                     "type": "string"
+                },
+                "used_count": {
+                    "type": "integer"
                 }
             }
         },
-        "http.createOrderRequest": {
+        "model.LayoutApartment": {
             "type": "object",
             "properties": {
-                "order_type": {
-                    "type": "string"
+                "door_number": {
+                    "type": "integer"
                 },
-                "text": {
+                "floor": {
+                    "type": "integer"
+                },
+                "id": {
                     "type": "string"
                 }
             }
         },
-        "http.createReservationRequest": {
+        "model.LayoutReport": {
             "type": "object",
-            "required": [
-                "date",
-                "people_num",
-                "time_slots"
-            ],
             "properties": {
-                "date": {
-                    "description": "2026-01-17",
+                "applied": {
+                    "type": "boolean"
+                },
+                "bound": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LayoutApartment"
+                    }
+                },
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LayoutApartment"
+                    }
+                },
+                "deleted": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LayoutApartment"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "entrance_id": {
                     "type": "string"
                 },
-                "people_num": {
-                    "type": "integer"
+                "existing": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LayoutApartment"
+                    }
                 },
-                "time_slots": {
+                "extra": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/model.LayoutApartment"
                     }
                 }
             }
         },
-        "http.deleteUserRequest": {
+        "model.LoginLockout": {
             "type": "object",
-            "required": [
-                "username"
-            ],
             "properties": {
+                "failures": {
+                    "type": "integer"
+                },
+                "last_ip": {
+                    "type": "string"
+                },
+                "locked_at": {
+                    "type": "string"
+                },
+                "locked_until": {
+                    "type": "string"
+                },
                 "username": {
                     "type": "string"
                 }
             }
         },
-        "http.getFreeSlotsResponse": {
+        "model.MemberRole": {
+            "type": "string",
+            "enum": [
+                "owner",
+                "co_owner",
+                "tenant",
+                "family"
+            ],
+            "x-enum-varnames": [
+                "MemberRoleOwner",
+                "MemberRoleCoOwner",
+                "MemberRoleTenant",
+                "MemberRoleFamily"
+            ]
+        },
+        "model.MemberStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "MemberStatusPending",
+                "MemberStatusApproved",
+                "MemberStatusRejected"
+            ]
+        },
+        "model.Order": {
             "type": "object",
             "properties": {
-                "free_pairs": {
-                    "type": "array",
-                    "items": {
-                        "type": "array",
-                        "items": {
-                            "type": "integer",
-                            "format": "int32"
-                        }
-                    }
+                "apartment_id": {
+                    "description": "ApartmentID is the household the order was placed for",
+                    "type": "string"
                 },
-                "free_slots": {
+                "id": {
+                    "type": "string"
+                },
+                "order_type": {
+                    "$ref": "#/definitions/protopb.OrderType"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Page-http_userResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.DailySlot"
+                        "$ref": "#/definitions/http.userResponse"
                     }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "http.loginRequest": {
+        "model.Page-model_ApartmentAudit": {
             "type": "object",
-            "required": [
-                "password",
-                "username"
-            ],
             "properties": {
-                "password": {
-                    "type": "string"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ApartmentAudit"
+                    }
                 },
-                "username": {
-                    "type": "string"
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "http.loginResponse": {
+        "model.Page-model_ApartmentDetails": {
             "type": "object",
             "properties": {
-                "token": {
-                    "type": "string"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ApartmentDetails"
+                    }
                 },
-                "user": {
-                    "$ref": "#/definitions/model.User"
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "http.makeReservationResponse": {
+        "model.Page-model_BindingRequest": {
             "type": "object",
             "properties": {
-                "free_visit_left": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BindingRequest"
+                    }
+                },
+                "page": {
                     "type": "integer"
                 },
-                "is_free": {
-                    "type": "boolean"
+                "page_size": {
+                    "type": "integer"
                 },
-                "is_success": {
-                    "type": "boolean"
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "http.registerRequest": {
+        "model.Profile": {
             "type": "object",
-            "required": [
-                "first_name",
-                "last_name",
-                "password",
-                "username"
-            ],
             "properties": {
+                "apartment": {
+                    "$ref": "#/definitions/model.ProfileApartment"
+                },
+                "avatar_url": {
+                    "type": "string"
+                },
                 "first_name": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "string"
+                },
+                "is_approved": {
+                    "type": "boolean"
+                },
                 "last_name": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "maxLength": 32,
-                    "minLength": 8
+                "role": {
+                    "type": "string"
+                },
+                "totp_enabled": {
+                    "type": "boolean"
                 },
                 "username": {
-                    "type": "string",
-                    "maxLength": 32,
-                    "minLength": 3
-                }
-            }
-        },
-        "http.sendChannelMessage": {
-            "type": "object",
-            "required": [
-                "message"
-            ],
-            "properties": {
-                "message": {
                     "type": "string"
+                },
+                "username_type": {
+                    "type": "integer"
                 }
             }
         },
-        "model.Apartment": {
+        "model.ProfileApartment": {
             "type": "object",
             "properties": {
+                "building": {
+                    "type": "string"
+                },
+                "can_vote": {
+                    "type": "boolean"
+                },
                 "door_number": {
                     "type": "integer"
                 },
+                "entrance": {
+                    "type": "integer"
+                },
                 "floor": {
                     "type": "integer"
                 },
                 "id": {
                     "type": "string"
                 },
-                "owner_id": {
-                    "type": "string"
+                "is_owner": {
+                    "type": "boolean"
+                },
+                "member_role": {
+                    "description": "MemberRole is empty when the user is not an approved member of the household",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.MemberRole"
+                        }
+                    ]
                 }
             }
         },
-        "model.ChannelMessage": {
+        "model.Session": {
             "type": "object",
             "properties": {
-                "author_id": {
+                "created_at": {
                     "type": "string"
                 },
-                "created_at": {
+                "current": {
+                    "description": "Current marks the session the listing was requested from",
+                    "type": "boolean"
+                },
+                "device": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "text": {
+                "ip": {
                     "type": "string"
                 },
-                "updated_at": {
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "model.CinemaReservation": {
+        "model.SlotTemplate": {
             "type": "object",
             "properties": {
-                "end_time": {
+                "code": {
                     "type": "string"
                 },
-                "id": {
+                "end_time": {
                     "type": "string"
                 },
-                "is_approved": {
-                    "type": "boolean"
-                },
-                "people_num": {
+                "id": {
                     "type": "integer"
                 },
-                "phone_num": {
-                    "type": "string"
+                "is_active": {
+                    "type": "boolean"
                 },
-                "slot_type": {
+                "position": {
                     "type": "integer"
                 },
                 "start_time": {
-                    "type": "string"
-                },
-                "user_id": {
+                    "description": "время дня в таймзоне кинотеатра, \"10:00:00\"",
                     "type": "string"
                 }
             }
         },
-        "model.DailySlot": {
+        "model.SlotTemplateChange": {
             "type": "object",
             "properties": {
-                "end_at": {
-                    "type": "string"
+                "conflict_dates": {
+                    "description": "ConflictDates are the days of DisabledSlots",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "id": {
+                "disabled_slots": {
+                    "description": "DisabledSlots are free slots whose new times overlap a reservation of the day, they are not offered",
                     "type": "integer"
                 },
-                "is_enabled": {
-                    "type": "boolean"
-                },
-                "position": {
+                "removed_slots": {
+                    "description": "RemovedSlots were free slots of a deleted or deactivated template",
                     "type": "integer"
                 },
-                "slot_date": {
-                    "type": "string"
+                "reserved_slots": {
+                    "description": "ReservedSlots are reserved and keep the times they were booked for",
+                    "type": "integer"
                 },
-                "start_at": {
-                    "type": "string"
+                "template": {
+                    "$ref": "#/definitions/model.SlotTemplate"
                 },
-                "template_id": {
+                "updated_slots": {
+                    "description": "UpdatedSlots got the new times or position",
                     "type": "integer"
                 }
             }
         },
-        "model.Order": {
+        "model.TokenPair": {
             "type": "object",
             "properties": {
-                "id": {
+                "access_expires_at": {
                     "type": "string"
                 },
-                "order_type": {
-                    "$ref": "#/definitions/protopb.OrderType"
+                "access_token": {
+                    "type": "string"
                 },
-                "text": {
+                "refresh_expires_at": {
                     "type": "string"
                 },
-                "user_id": {
+                "refresh_token": {
                     "type": "string"
                 }
             }
         },
-        "model.User": {
+        "model.TotpEnrollment": {
             "type": "object",
             "properties": {
-                "apartment_id": {
+                "otpauth_uri": {
                     "type": "string"
                 },
-                "first_name": {
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TwoFactorStep": {
+            "type": "string",
+            "enum": [
+                "verify",
+                "setup"
+            ],
+            "x-enum-varnames": [
+                "TwoFactorStepVerify",
+                "TwoFactorStepSetup"
+            ]
+        },
+        "pkg.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
                     "type": "string"
                 },
-                "id": {
+                "crv": {
                     "type": "string"
                 },
-                "is_approved": {
-                    "type": "boolean",
-                    "default": false
+                "e": {
+                    "type": "string"
                 },
-                "last_name": {
+                "kid": {
                     "type": "string"
                 },
-                "password": {
+                "kty": {
                     "type": "string"
                 },
-                "role_id": {
-                    "$ref": "#/definitions/protopb.Role"
+                "n": {
+                    "type": "string"
                 },
-                "username": {
+                "use": {
                     "type": "string"
                 },
-                "username_type": {
-                    "type": "integer",
-                    "default": 1
+                "x": {
+                    "type": "string"
+                },
+                "y": {
+                    "type": "string"
+                }
+            }
+        },
+        "pkg.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pkg.JWK"
+                    }
                 }
             }
         },
@@ -1547,24 +7645,6 @@ const docTemplate = `{
                 "OrderType_ELECTRICITY",
                 "OrderType_OTHER"
             ]
-        },
-        "protopb.Role": {
-            "type": "integer",
-            "format": "int32",
-            "enum": [
-                0,
-                1,
-                2,
-                3,
-                4
-            ],
-            "x-enum-varnames": [
-                "Role_STATUS_UNSPECIFIED",
-                "Role_GUEST",
-                "Role_INHABITANT",
-                "Role_ADMIN",
-                "Role_GOD"
-            ]
         }
     },
     "securityDefinitions": {
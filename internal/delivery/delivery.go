@@ -6,14 +6,15 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/podpivasniki1488/assyl-backend/internal/delivery/http"
 	"github.com/podpivasniki1488/assyl-backend/internal/service"
+	"github.com/podpivasniki1488/assyl-backend/pkg"
 )
 
 type Delivery struct {
 	Http http.Http
 }
 
-func NewDelivery(logger *slog.Logger, e *echo.Echo, service *service.Service, jwtSecret string) *Delivery {
+func NewDelivery(logger *slog.Logger, e *echo.Echo, service *service.Service, jwtKeys *pkg.JWTKeySet) *Delivery {
 	return &Delivery{
-		Http: http.NewHTTPDelivery(logger, e, service, jwtSecret),
+		Http: http.NewHTTPDelivery(logger, e, service, jwtKeys),
 	}
 }
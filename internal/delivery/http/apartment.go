@@ -12,43 +12,185 @@ func (h *httpDelivery) registerApartmentHandlers(v1 *echo.Group) {
 	apartment := v1.Group("/apartment")
 	apartment.Use(h.registerJWTMiddleware())
 
-	apartment.POST("/create", h.createApartment)
-	apartment.POST("/bind", h.bindApartment, h.getJWTData())
-	apartment.GET("", h.getApartment)
+	apartment.POST("/create", h.createApartment, h.requirePermission(model.PermApartmentCreate))
+	apartment.POST("/import", h.importResidents, h.requirePermission(model.PermApartmentImport))
+	apartment.POST("/layout", h.generateLayout, h.requirePermission(model.PermApartmentManage))
+	apartment.GET("", h.listApartments)
+	apartment.GET("/:id", h.getApartmentDetails, h.requirePermission(model.PermApartmentManage))
+	apartment.PATCH("/:id", h.updateApartment, h.requirePermission(model.PermApartmentManage))
+	apartment.DELETE("/:id", h.deleteApartment, h.requirePermission(model.PermApartmentManage))
+	apartment.POST("/:id/unbind", h.unbindApartment, h.requirePermission(model.PermApartmentManage))
+	apartment.GET("/:id/audit", h.getApartmentAudit, h.requirePermission(model.PermApartmentManage))
+	apartment.GET("/:id/ownership-history", h.getOwnershipHistory, h.requirePermission(model.PermApartmentManage))
+
+	h.registerBindingHandlers(apartment)
+	h.registerInvitationHandlers(apartment)
+	h.registerMemberHandlers(apartment)
 }
 
-// getApartment godoc
+// listApartments godoc
 //
-//	@Summary		Get apartment
-//	@Description	Возвращает квартиру по этажу и номеру двери.
+//	@Summary		List apartments
+//	@Description	Возвращает квартиры постранично, отсортированные по этажу и номеру двери.
+//	@Description	С правом apartment.manage (ADMIN, GOD) доступны все фильтры, а у каждой квартиры есть список жильцов.
+//	@Description	Остальные пользователи могут только найти квартиру по адресу: этаж и номер двери обязательны,
+//	@Description	блок и подъезд уточняют поиск, если в комплексе несколько блоков или подъездов.
 //	@Tags			apartment
 //	@Security		JWT
-//	@Accept			json
 //	@Produce		json
-//	@Param			floor		query		int									true	"Этаж"
-//	@Param			door_number	query		int									true	"Номер двери"
-//	@Success		200			{object}	DefaultResponse[model.Apartment]	"Квартира найдена"
-//	@Failure		400			{object}	DefaultResponse[error]				"Невалидный запрос"
-//	@Failure		401			{object}	DefaultResponse[error]				"Неавторизован"
-//	@Failure		404			{object}	DefaultResponse[error]				"Квартира не найдена"
-//	@Failure		500			{object}	DefaultResponse[error]				"Внутренняя ошибка сервера"
+//	@Param			page		query		int													false	"Страница (с 1)"
+//	@Param			page_size	query		int													false	"Размер страницы (до 100)"
+//	@Param			building_id	query		string												false	"ID блока"
+//	@Param			entrance	query		int													false	"Номер подъезда"
+//	@Param			floor		query		int													false	"Этаж"
+//	@Param			door_number	query		int													false	"Номер двери"
+//	@Param			bound		query		bool												false	"true - только с владельцем, false - только без владельца"
+//	@Param			owner_id	query		string												false	"ID владельца"
+//	@Success		200			{object}	DefaultResponse[model.Page[model.ApartmentDetails]]	"Успех"
+//	@Failure		400			{object}	DefaultResponse[error]								"Невалидный запрос"
+//	@Failure		401			{object}	DefaultResponse[error]								"Неавторизован"
+//	@Failure		403			{object}	DefaultResponse[error]								"Фильтры доступны только администраторам"
+//	@Failure		500			{object}	DefaultResponse[error]								"Внутренняя ошибка сервера"
 //	@Router			/apartment [get]
-func (h *httpDelivery) getApartment(c echo.Context) error {
-	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getApartment")
+func (h *httpDelivery) listApartments(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.listApartments")
 	defer span.End()
 
-	var req getApartmentRequest
+	var req listApartmentsRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 	}
 
-	if req.DoorNumber == nil || req.Floor == nil {
-		return c.JSON(http.StatusBadRequest, ErrorResponse("Missing mandatory field: doorNumber, floor"))
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	isManager := model.HasPermission(p.Role, model.PermApartmentManage)
+
+	if !isManager {
+		if req.DoorNumber == nil || req.Floor == nil {
+			return c.JSON(http.StatusBadRequest, ErrorResponse("Missing mandatory field: doorNumber, floor"))
+		}
+
+		if req.Bound != nil || req.OwnerID != uuid.Nil {
+			return c.JSON(http.StatusForbidden, ErrorResponse(model.ErrPermissionDenied.Error()))
+		}
+	}
+
+	res, err := h.service.Apartment.ListApartments(ctx, model.GetApartmentsRequest{
+		Pagination: model.Pagination{
+			Page:     req.Page,
+			PageSize: req.PageSize,
+		},
+		BuildingID:    req.BuildingID,
+		Entrance:      req.Entrance,
+		Floor:         req.Floor,
+		DoorNumber:    req.DoorNumber,
+		Bound:         req.Bound,
+		OwnerID:       req.OwnerID,
+		WithResidents: isManager,
+	})
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.Page[model.ApartmentDetails]]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+// getApartmentDetails godoc
+//
+//	@Summary		Get apartment with residents
+//	@Description	Возвращает квартиру и её подтверждённых жильцов. Требуется право apartment.manage (ADMIN, GOD).
+//	@Tags			apartment
+//	@Security		JWT
+//	@Produce		json
+//	@Param			id	path		string									true	"ID квартиры"
+//	@Success		200	{object}	DefaultResponse[model.ApartmentDetails]	"Успех"
+//	@Failure		400	{object}	DefaultResponse[error]					"Невалидный ID"
+//	@Failure		401	{object}	DefaultResponse[error]					"Неавторизован"
+//	@Failure		403	{object}	DefaultResponse[error]					"Недостаточно прав"
+//	@Failure		404	{object}	DefaultResponse[error]					"Квартира не найдена"
+//	@Failure		500	{object}	DefaultResponse[error]					"Внутренняя ошибка сервера"
+//	@Router			/apartment/{id} [get]
+func (h *httpDelivery) getApartmentDetails(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getApartmentDetails")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid apartment id"))
+	}
+
+	res, err := h.service.Apartment.GetApartmentDetails(ctx, id)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.ApartmentDetails]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+// updateApartment godoc
+//
+//	@Summary		Update apartment
+//	@Description	Меняет адрес квартиры (блок, подъезд, этаж, номер двери) и её характеристики: площадь, число комнат,
+//	@Description	кадастровый номер, тип (residential, commercial) и кладовую. Меняются только переданные поля.
+//	@Description	Если указан только подъезд, блок остаётся прежним. Пустой кадастровый номер удаляет его.
+//	@Description	Изменение записывается в журнал квартиры.
+//	@Description	Требуется право apartment.manage (ADMIN, GOD).
+//	@Tags			apartment
+//	@Security		JWT
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"ID квартиры"
+//	@Param			request	body		updateApartmentRequest				true	"Новые значения"
+//	@Success		200		{object}	DefaultResponse[model.Apartment]	"Квартира обновлена"
+//	@Failure		400		{object}	DefaultResponse[error]				"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]				"Неавторизован"
+//	@Failure		403		{object}	DefaultResponse[error]				"Недостаточно прав"
+//	@Failure		404		{object}	DefaultResponse[error]				"Квартира, блок или подъезд не найдены"
+//	@Failure		409		{object}	DefaultResponse[error]				"Квартира с таким адресом или кадастровым номером уже существует"
+//	@Failure		500		{object}	DefaultResponse[error]				"Внутренняя ошибка сервера"
+//	@Router			/apartment/{id} [patch]
+func (h *httpDelivery) updateApartment(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.updateApartment")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid apartment id"))
+	}
+
+	var req updateApartmentRequest
+	if err = c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 	}
 
-	res, err := h.service.Apartment.GetApartment(ctx, model.Apartment{
-		DoorNumber: *req.DoorNumber,
-		Floor:      *req.Floor,
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Apartment.UpdateApartment(ctx, p.UserID, id, model.UpdateApartmentRequest{
+		BuildingID:      req.BuildingID,
+		Entrance:        req.Entrance,
+		Floor:           req.Floor,
+		DoorNumber:      req.DoorNumber,
+		Area:            req.Area,
+		Rooms:           req.Rooms,
+		CadastralNumber: req.CadastralNumber,
+		Type:            req.Type,
+		StorageUnit:     req.StorageUnit,
 	})
 	if err != nil {
 		return h.handleErrResponse(c, err)
@@ -60,10 +202,166 @@ func (h *httpDelivery) getApartment(c echo.Context) error {
 	})
 }
 
+// deleteApartment godoc
+//
+//	@Summary		Delete apartment
+//	@Description	Удаляет квартиру вместе с заявками на вступление и приглашениями.
+//	@Description	Квартиру с владельцем или жильцами удалить нельзя, сначала отвяжите её.
+//	@Description	Требуется право apartment.manage (ADMIN, GOD).
+//	@Tags			apartment
+//	@Security		JWT
+//	@Param			id	path	string	true	"ID квартиры"
+//	@Success		204	"Квартира удалена"
+//	@Failure		400	{object}	DefaultResponse[error]	"Невалидный ID"
+//	@Failure		401	{object}	DefaultResponse[error]	"Неавторизован"
+//	@Failure		403	{object}	DefaultResponse[error]	"Недостаточно прав"
+//	@Failure		404	{object}	DefaultResponse[error]	"Квартира не найдена"
+//	@Failure		409	{object}	DefaultResponse[error]	"К квартире привязаны пользователи"
+//	@Failure		500	{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/apartment/{id} [delete]
+func (h *httpDelivery) deleteApartment(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.deleteApartment")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid apartment id"))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	if err = h.service.Apartment.DeleteApartment(ctx, p.UserID, id); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// unbindApartment godoc
+//
+//	@Summary		Unbind apartment
+//	@Description	Отвязывает от квартиры владельца и всех жильцов: очищает владельца квартиры и квартиру у пользователей,
+//	@Description	удаляет членства и отзывает действующие приглашения. Жильцы получают уведомление.
+//	@Description	Требуется право apartment.manage (ADMIN, GOD).
+//	@Tags			apartment
+//	@Security		JWT
+//	@Param			id	path	string	true	"ID квартиры"
+//	@Success		204	"Квартира отвязана"
+//	@Failure		400	{object}	DefaultResponse[error]	"Невалидный ID"
+//	@Failure		401	{object}	DefaultResponse[error]	"Неавторизован"
+//	@Failure		403	{object}	DefaultResponse[error]	"Недостаточно прав"
+//	@Failure		404	{object}	DefaultResponse[error]	"Квартира не найдена"
+//	@Failure		500	{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/apartment/{id}/unbind [post]
+func (h *httpDelivery) unbindApartment(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.unbindApartment")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid apartment id"))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	if err = h.service.Apartment.UnbindApartment(ctx, p.UserID, id); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// getApartmentAudit godoc
+//
+//	@Summary		Apartment audit log
+//	@Description	Возвращает журнал изменений квартиры (изменение, удаление, отвязка), новые записи первыми.
+//	@Description	Журнал доступен и после удаления квартиры. Требуется право apartment.manage (ADMIN, GOD).
+//	@Tags			apartment
+//	@Security		JWT
+//	@Produce		json
+//	@Param			id			path		string												true	"ID квартиры"
+//	@Param			page		query		int													false	"Страница (с 1)"
+//	@Param			page_size	query		int													false	"Размер страницы (до 100)"
+//	@Success		200			{object}	DefaultResponse[model.Page[model.ApartmentAudit]]	"Успех"
+//	@Failure		400			{object}	DefaultResponse[error]								"Невалидный запрос"
+//	@Failure		401			{object}	DefaultResponse[error]								"Неавторизован"
+//	@Failure		403			{object}	DefaultResponse[error]								"Недостаточно прав"
+//	@Failure		500			{object}	DefaultResponse[error]								"Внутренняя ошибка сервера"
+//	@Router			/apartment/{id}/audit [get]
+func (h *httpDelivery) getApartmentAudit(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getApartmentAudit")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid apartment id"))
+	}
+
+	var req pageRequest
+	if err = c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	res, err := h.service.Apartment.GetApartmentAudit(ctx, id, model.Pagination{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	})
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.Page[model.ApartmentAudit]]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+// getOwnershipHistory godoc
+//
+//	@Summary		Apartment ownership history
+//	@Description	Возвращает, кто и когда владел квартирой, новые записи первыми. У текущего владельца нет ended_at.
+//	@Description	История доступна и после удаления квартиры. Требуется право apartment.manage (ADMIN, GOD).
+//	@Tags			apartment
+//	@Security		JWT
+//	@Produce		json
+//	@Param			id	path		string										true	"ID квартиры"
+//	@Success		200	{object}	DefaultResponse[[]model.ApartmentOwnership]	"Успех"
+//	@Failure		400	{object}	DefaultResponse[error]						"Невалидный ID"
+//	@Failure		401	{object}	DefaultResponse[error]						"Неавторизован"
+//	@Failure		403	{object}	DefaultResponse[error]						"Недостаточно прав"
+//	@Failure		500	{object}	DefaultResponse[error]						"Внутренняя ошибка сервера"
+//	@Router			/apartment/{id}/ownership-history [get]
+func (h *httpDelivery) getOwnershipHistory(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getOwnershipHistory")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid apartment id"))
+	}
+
+	res, err := h.service.Apartment.GetOwnershipHistory(ctx, id)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[[]model.ApartmentOwnership]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
 // createApartment godoc
 //
 //	@Summary		Create apartment
-//	@Description	Создаёт новую квартиру.
+//	@Description	Создаёт новую квартиру в подъезде блока. building_id и entrance можно не указывать,
+//	@Description	пока в комплексе один блок и один подъезд. Требуется право apartment.create (ADMIN, GOD).
 //	@Tags			apartment
 //	@Security		JWT
 //	@Accept			json
@@ -72,6 +370,8 @@ func (h *httpDelivery) getApartment(c echo.Context) error {
 //	@Success		201		{object}	DefaultResponse[string]	"Квартира успешно создана"
 //	@Failure		400		{object}	DefaultResponse[error]	"Невалидный запрос"
 //	@Failure		401		{object}	DefaultResponse[error]	"Неавторизован"
+//	@Failure		403		{object}	DefaultResponse[error]	"Недостаточно прав"
+//	@Failure		404		{object}	DefaultResponse[error]	"Блок или подъезд не найден"
 //	@Failure		409		{object}	DefaultResponse[error]	"Квартира уже существует (конфликт)"
 //	@Failure		500		{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
 //	@Router			/apartment/create [post]
@@ -88,7 +388,9 @@ func (h *httpDelivery) createApartment(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 	}
 
-	if err := h.service.Apartment.CreateApartment(ctx, model.Apartment{
+	if err := h.service.Apartment.CreateApartment(ctx, model.ApartmentAddress{
+		BuildingID: req.BuildingID,
+		Entrance:   req.Entrance,
 		Floor:      req.Floor,
 		DoorNumber: req.DoorNum,
 	}); err != nil {
@@ -99,59 +401,36 @@ func (h *httpDelivery) createApartment(c echo.Context) error {
 }
 
 type createApartmentRequest struct {
-	Floor   uint8  `json:"floor" validate:"required"`
-	DoorNum uint16 `json:"door_num" validate:"required"`
+	BuildingID uuid.UUID `json:"building_id"`
+	Entrance   uint8     `json:"entrance"`
+	Floor      uint8     `json:"floor" validate:"required"`
+	DoorNum    uint16    `json:"door_num" validate:"required"`
 }
 
-type getApartmentRequest struct {
-	Floor      *uint8  `query:"floor"`
-	DoorNumber *uint16 `query:"door_number"`
+type listApartmentsRequest struct {
+	Page       int       `query:"page"`
+	PageSize   int       `query:"page_size"`
+	BuildingID uuid.UUID `query:"building_id"`
+	Entrance   uint8     `query:"entrance"`
+	Floor      *uint8    `query:"floor"`
+	DoorNumber *uint16   `query:"door_number"`
+	Bound      *bool     `query:"bound"`
+	OwnerID    uuid.UUID `query:"owner_id"`
 }
 
-// bindApartment godoc
-//
-//	@Summary		Bind apartment to user
-//	@Description	Привязывает квартиру к текущему авторизованному пользователю.
-//	@Tags			apartment
-//	@Security		JWT
-//	@Accept			json
-//	@Produce		json
-//	@Param			request	body		bindApartmentRequest	true	"Bind apartment request"
-//	@Success		200		{object}	DefaultResponse[string]	"Квартира успешно привязана к пользователю"
-//	@Failure		400		{object}	DefaultResponse[error]	"Невалидный запрос (невалидный JSON или тело)"
-//	@Failure		401		{object}	DefaultResponse[error]	"Неавторизован (отсутствует или неверный JWT)"
-//	@Failure		404		{object}	DefaultResponse[error]	"Квартира не найдена"
-//	@Failure		409		{object}	DefaultResponse[error]	"Квартира уже привязана к пользователю"
-//	@Failure		500		{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
-//	@Router			/apartment/bind [post]
-func (h *httpDelivery) bindApartment(c echo.Context) error {
-	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery_bindApartment")
-	defer span.End()
-
-	var req bindApartmentRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
-	}
-
-	if err := validate.Struct(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
-	}
-
-	username, ok := c.Get("username").(string)
-	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("username not found in context"))
-	}
-
-	if err := h.service.UserManagement.BindApartmentToUser(ctx, username, req.ApartmentId); err != nil {
-		return h.handleErrResponse(c, err)
-	}
-
-	return c.JSON(http.StatusOK, DefaultResponse[string]{
-		Status: "ok",
-		Data:   "success",
-	})
+type updateApartmentRequest struct {
+	BuildingID      uuid.UUID            `json:"building_id"`
+	Entrance        uint8                `json:"entrance"`
+	Floor           *uint8               `json:"floor" validate:"omitempty,min=1"`
+	DoorNumber      *uint16              `json:"door_number" validate:"omitempty,min=1"`
+	Area            *float64             `json:"area" validate:"omitempty,gt=0,lte=10000"`
+	Rooms           *uint8               `json:"rooms" validate:"omitempty,max=20"`
+	CadastralNumber *string              `json:"cadastral_number" validate:"omitempty,max=64"`
+	Type            *model.ApartmentType `json:"type" validate:"omitempty,oneof=residential commercial"`
+	StorageUnit     *string              `json:"storage_unit" validate:"omitempty,max=32"`
 }
 
-type bindApartmentRequest struct {
-	ApartmentId uuid.UUID `json:"apartment_id" validate:"required,uuid4"`
+type pageRequest struct {
+	Page     int `query:"page"`
+	PageSize int `query:"page_size"`
 }
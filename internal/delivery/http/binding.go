@@ -0,0 +1,372 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+const bindingProofMaxSize = 5 << 20
+
+func (h *httpDelivery) registerBindingHandlers(apartment *echo.Group) {
+	apartment.POST("/bind", h.bindApartment)
+
+	requests := apartment.Group("/bind-requests")
+
+	requests.GET("", h.getBindingRequests, h.requirePermission(model.PermBindingReview))
+	requests.GET("/my", h.getMyBindingRequests)
+	requests.GET("/:id/proof", h.getBindingProof)
+	requests.POST("/:id/approve", h.approveBindingRequest, h.requirePermission(model.PermBindingReview))
+	requests.POST("/:id/reject", h.rejectBindingRequest, h.requirePermission(model.PermBindingReview))
+}
+
+// bindApartment godoc
+//
+//	@Summary		Request apartment binding
+//	@Description	Создаёт заявку текущего пользователя на владение квартирой. К заявке можно приложить документ,
+//	@Description	подтверждающий право собственности (PDF, JPEG или PNG до 5 МБ). Без документа можно отправить те же поля в JSON.
+//	@Description	Владельцем пользователь станет после одобрения заявки администратором.
+//	@Description	С правом apartment.bind_any (ADMIN, GOD) квартира без владельца привязывается сразу, заявка сохраняется одобренной,
+//	@Description	остальные ожидающие заявки на неё отклоняются. Квартиру с владельцем привязать нельзя никому.
+//	@Description	Чтобы вступить в чужую квартиру, подайте заявку через /apartment/{id}/members.
+//	@Tags			apartment
+//	@Security		JWT
+//	@Accept			json
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			apartment_id	formData	string									true	"ID квартиры"
+//	@Param			comment			formData	string									false	"Комментарий для администратора"
+//	@Param			proof			formData	file									false	"Подтверждающий документ"
+//	@Success		201				{object}	DefaultResponse[model.BindingRequest]	"Заявка создана"
+//	@Failure		400				{object}	DefaultResponse[error]					"Невалидный запрос или документ"
+//	@Failure		401				{object}	DefaultResponse[error]					"Неавторизован (отсутствует или неверный JWT)"
+//	@Failure		404				{object}	DefaultResponse[error]					"Квартира не найдена"
+//	@Failure		409				{object}	DefaultResponse[error]					"Квартира уже привязана или заявка уже подана"
+//	@Failure		500				{object}	DefaultResponse[error]					"Внутренняя ошибка сервера"
+//	@Router			/apartment/bind [post]
+func (h *httpDelivery) bindApartment(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery_bindApartment")
+	defer span.End()
+
+	var req bindApartmentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	proof, err := readBindingProof(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	res, err := h.service.Binding.RequestBinding(ctx, p.UserID, p.Role, model.CreateBindingRequest{
+		ApartmentID: req.ApartmentId,
+		Comment:     req.Comment,
+		Proof:       proof,
+	})
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, DefaultResponse[model.BindingRequest]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+// getBindingRequests godoc
+//
+//	@Summary		Binding request queue
+//	@Description	Возвращает заявки на привязку квартир, старые первыми. Для очереди на рассмотрение передайте status=pending.
+//	@Description	Требуется право apartment.bind_review (ADMIN, GOD).
+//	@Tags			apartment
+//	@Security		JWT
+//	@Produce		json
+//	@Param			page			query		int													false	"Страница (с 1)"
+//	@Param			page_size		query		int													false	"Размер страницы (до 100)"
+//	@Param			status			query		string												false	"Статус (pending, approved, rejected)"
+//	@Param			apartment_id	query		string												false	"ID квартиры"
+//	@Param			user_id			query		string												false	"ID пользователя"
+//	@Success		200				{object}	DefaultResponse[model.Page[model.BindingRequest]]	"Успех"
+//	@Failure		400				{object}	DefaultResponse[error]								"Невалидный запрос"
+//	@Failure		401				{object}	DefaultResponse[error]								"Неавторизован"
+//	@Failure		403				{object}	DefaultResponse[error]								"Недостаточно прав"
+//	@Failure		500				{object}	DefaultResponse[error]								"Внутренняя ошибка сервера"
+//	@Router			/apartment/bind-requests [get]
+func (h *httpDelivery) getBindingRequests(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getBindingRequests")
+	defer span.End()
+
+	var req listBindingRequestsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	status := model.BindingStatus(req.Status)
+	if status != "" && !status.IsValid() {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid status"))
+	}
+
+	res, err := h.service.Binding.ListBindingRequests(ctx, model.GetBindingRequestsRequest{
+		Pagination: model.Pagination{
+			Page:     req.Page,
+			PageSize: req.PageSize,
+		},
+		Status:      status,
+		ApartmentID: req.ApartmentID,
+		UserID:      req.UserID,
+	})
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.Page[model.BindingRequest]]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+// getMyBindingRequests godoc
+//
+//	@Summary		My binding requests
+//	@Description	Возвращает заявки текущего пользователя на привязку квартир с их статусом и причиной отказа.
+//	@Tags			apartment
+//	@Security		JWT
+//	@Produce		json
+//	@Param			page		query		int													false	"Страница (с 1)"
+//	@Param			page_size	query		int													false	"Размер страницы (до 100)"
+//	@Success		200			{object}	DefaultResponse[model.Page[model.BindingRequest]]	"Успех"
+//	@Failure		400			{object}	DefaultResponse[error]								"Невалидный запрос"
+//	@Failure		401			{object}	DefaultResponse[error]								"Неавторизован"
+//	@Failure		500			{object}	DefaultResponse[error]								"Внутренняя ошибка сервера"
+//	@Router			/apartment/bind-requests/my [get]
+func (h *httpDelivery) getMyBindingRequests(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getMyBindingRequests")
+	defer span.End()
+
+	var req pageRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Binding.ListBindingRequests(ctx, model.GetBindingRequestsRequest{
+		Pagination: model.Pagination{
+			Page:     req.Page,
+			PageSize: req.PageSize,
+		},
+		UserID: p.UserID,
+	})
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.Page[model.BindingRequest]]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+// getBindingProof godoc
+//
+//	@Summary		Download binding proof
+//	@Description	Скачивает документ, приложенный к заявке. Доступно автору заявки и пользователям с правом apartment.bind_review.
+//	@Tags			apartment
+//	@Security		JWT
+//	@Produce		application/pdf
+//	@Produce		image/jpeg
+//	@Produce		image/png
+//	@Produce		json
+//	@Param			id	path		string					true	"ID заявки"
+//	@Success		200	{file}		file					"Документ"
+//	@Failure		400	{object}	DefaultResponse[error]	"Невалидный ID"
+//	@Failure		401	{object}	DefaultResponse[error]	"Неавторизован"
+//	@Failure		404	{object}	DefaultResponse[error]	"Заявка или документ не найдены"
+//	@Failure		500	{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/apartment/bind-requests/{id}/proof [get]
+func (h *httpDelivery) getBindingProof(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getBindingProof")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid binding request id"))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	doc, err := h.service.Binding.GetBindingProof(ctx, p.UserID, p.Role, id)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	c.Response().Header().Set(
+		echo.HeaderContentDisposition,
+		mime.FormatMediaType("attachment", map[string]string{"filename": doc.FileName}),
+	)
+
+	return c.Blob(http.StatusOK, doc.ContentType, doc.Data)
+}
+
+// approveBindingRequest godoc
+//
+//	@Summary		Approve binding request
+//	@Description	Одобряет заявку: пользователь становится владельцем квартиры, гость получает роль INHABITANT.
+//	@Description	Остальные ожидающие заявки на эту квартиру отклоняются. Пользователи получают уведомления.
+//	@Description	Требуется право apartment.bind_review (ADMIN, GOD).
+//	@Tags			apartment
+//	@Security		JWT
+//	@Produce		json
+//	@Param			id	path		string									true	"ID заявки"
+//	@Success		200	{object}	DefaultResponse[model.BindingRequest]	"Заявка одобрена"
+//	@Failure		400	{object}	DefaultResponse[error]					"Невалидный ID"
+//	@Failure		401	{object}	DefaultResponse[error]					"Неавторизован"
+//	@Failure		403	{object}	DefaultResponse[error]					"Недостаточно прав"
+//	@Failure		404	{object}	DefaultResponse[error]					"Заявка не найдена"
+//	@Failure		409	{object}	DefaultResponse[error]					"Заявка уже рассмотрена или у квартиры уже есть владелец"
+//	@Failure		500	{object}	DefaultResponse[error]					"Внутренняя ошибка сервера"
+//	@Router			/apartment/bind-requests/{id}/approve [post]
+func (h *httpDelivery) approveBindingRequest(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.approveBindingRequest")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid binding request id"))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Binding.ApproveBindingRequest(ctx, p.UserID, id)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.BindingRequest]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+// rejectBindingRequest godoc
+//
+//	@Summary		Reject binding request
+//	@Description	Отклоняет заявку с указанием причины, пользователь получает уведомление с этой причиной.
+//	@Description	Требуется право apartment.bind_review (ADMIN, GOD).
+//	@Tags			apartment
+//	@Security		JWT
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string									true	"ID заявки"
+//	@Param			request	body		rejectBindingRequest					true	"Причина отказа"
+//	@Success		200		{object}	DefaultResponse[model.BindingRequest]	"Заявка отклонена"
+//	@Failure		400		{object}	DefaultResponse[error]					"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]					"Неавторизован"
+//	@Failure		403		{object}	DefaultResponse[error]					"Недостаточно прав"
+//	@Failure		404		{object}	DefaultResponse[error]					"Заявка не найдена"
+//	@Failure		409		{object}	DefaultResponse[error]					"Заявка уже рассмотрена"
+//	@Failure		500		{object}	DefaultResponse[error]					"Внутренняя ошибка сервера"
+//	@Router			/apartment/bind-requests/{id}/reject [post]
+func (h *httpDelivery) rejectBindingRequest(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.rejectBindingRequest")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid binding request id"))
+	}
+
+	var req rejectBindingRequest
+	if err = c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Binding.RejectBindingRequest(ctx, p.UserID, id, req.Reason)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.BindingRequest]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+// readBindingProof returns the optional proof file of a multipart request, nil if there is none.
+func readBindingProof(c echo.Context) (*model.BindingDocument, error) {
+	fh, err := c.FormFile("proof")
+	if err != nil {
+		// JSON requests and forms without the file
+		return nil, nil
+	}
+
+	if fh.Size > bindingProofMaxSize {
+		return nil, errors.New("proof is larger than 5 MB")
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, bindingProofMaxSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.BindingDocument{
+		FileName: filepath.Base(fh.Filename),
+		Data:     data,
+	}, nil
+}
+
+type bindApartmentRequest struct {
+	ApartmentId uuid.UUID `json:"apartment_id" form:"apartment_id" validate:"required"`
+	Comment     string    `json:"comment" form:"comment" validate:"max=1000"`
+}
+
+type listBindingRequestsRequest struct {
+	Page        int       `query:"page"`
+	PageSize    int       `query:"page_size"`
+	Status      string    `query:"status"`
+	ApartmentID uuid.UUID `query:"apartment_id"`
+	UserID      uuid.UUID `query:"user_id"`
+}
+
+type rejectBindingRequest struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
@@ -0,0 +1,267 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+func (h *httpDelivery) registerBuildingHandlers(v1 *echo.Group) {
+	building := v1.Group("/building")
+	building.Use(h.registerJWTMiddleware())
+
+	building.GET("", h.listBuildings)
+	building.POST("", h.createBuilding, h.requirePermission(model.PermBuildingManage))
+	building.PATCH("/:id", h.updateBuilding, h.requirePermission(model.PermBuildingManage))
+	building.DELETE("/:id", h.deleteBuilding, h.requirePermission(model.PermBuildingManage))
+	building.POST("/:id/entrances", h.createEntrance, h.requirePermission(model.PermBuildingManage))
+	building.DELETE("/:id/entrances/:entrance_id", h.deleteEntrance, h.requirePermission(model.PermBuildingManage))
+}
+
+// listBuildings godoc
+//
+//	@Summary		List buildings
+//	@Description	Возвращает блоки жилого комплекса с их подъездами.
+//	@Tags			building
+//	@Security		JWT
+//	@Produce		json
+//	@Success		200	{object}	DefaultResponse[[]model.BuildingDetails]	"Успех"
+//	@Failure		401	{object}	DefaultResponse[error]						"Неавторизован"
+//	@Failure		500	{object}	DefaultResponse[error]						"Внутренняя ошибка сервера"
+//	@Router			/building [get]
+func (h *httpDelivery) listBuildings(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.listBuildings")
+	defer span.End()
+
+	res, err := h.service.Building.ListBuildings(ctx)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[[]model.BuildingDetails]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+// createBuilding godoc
+//
+//	@Summary		Create building
+//	@Description	Создаёт блок жилого комплекса. Название блока уникально. Требуется право building.manage (ADMIN, GOD).
+//	@Tags			building
+//	@Security		JWT
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		buildingRequest					true	"Блок"
+//	@Success		201		{object}	DefaultResponse[model.Building]	"Блок создан"
+//	@Failure		400		{object}	DefaultResponse[error]			"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]			"Неавторизован"
+//	@Failure		403		{object}	DefaultResponse[error]			"Недостаточно прав"
+//	@Failure		409		{object}	DefaultResponse[error]			"Блок с таким названием уже существует"
+//	@Failure		500		{object}	DefaultResponse[error]			"Внутренняя ошибка сервера"
+//	@Router			/building [post]
+func (h *httpDelivery) createBuilding(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.createBuilding")
+	defer span.End()
+
+	var req buildingRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	res, err := h.service.Building.CreateBuilding(ctx, model.Building{
+		Name:    req.Name,
+		Address: req.Address,
+	})
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, DefaultResponse[model.Building]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+// updateBuilding godoc
+//
+//	@Summary		Update building
+//	@Description	Меняет название и адрес блока. Требуется право building.manage (ADMIN, GOD).
+//	@Tags			building
+//	@Security		JWT
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string							true	"ID блока"
+//	@Param			request	body		buildingRequest					true	"Блок"
+//	@Success		200		{object}	DefaultResponse[model.Building]	"Блок обновлён"
+//	@Failure		400		{object}	DefaultResponse[error]			"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]			"Неавторизован"
+//	@Failure		403		{object}	DefaultResponse[error]			"Недостаточно прав"
+//	@Failure		404		{object}	DefaultResponse[error]			"Блок не найден"
+//	@Failure		409		{object}	DefaultResponse[error]			"Блок с таким названием уже существует"
+//	@Failure		500		{object}	DefaultResponse[error]			"Внутренняя ошибка сервера"
+//	@Router			/building/{id} [patch]
+func (h *httpDelivery) updateBuilding(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.updateBuilding")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid building id"))
+	}
+
+	var req buildingRequest
+	if err = c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	res, err := h.service.Building.UpdateBuilding(ctx, model.Building{
+		ID:      id,
+		Name:    req.Name,
+		Address: req.Address,
+	})
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.Building]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+// deleteBuilding godoc
+//
+//	@Summary		Delete building
+//	@Description	Удаляет блок вместе с подъездами. Блок с квартирами удалить нельзя.
+//	@Description	Требуется право building.manage (ADMIN, GOD).
+//	@Tags			building
+//	@Security		JWT
+//	@Param			id	path	string	true	"ID блока"
+//	@Success		204	"Блок удалён"
+//	@Failure		400	{object}	DefaultResponse[error]	"Невалидный ID"
+//	@Failure		401	{object}	DefaultResponse[error]	"Неавторизован"
+//	@Failure		403	{object}	DefaultResponse[error]	"Недостаточно прав"
+//	@Failure		404	{object}	DefaultResponse[error]	"Блок не найден"
+//	@Failure		409	{object}	DefaultResponse[error]	"В блоке есть квартиры"
+//	@Failure		500	{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/building/{id} [delete]
+func (h *httpDelivery) deleteBuilding(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.deleteBuilding")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid building id"))
+	}
+
+	if err = h.service.Building.DeleteBuilding(ctx, id); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// createEntrance godoc
+//
+//	@Summary		Create entrance
+//	@Description	Добавляет подъезд в блок. Номер подъезда уникален в пределах блока.
+//	@Description	Требуется право building.manage (ADMIN, GOD).
+//	@Tags			building
+//	@Security		JWT
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string							true	"ID блока"
+//	@Param			request	body		entranceRequest					true	"Подъезд"
+//	@Success		201		{object}	DefaultResponse[model.Entrance]	"Подъезд создан"
+//	@Failure		400		{object}	DefaultResponse[error]			"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]			"Неавторизован"
+//	@Failure		403		{object}	DefaultResponse[error]			"Недостаточно прав"
+//	@Failure		404		{object}	DefaultResponse[error]			"Блок не найден"
+//	@Failure		409		{object}	DefaultResponse[error]			"Подъезд с таким номером уже есть"
+//	@Failure		500		{object}	DefaultResponse[error]			"Внутренняя ошибка сервера"
+//	@Router			/building/{id}/entrances [post]
+func (h *httpDelivery) createEntrance(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.createEntrance")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid building id"))
+	}
+
+	var req entranceRequest
+	if err = c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	res, err := h.service.Building.CreateEntrance(ctx, id, req.Number)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, DefaultResponse[model.Entrance]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+// deleteEntrance godoc
+//
+//	@Summary		Delete entrance
+//	@Description	Удаляет подъезд без квартир. Требуется право building.manage (ADMIN, GOD).
+//	@Tags			building
+//	@Security		JWT
+//	@Param			id			path	string	true	"ID блока"
+//	@Param			entrance_id	path	string	true	"ID подъезда"
+//	@Success		204			"Подъезд удалён"
+//	@Failure		400			{object}	DefaultResponse[error]	"Невалидный ID"
+//	@Failure		401			{object}	DefaultResponse[error]	"Неавторизован"
+//	@Failure		403			{object}	DefaultResponse[error]	"Недостаточно прав"
+//	@Failure		404			{object}	DefaultResponse[error]	"Подъезд не найден"
+//	@Failure		409			{object}	DefaultResponse[error]	"В подъезде есть квартиры"
+//	@Failure		500			{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/building/{id}/entrances/{entrance_id} [delete]
+func (h *httpDelivery) deleteEntrance(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.deleteEntrance")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid building id"))
+	}
+
+	entranceID, err := uuid.Parse(c.Param("entrance_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid entrance id"))
+	}
+
+	if err = h.service.Building.DeleteEntrance(ctx, id, entranceID); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+type buildingRequest struct {
+	Name    string `json:"name" validate:"required,max=64"`
+	Address string `json:"address" validate:"max=256"`
+}
+
+type entranceRequest struct {
+	Number uint8 `json:"number" validate:"required"`
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
-	"github.com/podpivasniki1488/assyl-backend/protopb"
 )
 
 func (h *httpDelivery) registerChannelHandlers(v1 *echo.Group) {
@@ -15,23 +14,25 @@ func (h *httpDelivery) registerChannelHandlers(v1 *echo.Group) {
 
 	channel.Use(h.registerJWTMiddleware())
 	channel.GET("", h.getChannelMessages)
-	channel.POST("", h.sendMessage, h.getJWTData())
+	channel.POST("", h.sendMessage, h.requirePermission(model.PermChannelPost))
 }
 
 // getChannelMessages godoc
 //
 //	@Summary		Get channel messages
-//	@Description	Returns channel messages within the time period.
+//	@Description	Returns channel messages within the time period. With building_id only announcements
+//	@Description	for the whole complex and for that building are returned.
 //	@Tags			channel
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Param			from	query		string	true	"From datetime (RFC3339)"	example(2025-12-01T00:00:00Z)
-//	@Param			to		query		string	true	"To datetime (RFC3339)"		example(2025-12-02T00:00:00Z)
-//	@Success		200		{object}	DefaultResponse[[]model.ChannelMessage]
-//	@Failure		400		{object}	DefaultResponse[error]
-//	@Failure		401		{object}	DefaultResponse[error]
-//	@Failure		500		{object}	DefaultResponse[error]
+//	@Param			from		query		string	true	"From datetime (RFC3339)"	example(2025-12-01T00:00:00Z)
+//	@Param			to			query		string	true	"To datetime (RFC3339)"		example(2025-12-02T00:00:00Z)
+//	@Param			building_id	query		string	false	"Building ID"
+//	@Success		200			{object}	DefaultResponse[[]model.ChannelMessage]
+//	@Failure		400			{object}	DefaultResponse[error]
+//	@Failure		401			{object}	DefaultResponse[error]
+//	@Failure		500			{object}	DefaultResponse[error]
 //	@Router			/channel [get]
 func (h *httpDelivery) getChannelMessages(c echo.Context) error {
 	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getChannel")
@@ -46,7 +47,7 @@ func (h *httpDelivery) getChannelMessages(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 	}
 
-	res, err := h.service.Channel.GetByTimePeriod(ctx, req.From, req.To)
+	res, err := h.service.Channel.GetByTimePeriod(ctx, req.From, req.To, req.BuildingID)
 	if err != nil {
 		return h.handleErrResponse(c, err)
 	}
@@ -60,7 +61,8 @@ func (h *httpDelivery) getChannelMessages(c echo.Context) error {
 // sendMessage godoc
 //
 //	@Summary		Send channel message
-//	@Description	Sends a message to the channel. Only ADMIN or GOD can send.
+//	@Description	Sends a message to the channel. Only ADMIN or GOD can send. Set building_id to address
+//	@Description	only the residents of one building.
 //	@Tags			channel
 //	@Accept			json
 //	@Produce		json
@@ -70,6 +72,7 @@ func (h *httpDelivery) getChannelMessages(c echo.Context) error {
 //	@Failure		400		{object}	DefaultResponse[error]
 //	@Failure		401		{object}	DefaultResponse[error]
 //	@Failure		403		{object}	DefaultResponse[error]
+//	@Failure		404		{object}	DefaultResponse[error]
 //	@Failure		500		{object}	DefaultResponse[error]
 //	@Router			/channel [post]
 func (h *httpDelivery) sendMessage(c echo.Context) error {
@@ -85,30 +88,17 @@ func (h *httpDelivery) sendMessage(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 	}
 
-	userId, ok := c.Get("user_id").(string)
+	p, ok := getPrincipal(c)
 	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("failed to get user_id from context"))
-	}
-
-	parsed, err := uuid.Parse(userId)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, ErrorResponse("invalid user id"))
-	}
-
-	role, ok := c.Get("role").(string)
-	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("failed to get role from context"))
-	}
-
-	if protopb.Role_ADMIN.String() != role && protopb.Role_GOD.String() != role {
-		return c.JSON(http.StatusForbidden, ErrorResponse("only admins and gods can send messages"))
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
 	}
 
-	if err = h.service.Channel.SendChannelMessage(ctx, model.ChannelMessage{
-		AuthorId:  parsed,
-		Text:      req.Message,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	if err := h.service.Channel.SendChannelMessage(ctx, model.ChannelMessage{
+		AuthorId:   p.UserID,
+		Text:       req.Message,
+		BuildingID: req.BuildingID,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
 	}); err != nil {
 		return h.handleErrResponse(c, err)
 	}
@@ -117,10 +107,12 @@ func (h *httpDelivery) sendMessage(c echo.Context) error {
 }
 
 type sendChannelMessage struct {
-	Message string `json:"message" validate:"required"`
+	Message    string     `json:"message" validate:"required"`
+	BuildingID *uuid.UUID `json:"building_id,omitempty"`
 }
 
 type getChannelMessages struct {
-	From time.Time `query:"from" validate:"required"`
-	To   time.Time `query:"to" validate:"required,gtfield=From"`
+	From       time.Time `query:"from" validate:"required"`
+	To         time.Time `query:"to" validate:"required,gtfield=From"`
+	BuildingID uuid.UUID `query:"building_id"`
 }
@@ -29,39 +29,34 @@ func (h *httpDelivery) registerChatHandlers(v1 *echo.Group) {
 	chat := v1.Group("/chat")
 	chat.Use(h.registerJWTMiddleware())
 
-	chat.POST("/send", h.sendMsgToChat, h.getJWTData())
-	chat.POST("/start", h.startChat, h.getJWTData())
+	chat.POST("/send", h.sendMsgToChat)
+	chat.POST("/start", h.startChat)
 
 	ws := v1.Group("/ws")
 	ws.Use(h.registerJWTMiddleware())
 
-	ws.GET("/last-message", h.getUserLastMessages, h.getJWTData()) // listen for user-last messages (must be websocket)
+	ws.GET("/last-message", h.getUserLastMessages) // listen for user-last messages (must be websocket)
 }
 
 func (h *httpDelivery) startChat(c echo.Context) error {
 	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.startChat")
 	defer span.End()
 
-	userId, ok := c.Get("user_id").(string)
+	p, ok := getPrincipal(c)
 	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("user id not found in context"))
-	}
-
-	parsed, err := uuid.Parse(userId)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, ErrorResponse("invalid user id"))
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
 	}
 
 	var req startChatRequest
-	if err = c.Bind(&req); err != nil {
+	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 	}
 
-	if err = validate.Struct(&req); err != nil {
+	if err := validate.Struct(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 	}
 
-	res, err := h.service.Chat.StartNewChat(ctx, parsed, req.Members)
+	res, err := h.service.Chat.StartNewChat(ctx, p.UserID, req.Members)
 	if err != nil {
 		return h.handleErrResponse(c, err)
 	}
@@ -80,26 +75,21 @@ func (h *httpDelivery) sendMsgToChat(c echo.Context) error {
 	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.sendMsgToChat")
 	defer span.End()
 
-	userId, ok := c.Get("user_id").(string)
+	p, ok := getPrincipal(c)
 	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("role not found in context"))
-	}
-
-	parsed, err := uuid.Parse(userId)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, ErrorResponse("invalid user id"))
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
 	}
 
 	var req sendMsgToChatRequest
-	if err = c.Bind(&req); err != nil {
+	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 	}
 
-	if err = validate.Struct(&req); err != nil {
+	if err := validate.Struct(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 	}
 
-	if err = h.service.Chat.SendMessageToChat(ctx, req.Message, req.ChatId, parsed); err != nil {
+	if err := h.service.Chat.SendMessageToChat(ctx, req.Message, req.ChatId, p.UserID); err != nil {
 		return h.handleErrResponse(c, err)
 	}
 
@@ -118,14 +108,9 @@ func (h *httpDelivery) getUserLastMessages(c echo.Context) error {
 	_, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getUserLastMessages")
 	defer span.End()
 
-	userId, ok := c.Get("user_id").(string)
+	p, ok := getPrincipal(c)
 	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("userId not found in context"))
-	}
-
-	parsed, err := uuid.Parse(userId)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, ErrorResponse("invalid user id"))
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
 	}
 
 	conn, err := Upgrader.Upgrade(c.Response(), c.Request(), nil)
@@ -135,7 +120,7 @@ func (h *httpDelivery) getUserLastMessages(c echo.Context) error {
 	}
 	defer conn.Close()
 
-	subCh, unsub := h.service.Chat.SubscribeToUpdates(parsed)
+	subCh, unsub := h.service.Chat.SubscribeToUpdates(p.UserID)
 	defer unsub()
 
 	done := make(chan struct{})
@@ -0,0 +1,191 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+func (h *httpDelivery) registerExportHandlers(user *echo.Group) {
+	user.GET("/me/export", h.exportOwnData)
+	user.GET("/:id/export", h.exportUserData, h.requirePermission(model.PermUserExport))
+	user.GET("/exports/:id", h.getExportJob)
+	user.GET("/exports/:id/download", h.downloadExport)
+}
+
+// exportOwnData godoc
+//
+//	@Summary		Export own personal data
+//	@Description	Возвращает ZIP с JSON-файлами: профиль, привязка к квартире, брони, заявки, отзывы, посты в канале и сообщения чатов.
+//	@Description	Если данных много, архив собирается в фоне: возвращается 202 с задачей, статус — /user/exports/{id}, скачивание — download_url.
+//	@Tags			user
+//	@Produce		application/zip
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{file}		file								"ZIP-архив"
+//	@Success		202	{object}	DefaultResponse[model.ExportJob]	"Архив собирается в фоне"
+//	@Failure		401	{object}	DefaultResponse[error]				"Не авторизован"
+//	@Failure		500	{object}	DefaultResponse[error]				"Внутренняя ошибка сервера"
+//	@Router			/user/me/export [get]
+func (h *httpDelivery) exportOwnData(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.exportOwnData")
+	defer span.End()
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Export.ExportUserData(ctx, p.UserID, p.UserID)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return h.exportResponse(c, p.UserID, res)
+}
+
+// exportUserData godoc
+//
+//	@Summary		Export user's personal data (admin/god only)
+//	@Description	То же, что /user/me/export, но для любого пользователя по ID.
+//	@Tags			user
+//	@Produce		application/zip
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string								true	"ID пользователя"
+//	@Success		200	{file}		file								"ZIP-архив"
+//	@Success		202	{object}	DefaultResponse[model.ExportJob]	"Архив собирается в фоне"
+//	@Failure		400	{object}	DefaultResponse[error]				"Невалидный ID"
+//	@Failure		401	{object}	DefaultResponse[error]				"Не авторизован"
+//	@Failure		403	{object}	DefaultResponse[error]				"Недостаточно прав"
+//	@Failure		404	{object}	DefaultResponse[error]				"Пользователь не найден"
+//	@Failure		500	{object}	DefaultResponse[error]				"Внутренняя ошибка сервера"
+//	@Router			/user/{id}/export [get]
+func (h *httpDelivery) exportUserData(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.exportUserData")
+	defer span.End()
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Export.ExportUserData(ctx, p.UserID, userID)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return h.exportResponse(c, userID, res)
+}
+
+// getExportJob godoc
+//
+//	@Summary		Get export status
+//	@Description	Статус фоновой выгрузки. Доступен владельцу данных, тому, кто запросил выгрузку, и администраторам.
+//	@Tags			user
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string								true	"ID выгрузки"
+//	@Success		200	{object}	DefaultResponse[model.ExportJob]	"Успех"
+//	@Failure		400	{object}	DefaultResponse[error]				"Невалидный ID"
+//	@Failure		401	{object}	DefaultResponse[error]				"Не авторизован"
+//	@Failure		404	{object}	DefaultResponse[error]				"Выгрузка не найдена или истекла"
+//	@Failure		500	{object}	DefaultResponse[error]				"Внутренняя ошибка сервера"
+//	@Router			/user/exports/{id} [get]
+func (h *httpDelivery) getExportJob(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getExportJob")
+	defer span.End()
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	job, err := h.service.Export.GetExportJob(ctx, p.UserID, p.Role, jobID)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[*model.ExportJob]{
+		Status: "ok",
+		Data:   withDownloadURL(job),
+	})
+}
+
+// downloadExport godoc
+//
+//	@Summary		Download export
+//	@Description	Скачивает готовый ZIP фоновой выгрузки. Архив хранится 24 часа.
+//	@Tags			user
+//	@Produce		application/zip
+//	@Security		BearerAuth
+//	@Param			id	path		string					true	"ID выгрузки"
+//	@Success		200	{file}		file					"ZIP-архив"
+//	@Failure		400	{object}	DefaultResponse[error]	"Невалидный ID"
+//	@Failure		401	{object}	DefaultResponse[error]	"Не авторизован"
+//	@Failure		404	{object}	DefaultResponse[error]	"Выгрузка не найдена или истекла"
+//	@Failure		409	{object}	DefaultResponse[error]	"Выгрузка ещё не готова"
+//	@Failure		500	{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/user/exports/{id}/download [get]
+func (h *httpDelivery) downloadExport(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.downloadExport")
+	defer span.End()
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	job, archive, err := h.service.Export.DownloadExport(ctx, p.UserID, p.Role, jobID)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return zipAttachment(c, job.UserID, archive)
+}
+
+func (h *httpDelivery) exportResponse(c echo.Context, userID uuid.UUID, res *model.ExportResult) error {
+	if res.Job != nil {
+		return c.JSON(http.StatusAccepted, DefaultResponse[*model.ExportJob]{
+			Status: "ok",
+			Data:   withDownloadURL(res.Job),
+		})
+	}
+
+	return zipAttachment(c, userID, res.Archive)
+}
+
+func zipAttachment(c echo.Context, userID uuid.UUID, archive []byte) error {
+	c.Response().Header().Set(
+		echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="assyl-export-%s.zip"`, userID),
+	)
+
+	return c.Blob(http.StatusOK, "application/zip", archive)
+}
+
+func withDownloadURL(job *model.ExportJob) *model.ExportJob {
+	if job.Status == model.ExportStatusReady {
+		job.DownloadURL = fmt.Sprintf("/v1/user/exports/%s/download", job.ID)
+	}
+
+	return job
+}
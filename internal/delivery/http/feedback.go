@@ -4,7 +4,6 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
 	"github.com/podpivasniki1488/assyl-backend/protopb"
@@ -14,8 +13,8 @@ func (h *httpDelivery) registerFeedbackHandlers(v1 *echo.Group) {
 	feedback := v1.Group("/feedback")
 
 	feedback.Use(h.registerJWTMiddleware())
-	feedback.POST("", h.createFeedback, h.getJWTData())
-	feedback.GET("", h.getFeedbacks, h.getJWTData())
+	feedback.POST("", h.createFeedback)
+	feedback.GET("", h.getFeedbacks, h.requirePermission(model.PermFeedbackView))
 }
 
 // createFeedback godoc
@@ -39,18 +38,13 @@ func (h *httpDelivery) createFeedback(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 	}
 
-	userId, ok := c.Get("user_id").(string)
+	p, ok := getPrincipal(c)
 	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("failed to get user_id from context"))
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
 	}
 
-	parsed, err := uuid.Parse(userId)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, ErrorResponse("invalid user id"))
-	}
-
-	if err = h.service.Feedback.CreateFeedback(ctx, model.Feedback{
-		UserId:       parsed,
+	if err := h.service.Feedback.CreateFeedback(ctx, model.Feedback{
+		UserId:       p.UserID,
 		Text:         req.Message,
 		FeedbackType: req.FeedbackType,
 	}); err != nil {
@@ -91,23 +85,9 @@ func (h *httpDelivery) getFeedbacks(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 	}
 
-	role, ok := c.Get("role").(string)
+	p, ok := getPrincipal(c)
 	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("failed to get role from context"))
-	}
-
-	if protopb.Role_ADMIN.String() != role && protopb.Role_GOD.String() != role {
-		return c.JSON(http.StatusForbidden, ErrorResponse("only admins and gods can send messages"))
-	}
-
-	userId, ok := c.Get("user_id").(string)
-	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("failed to get user_id from context"))
-	}
-
-	parsed, err := uuid.Parse(userId)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, ErrorResponse("invalid user id"))
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
 	}
 
 	txt := ""
@@ -121,7 +101,7 @@ func (h *httpDelivery) getFeedbacks(c echo.Context) error {
 	}
 
 	res, err := h.service.Feedback.GetFeedbacks(ctx, model.GetFeedbackRequest{
-		UserID:        parsed,
+		UserID:        p.UserID,
 		CreatedAtFrom: req.CreatedAtFrom,
 		CreatedAtTo:   req.CreatedAtTo,
 		Text:          txt,
@@ -9,26 +9,27 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	_ "github.com/podpivasniki1488/assyl-backend/docs"
 	"github.com/podpivasniki1488/assyl-backend/internal/service"
+	"github.com/podpivasniki1488/assyl-backend/pkg"
 	echoSwagger "github.com/swaggo/echo-swagger"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type httpDelivery struct {
-	echoApp   *echo.Echo
-	logger    *slog.Logger
-	service   *service.Service
-	tracer    trace.Tracer
-	jwtSecret string
+	echoApp *echo.Echo
+	logger  *slog.Logger
+	service *service.Service
+	tracer  trace.Tracer
+	jwtKeys *pkg.JWTKeySet
 }
 
-func NewHTTPDelivery(logger *slog.Logger, e *echo.Echo, s *service.Service, jwtSecret string) Http {
+func NewHTTPDelivery(logger *slog.Logger, e *echo.Echo, s *service.Service, jwtKeys *pkg.JWTKeySet) Http {
 	return &httpDelivery{
-		echoApp:   e,
-		logger:    logger,
-		service:   s,
-		tracer:    otel.Tracer("httpDelivery"),
-		jwtSecret: jwtSecret,
+		echoApp: e,
+		logger:  logger,
+		service: s,
+		tracer:  otel.Tracer("httpDelivery"),
+		jwtKeys: jwtKeys,
 	}
 }
 
@@ -54,11 +55,13 @@ func (h *httpDelivery) Start(port string) {
 func (h *httpDelivery) registerV1Handler(v1 *echo.Group) {
 	h.registerAuthHandlers(v1)
 	h.registerApartmentHandlers(v1)
+	h.registerBuildingHandlers(v1)
 	h.registerReservationHandlers(v1)
 	h.registerChannelHandlers(v1)
 	h.registerFeedbackHandlers(v1)
 	h.registerOrderHandlers(v1)
 	h.registerUserHandlers(v1)
+	h.registerWellKnownHandlers(v1)
 }
 
 func (h *httpDelivery) registerV1WSHandler(v1 *echo.Group) {
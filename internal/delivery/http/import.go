@@ -0,0 +1,92 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+const importMaxFileSize = 5 << 20
+
+// importResidents godoc
+//
+//	@Summary		Import apartments and residents
+//	@Description	Загружает таблицу CSV или XLSX с колонками floor, door (обязательные) и building/block, entrance, first_name, last_name,
+//	@Description	phone/email, role (необязательные). Блок и подъезд должны уже существовать, их можно не указывать, пока они однозначны.
+//	@Description	Квартиры и жильцы создаются или обновляются, повторный импорт ничего не меняет.
+//	@Description	Новые жильцы создаются без пароля и задают его через «Забыли пароль»; с send_invitations=true им приходит сообщение об этом.
+//	@Description	dry_run=true только возвращает список изменений. Если в файле есть ошибки, ничего не применяется.
+//	@Description	Импорт не меняет владельцев: строка owner для квартиры с другим владельцем и строка текущего владельца с другой ролью —
+//	@Description	ошибки. Жилец может быть указан только в одной квартире.
+//	@Description	Требуется право apartment.import (ADMIN, GOD).
+//	@Tags			apartment
+//	@Security		JWT
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			file				formData	file								true	"Файл .csv или .xlsx"
+//	@Param			dry_run				formData	bool								false	"Только показать изменения"
+//	@Param			send_invitations	formData	bool								false	"Отправить приглашения новым жильцам"
+//	@Success		200					{object}	DefaultResponse[model.ImportReport]	"Отчёт об импорте"
+//	@Failure		400					{object}	DefaultResponse[error]				"Невалидный файл"
+//	@Failure		401					{object}	DefaultResponse[error]				"Неавторизован"
+//	@Failure		403					{object}	DefaultResponse[error]				"Недостаточно прав"
+//	@Failure		500					{object}	DefaultResponse[error]				"Внутренняя ошибка сервера"
+//	@Router			/apartment/import [post]
+func (h *httpDelivery) importResidents(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.importResidents")
+	defer span.End()
+
+	var req importRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("file is required"))
+	}
+
+	if fh.Size > importMaxFileSize {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("file is larger than 5 MB"))
+	}
+
+	format := model.ImportFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(fh.Filename)), "."))
+
+	f, err := fh.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, importMaxFileSize))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Import.ImportResidents(ctx, p.UserID, format, data, model.ImportOptions{
+		DryRun:          req.DryRun,
+		SendInvitations: req.SendInvitations,
+	})
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.ImportReport]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+type importRequest struct {
+	DryRun          bool `form:"dry_run"`
+	SendInvitations bool `form:"send_invitations"`
+}
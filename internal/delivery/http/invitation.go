@@ -0,0 +1,222 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+const defaultInvitationTTLHours = 7 * 24
+
+func (h *httpDelivery) registerInvitationHandlers(apartment *echo.Group) {
+	invitations := apartment.Group("/invitations")
+
+	invitations.POST("", h.createInvitation)
+	invitations.GET("", h.getInvitations)
+	invitations.DELETE("/:id", h.revokeInvitation)
+	invitations.POST("/redeem", h.redeemInvitation)
+}
+
+// createInvitation godoc
+//
+//	@Summary		Create apartment invitation
+//	@Description	Создаёт код приглашения в квартиру. Код показывается один раз.
+//	@Description	Админы могут приглашать в любую квартиру, владельцы и совладельцы — только в свою.
+//	@Description	role — роль приглашённого в квартире (co_owner, tenant, family), по умолчанию family.
+//	@Description	expires_in_hours: 0 — по умолчанию (7 дней), -1 — без срока действия.
+//	@Tags			apartment
+//	@Security		JWT
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		createInvitationRequest						true	"Create invitation request"
+//	@Success		201		{object}	DefaultResponse[model.CreatedInvitation]	"Приглашение создано"
+//	@Failure		400		{object}	DefaultResponse[error]						"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]						"Неавторизован"
+//	@Failure		403		{object}	DefaultResponse[error]						"Недостаточно прав"
+//	@Failure		404		{object}	DefaultResponse[error]						"Квартира не найдена"
+//	@Failure		500		{object}	DefaultResponse[error]						"Внутренняя ошибка сервера"
+//	@Router			/apartment/invitations [post]
+func (h *httpDelivery) createInvitation(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.createInvitation")
+	defer span.End()
+
+	var req createInvitationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	if req.MaxUses == 0 {
+		req.MaxUses = 1
+	}
+
+	var ttl time.Duration
+	switch {
+	case req.ExpiresInHours == 0:
+		ttl = defaultInvitationTTLHours * time.Hour
+	case req.ExpiresInHours > 0:
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+
+	res, err := h.service.Invitation.CreateInvitation(ctx, p.UserID, p.Role, model.CreateInvitationRequest{
+		ApartmentID: req.ApartmentID,
+		MemberRole:  model.MemberRole(req.Role),
+		MaxUses:     req.MaxUses,
+		TTL:         ttl,
+	})
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, DefaultResponse[model.CreatedInvitation]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+// getInvitations godoc
+//
+//	@Summary		List apartment invitations
+//	@Description	Возвращает приглашения квартиры (без самих кодов).
+//	@Tags			apartment
+//	@Security		JWT
+//	@Produce		json
+//	@Param			apartment_id	query		string								true	"ID квартиры"
+//	@Success		200				{object}	DefaultResponse[[]model.Invitation]	"Успех"
+//	@Failure		400				{object}	DefaultResponse[error]				"Невалидный запрос"
+//	@Failure		401				{object}	DefaultResponse[error]				"Неавторизован"
+//	@Failure		403				{object}	DefaultResponse[error]				"Недостаточно прав"
+//	@Failure		404				{object}	DefaultResponse[error]				"Квартира не найдена"
+//	@Failure		500				{object}	DefaultResponse[error]				"Внутренняя ошибка сервера"
+//	@Router			/apartment/invitations [get]
+func (h *httpDelivery) getInvitations(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getInvitations")
+	defer span.End()
+
+	var req getInvitationsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Invitation.GetInvitations(ctx, p.UserID, p.Role, req.ApartmentID)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[[]model.Invitation]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+// revokeInvitation godoc
+//
+//	@Summary		Revoke apartment invitation
+//	@Description	Отзывает приглашение, код перестаёт работать.
+//	@Tags			apartment
+//	@Security		JWT
+//	@Produce		json
+//	@Param			id	path	string	true	"ID приглашения"
+//	@Success		204	"Приглашение отозвано"
+//	@Failure		400	{object}	DefaultResponse[error]	"Невалидный запрос"
+//	@Failure		401	{object}	DefaultResponse[error]	"Неавторизован"
+//	@Failure		403	{object}	DefaultResponse[error]	"Недостаточно прав"
+//	@Failure		404	{object}	DefaultResponse[error]	"Приглашение не найдено"
+//	@Failure		500	{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/apartment/invitations/{id} [delete]
+func (h *httpDelivery) revokeInvitation(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.revokeInvitation")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid invitation id"))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	if err = h.service.Invitation.RevokeInvitation(ctx, p.UserID, p.Role, id); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// redeemInvitation godoc
+//
+//	@Summary		Redeem apartment invitation
+//	@Description	Привязывает текущего пользователя к квартире по коду приглашения и повышает GUEST до INHABITANT.
+//	@Description	Новая роль попадает в токен после /auth/refresh.
+//	@Tags			apartment
+//	@Security		JWT
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	redeemInvitationRequest	true	"Redeem request"
+//	@Success		204		"Квартира привязана"
+//	@Failure		400		{object}	DefaultResponse[error]	"Невалидный, истёкший или исчерпанный код"
+//	@Failure		401		{object}	DefaultResponse[error]	"Неавторизован"
+//	@Failure		500		{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/apartment/invitations/redeem [post]
+func (h *httpDelivery) redeemInvitation(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.redeemInvitation")
+	defer span.End()
+
+	var req redeemInvitationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	if err := h.service.Invitation.RedeemInvitation(ctx, p.UserID, req.Code); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+type createInvitationRequest struct {
+	ApartmentID    uuid.UUID `json:"apartment_id" validate:"required"`
+	MaxUses        int       `json:"max_uses" validate:"min=0,max=100"`
+	ExpiresInHours int       `json:"expires_in_hours" validate:"min=-1"`
+	// Role of the invited user in the household, family by default
+	Role string `json:"role" validate:"omitempty,oneof=co_owner tenant family"`
+}
+
+type getInvitationsRequest struct {
+	ApartmentID uuid.UUID `query:"apartment_id" validate:"required"`
+}
+
+type redeemInvitationRequest struct {
+	Code string `json:"code" validate:"required"`
+}
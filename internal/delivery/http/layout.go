@@ -0,0 +1,100 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+// generateLayout godoc
+//
+//	@Summary		Generate apartments from a layout
+//	@Description	Создаёт квартиры подъезда по описанию планировки: на каждом этаже от from_floor до to_floor двери от from_door до to_door,
+//	@Description	в exceptions можно задать другие двери для отдельного этажа или пропустить его (skip). Существующие квартиры не меняются,
+//	@Description	повторный запуск ничего не создаёт. В отчёте квартиры разделены на созданные, существующие и лишние (есть в подъезде, но не в планировке).
+//	@Description	С prune=true лишние квартиры удаляются. Квартиры с владельцем или жильцами не удаляются никогда: если такие есть среди лишних,
+//	@Description	они перечислены в bound и ничего не применяется. dry_run=true только возвращает отчёт.
+//	@Description	Блок и подъезд можно не указывать, пока они однозначны. Требуется право apartment.manage (ADMIN, GOD).
+//	@Tags			apartment
+//	@Security		JWT
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		layoutRequest						true	"Планировка"
+//	@Success		200		{object}	DefaultResponse[model.LayoutReport]	"Отчёт"
+//	@Failure		400		{object}	DefaultResponse[error]				"Невалидная планировка"
+//	@Failure		401		{object}	DefaultResponse[error]				"Неавторизован"
+//	@Failure		403		{object}	DefaultResponse[error]				"Недостаточно прав"
+//	@Failure		404		{object}	DefaultResponse[error]				"Блок или подъезд не найдены"
+//	@Failure		409		{object}	DefaultResponse[error]				"К удаляемой квартире привязаны пользователи"
+//	@Failure		500		{object}	DefaultResponse[error]				"Внутренняя ошибка сервера"
+//	@Router			/apartment/layout [post]
+func (h *httpDelivery) generateLayout(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.generateLayout")
+	defer span.End()
+
+	var req layoutRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	layout := model.ApartmentLayout{
+		BuildingID: req.BuildingID,
+		Entrance:   req.Entrance,
+		FromFloor:  req.FromFloor,
+		ToFloor:    req.ToFloor,
+		FromDoor:   req.FromDoor,
+		ToDoor:     req.ToDoor,
+	}
+
+	for _, e := range req.Exceptions {
+		layout.Exceptions = append(layout.Exceptions, model.LayoutFloor{
+			Floor:    e.Floor,
+			FromDoor: e.FromDoor,
+			ToDoor:   e.ToDoor,
+			Skip:     e.Skip,
+		})
+	}
+
+	res, err := h.service.Layout.GenerateLayout(ctx, p.UserID, layout, model.LayoutOptions{
+		DryRun: req.DryRun,
+		Prune:  req.Prune,
+	})
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.LayoutReport]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+type layoutRequest struct {
+	BuildingID uuid.UUID            `json:"building_id"`
+	Entrance   uint8                `json:"entrance"`
+	FromFloor  uint8                `json:"from_floor" validate:"required"`
+	ToFloor    uint8                `json:"to_floor" validate:"required,gtefield=FromFloor"`
+	FromDoor   uint16               `json:"from_door" validate:"required"`
+	ToDoor     uint16               `json:"to_door" validate:"required,gtefield=FromDoor"`
+	Exceptions []layoutFloorRequest `json:"exceptions" validate:"dive"`
+	DryRun     bool                 `json:"dry_run"`
+	Prune      bool                 `json:"prune"`
+}
+
+type layoutFloorRequest struct {
+	Floor    uint8  `json:"floor" validate:"required"`
+	FromDoor uint16 `json:"from_door"`
+	ToDoor   uint16 `json:"to_door"`
+	Skip     bool   `json:"skip"`
+}
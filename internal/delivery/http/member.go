@@ -0,0 +1,352 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+func (h *httpDelivery) registerMemberHandlers(apartment *echo.Group) {
+	apartment.GET("/memberships", h.getMyMemberships)
+
+	members := apartment.Group("/:id/members")
+
+	members.POST("", h.requestMembership)
+	members.GET("", h.getMembers)
+	members.POST("/:user_id/approve", h.approveMember)
+	members.POST("/:user_id/reject", h.rejectMember)
+	members.PATCH("/:user_id", h.changeMemberRole)
+	members.DELETE("/:user_id", h.removeMember)
+}
+
+// requestMembership godoc
+//
+//	@Summary		Ask to join an apartment
+//	@Description	Создаёт заявку текущего пользователя на вступление в квартиру с ролью co_owner, tenant или family.
+//	@Description	Заявку подтверждает владелец или совладелец квартиры (или админ). Отклонённую заявку можно подать снова.
+//	@Tags			apartment
+//	@Security		JWT
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string									true	"ID квартиры"
+//	@Param			request	body		membershipRequest						true	"Membership request"
+//	@Success		201		{object}	DefaultResponse[model.ApartmentMember]	"Заявка создана"
+//	@Failure		400		{object}	DefaultResponse[error]					"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]					"Неавторизован"
+//	@Failure		404		{object}	DefaultResponse[error]					"Квартира не найдена"
+//	@Failure		409		{object}	DefaultResponse[error]					"Пользователь уже состоит в квартире или заявка уже подана"
+//	@Failure		500		{object}	DefaultResponse[error]					"Внутренняя ошибка сервера"
+//	@Router			/apartment/{id}/members [post]
+func (h *httpDelivery) requestMembership(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.requestMembership")
+	defer span.End()
+
+	apartmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid apartment id"))
+	}
+
+	var req membershipRequest
+	if err = c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Membership.RequestMembership(ctx, p.UserID, apartmentID, model.MemberRole(req.Role))
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, DefaultResponse[model.ApartmentMember]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+// getMembers godoc
+//
+//	@Summary		List apartment members
+//	@Description	Возвращает жильцов квартиры. Владельцы, совладельцы и админы видят также заявки на вступление,
+//	@Description	остальные подтверждённые жильцы — только подтверждённых.
+//	@Tags			apartment
+//	@Security		JWT
+//	@Produce		json
+//	@Param			id	path		string										true	"ID квартиры"
+//	@Success		200	{object}	DefaultResponse[[]model.ApartmentMember]	"Успех"
+//	@Failure		400	{object}	DefaultResponse[error]						"Невалидный запрос"
+//	@Failure		401	{object}	DefaultResponse[error]						"Неавторизован"
+//	@Failure		403	{object}	DefaultResponse[error]						"Пользователь не состоит в квартире"
+//	@Failure		404	{object}	DefaultResponse[error]						"Квартира не найдена"
+//	@Failure		500	{object}	DefaultResponse[error]						"Внутренняя ошибка сервера"
+//	@Router			/apartment/{id}/members [get]
+func (h *httpDelivery) getMembers(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getMembers")
+	defer span.End()
+
+	apartmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid apartment id"))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Membership.ListMembers(ctx, p.UserID, p.Role, apartmentID)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[[]model.ApartmentMember]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+// getMyMemberships godoc
+//
+//	@Summary		List my memberships
+//	@Description	Возвращает квартиры текущего пользователя вместе с заявками на вступление и их статусами.
+//	@Tags			apartment
+//	@Security		JWT
+//	@Produce		json
+//	@Success		200	{object}	DefaultResponse[[]model.ApartmentMember]	"Успех"
+//	@Failure		401	{object}	DefaultResponse[error]						"Неавторизован"
+//	@Failure		500	{object}	DefaultResponse[error]						"Внутренняя ошибка сервера"
+//	@Router			/apartment/memberships [get]
+func (h *httpDelivery) getMyMemberships(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getMyMemberships")
+	defer span.End()
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Membership.ListUserMemberships(ctx, p.UserID)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[[]model.ApartmentMember]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+// approveMember godoc
+//
+//	@Summary		Approve membership request
+//	@Description	Подтверждает заявку на вступление. role можно не указывать — тогда остаётся роль из заявки.
+//	@Description	Пользователь получает квартиру, GUEST повышается до INHABITANT (новая роль попадает в токен после /auth/refresh).
+//	@Tags			apartment
+//	@Security		JWT
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string									true	"ID квартиры"
+//	@Param			user_id	path		string									true	"ID пользователя"
+//	@Param			request	body		approveMemberRequest					false	"Approve request"
+//	@Success		200		{object}	DefaultResponse[model.ApartmentMember]	"Заявка подтверждена"
+//	@Failure		400		{object}	DefaultResponse[error]					"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]					"Неавторизован"
+//	@Failure		403		{object}	DefaultResponse[error]					"Недостаточно прав"
+//	@Failure		404		{object}	DefaultResponse[error]					"Квартира или заявка не найдены"
+//	@Failure		409		{object}	DefaultResponse[error]					"Заявка уже рассмотрена"
+//	@Failure		500		{object}	DefaultResponse[error]					"Внутренняя ошибка сервера"
+//	@Router			/apartment/{id}/members/{user_id}/approve [post]
+func (h *httpDelivery) approveMember(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.approveMember")
+	defer span.End()
+
+	apartmentID, userID, err := memberPathParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	var req approveMemberRequest
+	if err = c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Membership.ApproveMember(ctx, p.UserID, p.Role, apartmentID, userID, model.MemberRole(req.Role))
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.ApartmentMember]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+// rejectMember godoc
+//
+//	@Summary		Reject membership request
+//	@Description	Отклоняет заявку на вступление, пользователь получает уведомление.
+//	@Tags			apartment
+//	@Security		JWT
+//	@Produce		json
+//	@Param			id		path	string	true	"ID квартиры"
+//	@Param			user_id	path	string	true	"ID пользователя"
+//	@Success		204		"Заявка отклонена"
+//	@Failure		400		{object}	DefaultResponse[error]	"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]	"Неавторизован"
+//	@Failure		403		{object}	DefaultResponse[error]	"Недостаточно прав"
+//	@Failure		404		{object}	DefaultResponse[error]	"Квартира или заявка не найдены"
+//	@Failure		409		{object}	DefaultResponse[error]	"Заявка уже рассмотрена"
+//	@Failure		500		{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/apartment/{id}/members/{user_id}/reject [post]
+func (h *httpDelivery) rejectMember(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.rejectMember")
+	defer span.End()
+
+	apartmentID, userID, err := memberPathParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	if err = h.service.Membership.RejectMember(ctx, p.UserID, p.Role, apartmentID, userID); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// changeMemberRole godoc
+//
+//	@Summary		Change member role
+//	@Description	Меняет роль жильца (co_owner, tenant, family). Роль владельца так не меняется — для этого квартира привязывается заново.
+//	@Tags			apartment
+//	@Security		JWT
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string									true	"ID квартиры"
+//	@Param			user_id	path		string									true	"ID пользователя"
+//	@Param			request	body		membershipRequest						true	"Role request"
+//	@Success		200		{object}	DefaultResponse[model.ApartmentMember]	"Роль изменена"
+//	@Failure		400		{object}	DefaultResponse[error]					"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]					"Неавторизован"
+//	@Failure		403		{object}	DefaultResponse[error]					"Недостаточно прав"
+//	@Failure		404		{object}	DefaultResponse[error]					"Квартира или жилец не найдены"
+//	@Failure		409		{object}	DefaultResponse[error]					"Нельзя изменить роль владельца"
+//	@Failure		500		{object}	DefaultResponse[error]					"Внутренняя ошибка сервера"
+//	@Router			/apartment/{id}/members/{user_id} [patch]
+func (h *httpDelivery) changeMemberRole(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.changeMemberRole")
+	defer span.End()
+
+	apartmentID, userID, err := memberPathParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	var req membershipRequest
+	if err = c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Membership.ChangeMemberRole(ctx, p.UserID, p.Role, apartmentID, userID, model.MemberRole(req.Role))
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.ApartmentMember]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+// removeMember godoc
+//
+//	@Summary		Remove apartment member
+//	@Description	Удаляет жильца из квартиры или отзывает заявку. Жилец может выйти из квартиры сам,
+//	@Description	остальных удаляют владелец, совладелец или админ. Владельца удалить нельзя.
+//	@Tags			apartment
+//	@Security		JWT
+//	@Produce		json
+//	@Param			id		path	string	true	"ID квартиры"
+//	@Param			user_id	path	string	true	"ID пользователя"
+//	@Success		204		"Жилец удалён"
+//	@Failure		400		{object}	DefaultResponse[error]	"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]	"Неавторизован"
+//	@Failure		403		{object}	DefaultResponse[error]	"Недостаточно прав"
+//	@Failure		404		{object}	DefaultResponse[error]	"Квартира или жилец не найдены"
+//	@Failure		409		{object}	DefaultResponse[error]	"Нельзя удалить владельца"
+//	@Failure		500		{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/apartment/{id}/members/{user_id} [delete]
+func (h *httpDelivery) removeMember(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.removeMember")
+	defer span.End()
+
+	apartmentID, userID, err := memberPathParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	if err = h.service.Membership.RemoveMember(ctx, p.UserID, p.Role, apartmentID, userID); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func memberPathParams(c echo.Context) (apartmentID, userID uuid.UUID, err error) {
+	if apartmentID, err = uuid.Parse(c.Param("id")); err != nil {
+		return uuid.Nil, uuid.Nil, errors.New("invalid apartment id")
+	}
+
+	if userID, err = uuid.Parse(c.Param("user_id")); err != nil {
+		return uuid.Nil, uuid.Nil, errors.New("invalid user id")
+	}
+
+	return apartmentID, userID, nil
+}
+
+type membershipRequest struct {
+	Role string `json:"role" validate:"required,oneof=co_owner tenant family"`
+}
+
+type approveMemberRequest struct {
+	// Role overrides the requested role, optional
+	Role string `json:"role" validate:"omitempty,oneof=co_owner tenant family"`
+}
@@ -30,48 +30,65 @@ func (h *httpDelivery) handleErrResponse(c echo.Context, err error) error {
 		return c.JSON(appErr.HttpStatusCode, ErrorResponse(appErr.Error()))
 	}
 
-	h.logger.Error("internal error %s", err.Error())
+	h.logger.Error("internal error", "error", err)
 
 	return c.JSON(http.StatusInternalServerError, ErrorResponse("internal error"))
 }
 
 func (h *httpDelivery) registerJWTMiddleware() func(next echo.HandlerFunc) echo.HandlerFunc {
-	return echojwt.WithConfig(echojwt.Config{
-		SigningKey: []byte(h.jwtSecret),
+	jwtMiddleware := echojwt.WithConfig(echojwt.Config{
+		ParseTokenFunc: func(c echo.Context, auth string) (interface{}, error) {
+			return h.jwtKeys.Parse(auth, jwt.MapClaims{})
+		},
 	})
-}
 
-func (h *httpDelivery) getJWTData() func(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			token, ok := c.Get("user").(*jwt.Token)
-			if !ok {
-				return c.JSON(http.StatusUnauthorized, "JWT token missing or invalid")
-			}
+		return jwtMiddleware(h.authenticate(next))
+	}
+}
 
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if !ok {
-				return c.JSON(http.StatusUnauthorized, "failed to cast claims")
-			}
+// authenticate turns token claims into a principal and rejects tokens whose server-side session
+// was revoked (logout, user deletion, etc.)
+func (h *httpDelivery) authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token, ok := c.Get("user").(*jwt.Token)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, ErrorResponse("JWT token missing or invalid"))
+		}
 
-			username, ok := claims["username"].(string)
-			if !ok {
-				return c.JSON(http.StatusUnauthorized, "failed to cast username")
-			}
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, ErrorResponse("failed to cast claims"))
+		}
 
-			userId, ok := claims["user_id"].(string)
-			if !ok {
-				return c.JSON(http.StatusUnauthorized, "failed to cast user_id")
-			}
+		p, err := principalFromClaims(claims)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, ErrorResponse(err.Error()))
+		}
+
+		if err = h.service.Auth.ValidateSession(c.Request().Context(), p.SessionID, p.UserID, c.RealIP()); err != nil {
+			return h.handleErrResponse(c, err)
+		}
+
+		c.Set(principalContextKey, p)
 
-			role, ok := claims["role"].(string)
+		return next(c)
+	}
+}
+
+// requirePermission allows the request only if the principal's role has perm.
+// Must run after registerJWTMiddleware.
+func (h *httpDelivery) requirePermission(perm model.Permission) func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			p, ok := getPrincipal(c)
 			if !ok {
-				return c.JSON(http.StatusUnauthorized, "failed to cast role")
+				return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
 			}
 
-			c.Set("username", username)
-			c.Set("user_id", userId)
-			c.Set("role", role)
+			if !model.HasPermission(p.Role, perm) {
+				return h.handleErrResponse(c, model.ErrPermissionDenied)
+			}
 
 			return next(c)
 		}
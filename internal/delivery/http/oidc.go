@@ -0,0 +1,117 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+func (h *httpDelivery) registerOIDCHandlers(auth *echo.Group) {
+	oidc := auth.Group("/oidc")
+
+	oidc.GET("/providers", h.getOIDCProviders)
+	oidc.GET("/:provider/start", h.startOIDCLogin)
+	oidc.GET("/:provider/callback", h.oidcCallback)
+}
+
+// getOIDCProviders godoc
+//
+//	@Summary		List external identity providers
+//	@Description	Возвращает имена настроенных OpenID Connect провайдеров.
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	DefaultResponse[[]string]	"Успех"
+//	@Router			/auth/oidc/providers [get]
+func (h *httpDelivery) getOIDCProviders(c echo.Context) error {
+	_, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getOIDCProviders")
+	defer span.End()
+
+	return c.JSON(http.StatusOK, DefaultResponse[[]string]{
+		Status: "ok",
+		Data:   h.service.Auth.OIDCProviders(),
+	})
+}
+
+// startOIDCLogin godoc
+//
+//	@Summary		Start login via external identity provider
+//	@Description	Возвращает URL провайдера (authorization code + PKCE), на который нужно отправить пользователя.
+//	@Description	После входа провайдер вернёт пользователя на /auth/oidc/{provider}/callback.
+//	@Tags			auth
+//	@Produce		json
+//	@Param			provider	path		string							true	"Имя провайдера"
+//	@Success		200			{object}	DefaultResponse[oidcStartResponse]	"Успех"
+//	@Failure		404			{object}	DefaultResponse[error]			"Провайдер не найден"
+//	@Failure		500			{object}	DefaultResponse[error]			"Внутренняя ошибка сервера"
+//	@Router			/auth/oidc/{provider}/start [get]
+func (h *httpDelivery) startOIDCLogin(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.startOIDCLogin")
+	defer span.End()
+
+	authURL, err := h.service.Auth.StartOIDCLogin(ctx, c.Param("provider"))
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[oidcStartResponse]{
+		Status: "ok",
+		Data:   oidcStartResponse{AuthorizationURL: authURL},
+	})
+}
+
+// oidcCallback godoc
+//
+//	@Summary		External identity provider callback
+//	@Description	Обменивает код авторизации на ID токен, связывает внешний аккаунт с пользователем
+//	@Description	(или создаёт нового с ролью GUEST) и выдаёт токены. Ответ такой же, как у /auth/login, включая шаг 2FA.
+//	@Tags			auth
+//	@Produce		json
+//	@Param			provider	path		string							true	"Имя провайдера"
+//	@Param			code		query		string							false	"Код авторизации"
+//	@Param			state		query		string							true	"State из /start"
+//	@Param			error		query		string							false	"Ошибка от провайдера"
+//	@Success		200			{object}	DefaultResponse[loginResponse]	"Успех"
+//	@Failure		400			{object}	DefaultResponse[error]			"Невалидный запрос или state истёк"
+//	@Failure		401			{object}	DefaultResponse[error]			"Вход у провайдера не удался"
+//	@Failure		403			{object}	DefaultResponse[error]			"Пользователь отключён администратором"
+//	@Failure		404			{object}	DefaultResponse[error]			"Провайдер не найден"
+//	@Failure		500			{object}	DefaultResponse[error]			"Внутренняя ошибка сервера"
+//	@Router			/auth/oidc/{provider}/callback [get]
+func (h *httpDelivery) oidcCallback(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.oidcCallback")
+	defer span.End()
+
+	var req oidcCallbackRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if req.Error != "" {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse(model.ErrOIDCFailed.Message+": "+req.Error))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	res, err := h.service.Auth.CompleteOIDCLogin(ctx, c.Param("provider"), req.Code, req.State, clientInfo(c, ""))
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[loginResponse]{
+		Status: "ok",
+		Data:   newLoginResponse(res),
+	})
+}
+
+type oidcStartResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type oidcCallbackRequest struct {
+	Code  string `query:"code" validate:"required"`
+	State string `query:"state" validate:"required"`
+	Error string `query:"error"`
+}
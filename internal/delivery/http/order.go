@@ -13,14 +13,15 @@ func (h *httpDelivery) registerOrderHandlers(v1 *echo.Group) {
 	order := v1.Group("/order")
 
 	order.Use(h.registerJWTMiddleware())
-	order.GET("", h.getOrders, h.getJWTData())
-	order.POST("", h.createOrder, h.getJWTData())
+	order.GET("", h.getOrders)
+	order.POST("", h.createOrder)
 }
 
 // createOrder godoc
 //
 //	@Summary		Create order
 //	@Description	Создаёт заявку/заказ от имени авторизованного пользователя. OrderType должен существовать в enum protopb.OrderType.
+//	@Description	Заказ оформляется на квартиру пользователя, поэтому доступен только подтверждённым жильцам квартиры.
 //	@Tags			order
 //	@Accept			json
 //	@Produce		json
@@ -29,6 +30,7 @@ func (h *httpDelivery) registerOrderHandlers(v1 *echo.Group) {
 //	@Success		204		{object}	DefaultResponse[string]	"Успех (No Content)"
 //	@Failure		400		{object}	DefaultResponse[error]	"Невалидный запрос"
 //	@Failure		401		{object}	DefaultResponse[error]	"Не авторизован / некорректный user_id в токене"
+//	@Failure		403		{object}	DefaultResponse[error]	"Пользователь не является жильцом квартиры"
 //	@Failure		422		{object}	DefaultResponse[error]	"Невалидный order_type"
 //	@Failure		500		{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
 //	@Router			/order [post]
@@ -41,14 +43,9 @@ func (h *httpDelivery) createOrder(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 	}
 
-	userId, ok := c.Get("user_id").(string)
+	p, ok := getPrincipal(c)
 	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("failed to get user_id from context"))
-	}
-
-	parsed, err := uuid.Parse(userId)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, ErrorResponse("invalid user id"))
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
 	}
 
 	val, ok := protopb.OrderType_value[req.OrderType]
@@ -58,8 +55,8 @@ func (h *httpDelivery) createOrder(c echo.Context) error {
 
 	orderType := protopb.OrderType(val)
 
-	if err = h.service.Order.OrderService(ctx, &model.Order{
-		UserID:    parsed,
+	if err := h.service.Order.OrderService(ctx, &model.Order{
+		UserID:    p.UserID,
 		Text:      req.Text,
 		OrderType: orderType,
 	}); err != nil {
@@ -104,26 +101,16 @@ func (h *httpDelivery) getOrders(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 	}
 
-	role, ok := c.Get("role").(string)
+	p, ok := getPrincipal(c)
 	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("failed to get role from context"))
-	}
-
-	userId, ok := c.Get("user_id").(string)
-	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("failed to get user_id from context"))
-	}
-
-	parsed, err := uuid.Parse(userId)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, ErrorResponse("invalid user id"))
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
 	}
 
 	resp, err := h.service.Order.GetUserOrders(ctx, &model.GetOrderRequest{
-		UserID:    parsed,
+		UserID:    p.UserID,
 		OrderType: req.OrderType,
 		Text:      req.Text,
-	}, role)
+	}, p.Role)
 	if err != nil {
 		return h.handleErrResponse(c, err)
 	}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/podpivasniki1488/assyl-backend/protopb"
+)
+
+const principalContextKey = "principal"
+
+// principal is the authenticated caller, built once from access token claims.
+type principal struct {
+	UserID    uuid.UUID
+	Username  string
+	Role      protopb.Role
+	SessionID uuid.UUID
+}
+
+func principalFromClaims(claims jwt.MapClaims) (principal, error) {
+	username, ok := claims["username"].(string)
+	if !ok {
+		return principal{}, errors.New("failed to cast username")
+	}
+
+	rawUserId, _ := claims["user_id"].(string)
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return principal{}, errors.New("invalid user id")
+	}
+
+	rawRole, _ := claims["role"].(string)
+	role, ok := protopb.Role_value[rawRole]
+	if !ok {
+		return principal{}, errors.New("invalid role")
+	}
+
+	rawSid, _ := claims["sid"].(string)
+	sid, err := uuid.Parse(rawSid)
+	if err != nil {
+		return principal{}, errors.New("invalid session id")
+	}
+
+	return principal{
+		UserID:    userId,
+		Username:  username,
+		Role:      protopb.Role(role),
+		SessionID: sid,
+	}, nil
+}
+
+func getPrincipal(c echo.Context) (principal, bool) {
+	p, ok := c.Get(principalContextKey).(principal)
+	return p, ok
+}
@@ -0,0 +1,251 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+// registerProfileHandlers adds the self-service endpoints, available to every authenticated user.
+func (h *httpDelivery) registerProfileHandlers(user *echo.Group) {
+	user.GET("/me", h.getProfile)
+	user.PATCH("/me", h.updateProfile)
+	user.PUT("/me/password", h.changePassword)
+	user.POST("/me/username", h.startUsernameChange)
+	user.POST("/me/username/confirm", h.confirmUsernameChange)
+	user.GET("/me/sessions", h.listSessions)
+	user.DELETE("/me/sessions", h.revokeOtherSessions)
+	user.DELETE("/me/sessions/:id", h.revokeSession)
+}
+
+// getProfile godoc
+//
+//	@Summary		Get own profile
+//	@Description	Возвращает профиль текущего пользователя вместе с привязанной квартирой (этаж/дверь).
+//	@Description	Пароль и секреты 2FA не возвращаются.
+//	@Tags			user
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	DefaultResponse[model.Profile]	"Успех"
+//	@Failure		401	{object}	DefaultResponse[error]			"Не авторизован"
+//	@Failure		404	{object}	DefaultResponse[error]			"Пользователь не найден"
+//	@Failure		500	{object}	DefaultResponse[error]			"Внутренняя ошибка сервера"
+//	@Router			/user/me [get]
+func (h *httpDelivery) getProfile(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getProfile")
+	defer span.End()
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Profile.GetProfile(ctx, p.UserID)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[*model.Profile]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+// updateProfile godoc
+//
+//	@Summary		Update own profile
+//	@Description	Меняет имя, фамилию и аватар. Изменяются только переданные поля, пустой avatar_url удаляет аватар.
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		updateProfileRequest			true	"Update profile request"
+//	@Success		200		{object}	DefaultResponse[model.Profile]	"Успех"
+//	@Failure		400		{object}	DefaultResponse[error]			"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]			"Не авторизован"
+//	@Failure		500		{object}	DefaultResponse[error]			"Внутренняя ошибка сервера"
+//	@Router			/user/me [patch]
+func (h *httpDelivery) updateProfile(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.updateProfile")
+	defer span.End()
+
+	var req updateProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Profile.UpdateProfile(ctx, p.UserID, model.UpdateProfileRequest{
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		AvatarURL: req.AvatarURL,
+	})
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[*model.Profile]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+// changePassword godoc
+//
+//	@Summary		Change own password
+//	@Description	Меняет пароль по текущему паролю. Остальные сессии пользователя завершаются, текущая остаётся.
+//	@Description	Неверный текущий пароль учитывается как неудачная попытка входа.
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body	changePasswordRequest	true	"Change password request"
+//	@Success		204		"Пароль изменён"
+//	@Failure		400		{object}	DefaultResponse[error]	"Невалидный запрос / неверный текущий пароль"
+//	@Failure		401		{object}	DefaultResponse[error]	"Не авторизован"
+//	@Failure		423		{object}	DefaultResponse[error]	"Аккаунт временно заблокирован"
+//	@Failure		429		{object}	DefaultResponse[error]	"Слишком много попыток"
+//	@Failure		500		{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/user/me/password [put]
+func (h *httpDelivery) changePassword(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.changePassword")
+	defer span.End()
+
+	var req changePasswordRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	if err := h.service.Profile.ChangePassword(ctx, p.UserID, p.SessionID, req.CurrentPassword, req.NewPassword); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// startUsernameChange godoc
+//
+//	@Summary		Start username change
+//	@Description	Отправляет OTP-код на новый email или телефон. Требуется текущий пароль.
+//	@Description	Логин меняется только после подтверждения кода через /user/me/username/confirm.
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body	startUsernameChangeRequest	true	"Username change request"
+//	@Success		202		"Код отправлен"
+//	@Failure		400		{object}	DefaultResponse[error]	"Невалидный запрос / неверный пароль / логин занят"
+//	@Failure		401		{object}	DefaultResponse[error]	"Не авторизован"
+//	@Failure		423		{object}	DefaultResponse[error]	"Аккаунт временно заблокирован"
+//	@Failure		429		{object}	DefaultResponse[error]	"Код уже отправлен недавно"
+//	@Failure		500		{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/user/me/username [post]
+func (h *httpDelivery) startUsernameChange(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.startUsernameChange")
+	defer span.End()
+
+	var req startUsernameChangeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	if err := h.service.Profile.StartUsernameChange(ctx, p.UserID, req.Password, req.Username); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// confirmUsernameChange godoc
+//
+//	@Summary		Confirm username change
+//	@Description	Подтверждает новый логин кодом из OTP. Остальные сессии завершаются, на старый адрес уходит уведомление.
+//	@Description	Текущую сессию нужно обновить через /auth/refresh, чтобы токен содержал новый логин.
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		confirmUsernameChangeRequest	true	"Confirm request"
+//	@Success		200		{object}	DefaultResponse[model.Profile]	"Успех"
+//	@Failure		400		{object}	DefaultResponse[error]			"Невалидный или просроченный код / логин занят"
+//	@Failure		401		{object}	DefaultResponse[error]			"Не авторизован"
+//	@Failure		429		{object}	DefaultResponse[error]			"Слишком много попыток"
+//	@Failure		500		{object}	DefaultResponse[error]			"Внутренняя ошибка сервера"
+//	@Router			/user/me/username/confirm [post]
+func (h *httpDelivery) confirmUsernameChange(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.confirmUsernameChange")
+	defer span.End()
+
+	var req confirmUsernameChangeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Profile.ConfirmUsernameChange(ctx, p.UserID, p.SessionID, req.OtpCode)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[*model.Profile]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+type updateProfileRequest struct {
+	FirstName *string `json:"first_name" validate:"omitempty,min=1,max=64"`
+	LastName  *string `json:"last_name" validate:"omitempty,min=1,max=64"`
+	// AvatarURL is an http(s) link, an empty string removes the avatar
+	AvatarURL *string `json:"avatar_url" validate:"omitempty,max=512,len=0|http_url"`
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=32,nefield=CurrentPassword"`
+}
+
+type startUsernameChangeRequest struct {
+	// Username is the new email or phone number in E.164 format
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type confirmUsernameChangeRequest struct {
+	OtpCode string `json:"otp_code" validate:"required"`
+}
@@ -7,23 +7,25 @@ import (
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
-	"github.com/podpivasniki1488/assyl-backend/protopb"
 )
 
 func (h *httpDelivery) registerReservationHandlers(v1 *echo.Group) {
 	reservation := v1.Group("/reservation")
 
 	reservation.Use(h.registerJWTMiddleware())
-	reservation.POST("", h.createReservation, h.getJWTData())
-	reservation.GET("", h.getReservation, h.getJWTData())
-	reservation.PATCH("/approve", h.approveReservation, h.getJWTData())
+	reservation.POST("", h.createReservation)
+	reservation.GET("", h.getReservation)
+	reservation.PATCH("/approve", h.approveReservation, h.requirePermission(model.PermReservationApprove))
 	reservation.GET("/free-slots", h.getFreeSlots)
+
+	h.registerSlotTemplateHandlers(reservation)
 }
 
 // getReservation godoc
 //
 //	@Summary		Get user reservations
 //	@Description	Возвращает список бронирований текущего пользователя за период (from-to).
+//	@Description	Дни считаются в таймзоне кинотеатра, время бронирований возвращается в ней же.
 //	@Tags			reservation
 //	@Security		BearerAuth
 //	@Accept			json
@@ -40,22 +42,17 @@ func (h *httpDelivery) getReservation(c echo.Context) error {
 	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getReservation")
 	defer span.End()
 
-	userId, ok := c.Get("user_id").(string)
+	p, ok := getPrincipal(c)
 	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("failed to get user_id from context"))
-	}
-
-	parsed, err := uuid.Parse(userId)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, ErrorResponse("invalid user id"))
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
 	}
 
 	var req getReservationRequest
-	if err = c.Bind(&req); err != nil {
+	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
 
-	if err = validate.Struct(&req); err != nil {
+	if err := validate.Struct(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
 
@@ -69,7 +66,7 @@ func (h *httpDelivery) getReservation(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid date format (YYYY-MM-DD)"))
 	}
 
-	resp, err := h.service.Reservation.GetUserReservations(ctx, parsed, parsedStartDate, parsedEndDate)
+	resp, err := h.service.Reservation.GetUserReservations(ctx, p.UserID, parsedStartDate, parsedEndDate)
 	if err != nil {
 		return h.handleErrResponse(c, err)
 	}
@@ -108,15 +105,6 @@ func (h *httpDelivery) approveReservation(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	role, ok := c.Get("role").(string)
-	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("failed to get role from context"))
-	}
-
-	if protopb.Role_ADMIN.String() != role || protopb.Role_ADMIN.String() != role {
-		return c.JSON(http.StatusForbidden, ErrorResponse("only admins can approve reservations"))
-	}
-
 	if err := h.service.Reservation.ApproveReservation(ctx, req.ReservationId); err != nil {
 		return h.handleErrResponse(c, err)
 	}
@@ -128,6 +116,7 @@ func (h *httpDelivery) approveReservation(c echo.Context) error {
 //
 //	@Summary		Create reservation
 //	@Description	Создаёт бронирование на указанный период для текущего пользователя.
+//	@Description	date — календарный день в таймзоне кинотеатра.
 //	@Tags			reservation
 //	@Security		BearerAuth
 //	@Accept			json
@@ -152,24 +141,9 @@ func (h *httpDelivery) createReservation(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	userId, ok := c.Get("user_id").(string)
-	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("failed to get user_id from context"))
-	}
-
-	parsed, err := uuid.Parse(userId)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, ErrorResponse("invalid user id"))
-	}
-
-	username, ok := c.Get("username").(string)
-	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("failed to get username from context"))
-	}
-
-	role, ok := c.Get("role").(string)
+	p, ok := getPrincipal(c)
 	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("failed to get role from context"))
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
 	}
 
 	parsedDate, err := time.Parse("2006-01-02", req.Date)
@@ -178,11 +152,11 @@ func (h *httpDelivery) createReservation(c echo.Context) error {
 	}
 
 	positions := make([]int16, 0, len(req.TimeSlots))
-	for _, p := range req.TimeSlots {
-		positions = append(positions, int16(p))
+	for _, slot := range req.TimeSlots {
+		positions = append(positions, int16(slot))
 	}
 
-	res, err := h.service.Reservation.MakeReservation(ctx, parsed, parsedDate, positions, req.PeopleNum, role, username)
+	res, err := h.service.Reservation.MakeReservation(ctx, p.UserID, parsedDate, positions, req.PeopleNum, p.Role, p.Username)
 	if err != nil {
 		return h.handleErrResponse(c, err)
 	}
@@ -202,6 +176,7 @@ func (h *httpDelivery) createReservation(c echo.Context) error {
 //	@Summary		Get free time slots
 //	@Description	Возвращает список свободных временных интервалов в заданном диапазоне.
 //	@Description	Свободный слот — это интервал времени, не пересекающийся ни с одной резервацией.
+//	@Description	Дата и время слотов указаны в таймзоне кинотеатра (VENUE_TIMEZONE, по умолчанию Asia/Almaty).
 //	@Tags			reservation
 //	@Security		BearerAuth
 //	@Produce		json
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+// clientInfo describes the caller's device for the session list.
+func clientInfo(c echo.Context, device string) model.ClientInfo {
+	return model.ClientInfo{
+		IP:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+		Device:    device,
+	}
+}
+
+// listSessions godoc
+//
+//	@Summary		List own sessions
+//	@Description	Возвращает активные сессии пользователя: устройство, IP, user agent и время последней активности.
+//	@Description	Сессия, из которой пришёл запрос, помечена current=true.
+//	@Tags			user
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	DefaultResponse[[]model.Session]	"Успех"
+//	@Failure		401	{object}	DefaultResponse[error]				"Не авторизован"
+//	@Failure		500	{object}	DefaultResponse[error]				"Внутренняя ошибка сервера"
+//	@Router			/user/me/sessions [get]
+func (h *httpDelivery) listSessions(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.listSessions")
+	defer span.End()
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.Profile.ListSessions(ctx, p.UserID, p.SessionID)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[[]model.Session]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+// revokeSession godoc
+//
+//	@Summary		Revoke a session
+//	@Description	Завершает одну сессию пользователя, например на потерянном устройстве. Её токены перестают работать сразу.
+//	@Tags			user
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"ID сессии"
+//	@Success		204	"Сессия завершена"
+//	@Failure		400	{object}	DefaultResponse[error]	"Невалидный ID"
+//	@Failure		401	{object}	DefaultResponse[error]	"Не авторизован"
+//	@Failure		404	{object}	DefaultResponse[error]	"Сессия не найдена"
+//	@Failure		500	{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/user/me/sessions/{id} [delete]
+func (h *httpDelivery) revokeSession(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.revokeSession")
+	defer span.End()
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	if err = h.service.Profile.RevokeSession(ctx, p.UserID, sessionID); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// revokeOtherSessions godoc
+//
+//	@Summary		Revoke all other sessions
+//	@Description	Завершает все сессии пользователя, кроме текущей.
+//	@Tags			user
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		204	"Сессии завершены"
+//	@Failure		401	{object}	DefaultResponse[error]	"Не авторизован"
+//	@Failure		500	{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/user/me/sessions [delete]
+func (h *httpDelivery) revokeOtherSessions(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.revokeOtherSessions")
+	defer span.End()
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	if err := h.service.Profile.RevokeOtherSessions(ctx, p.UserID, p.SessionID); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
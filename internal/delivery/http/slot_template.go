@@ -0,0 +1,208 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+func (h *httpDelivery) registerSlotTemplateHandlers(reservation *echo.Group) {
+	reservation.GET("/slot-templates", h.listSlotTemplates, h.requirePermission(model.PermSlotManage))
+	reservation.POST("/slot-templates", h.createSlotTemplate, h.requirePermission(model.PermSlotManage))
+	reservation.PATCH("/slot-templates/:id", h.updateSlotTemplate, h.requirePermission(model.PermSlotManage))
+	reservation.DELETE("/slot-templates/:id", h.deleteSlotTemplate, h.requirePermission(model.PermSlotManage))
+}
+
+// listSlotTemplates godoc
+//
+//	@Summary		List slot templates
+//	@Description	Возвращает все шаблоны слотов кинотеатра, включая неактивные, по порядку position.
+//	@Description	Требуется право slot.manage (ADMIN, GOD).
+//	@Tags			reservation
+//	@Security		JWT
+//	@Produce		json
+//	@Success		200	{object}	DefaultResponse[[]model.SlotTemplate]	"Успех"
+//	@Failure		401	{object}	DefaultResponse[error]					"Неавторизован"
+//	@Failure		403	{object}	DefaultResponse[error]					"Недостаточно прав"
+//	@Failure		500	{object}	DefaultResponse[error]					"Внутренняя ошибка сервера"
+//	@Router			/reservation/slot-templates [get]
+func (h *httpDelivery) listSlotTemplates(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.listSlotTemplates")
+	defer span.End()
+
+	res, err := h.service.SlotTemplates.ListSlotTemplates(ctx)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[[]model.SlotTemplate]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+// createSlotTemplate godoc
+//
+//	@Summary		Create slot template
+//	@Description	Создаёт шаблон слота. Время указывается как HH:MM в таймзоне кинотеатра, конец позже начала.
+//	@Description	Активный шаблон не может пересекаться по времени с другими активными. Слоты по новому шаблону
+//	@Description	появляются при следующем запросе дня. Требуется право slot.manage (ADMIN, GOD).
+//	@Tags			reservation
+//	@Security		JWT
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		createSlotTemplateRequest			true	"Шаблон"
+//	@Success		201		{object}	DefaultResponse[model.SlotTemplate]	"Шаблон создан"
+//	@Failure		400		{object}	DefaultResponse[error]				"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]				"Неавторизован"
+//	@Failure		403		{object}	DefaultResponse[error]				"Недостаточно прав"
+//	@Failure		409		{object}	DefaultResponse[error]				"Код или позиция заняты, либо время пересекается"
+//	@Failure		500		{object}	DefaultResponse[error]				"Внутренняя ошибка сервера"
+//	@Router			/reservation/slot-templates [post]
+func (h *httpDelivery) createSlotTemplate(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.createSlotTemplate")
+	defer span.End()
+
+	var req createSlotTemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	t := model.SlotTemplate{
+		Code:      req.Code,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Position:  req.Position,
+		IsActive:  true,
+	}
+
+	if req.IsActive != nil {
+		t.IsActive = *req.IsActive
+	}
+
+	res, err := h.service.SlotTemplates.CreateSlotTemplate(ctx, t)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, DefaultResponse[model.SlotTemplate]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+// updateSlotTemplate godoc
+//
+//	@Summary		Update slot template
+//	@Description	Меняет шаблон слота, меняются только переданные поля. Изменение сразу применяется к ещё не начавшимся слотам:
+//	@Description	свободные получают новое время и позицию, забронированные сохраняют время брони (их число в reserved_slots).
+//	@Description	Свободные слоты, которые с новым временем пересекаются с бронью того же дня, отключаются (disabled_slots, дни в conflict_dates).
+//	@Description	Деактивация удаляет свободные будущие слоты шаблона, забронированные остаются.
+//	@Description	Требуется право slot.manage (ADMIN, GOD).
+//	@Tags			reservation
+//	@Security		JWT
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int											true	"ID шаблона"
+//	@Param			request	body		updateSlotTemplateRequest					true	"Новые значения"
+//	@Success		200		{object}	DefaultResponse[model.SlotTemplateChange]	"Шаблон обновлён"
+//	@Failure		400		{object}	DefaultResponse[error]						"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]						"Неавторизован"
+//	@Failure		403		{object}	DefaultResponse[error]						"Недостаточно прав"
+//	@Failure		404		{object}	DefaultResponse[error]						"Шаблон не найден"
+//	@Failure		409		{object}	DefaultResponse[error]						"Код или позиция заняты, либо время пересекается"
+//	@Failure		500		{object}	DefaultResponse[error]						"Внутренняя ошибка сервера"
+//	@Router			/reservation/slot-templates/{id} [patch]
+func (h *httpDelivery) updateSlotTemplate(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.updateSlotTemplate")
+	defer span.End()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 16)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid slot template id"))
+	}
+
+	var req updateSlotTemplateRequest
+	if err = c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	res, err := h.service.SlotTemplates.UpdateSlotTemplate(ctx, int16(id), model.UpdateSlotTemplateRequest{
+		Code:      req.Code,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Position:  req.Position,
+		IsActive:  req.IsActive,
+	})
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.SlotTemplateChange]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+// deleteSlotTemplate godoc
+//
+//	@Summary		Delete slot template
+//	@Description	Удаляет шаблон слота вместе со свободными будущими слотами. Забронированные слоты и брони остаются.
+//	@Description	Требуется право slot.manage (ADMIN, GOD).
+//	@Tags			reservation
+//	@Security		JWT
+//	@Produce		json
+//	@Param			id	path		int											true	"ID шаблона"
+//	@Success		200	{object}	DefaultResponse[model.SlotTemplateChange]	"Шаблон удалён"
+//	@Failure		400	{object}	DefaultResponse[error]						"Невалидный ID"
+//	@Failure		401	{object}	DefaultResponse[error]						"Неавторизован"
+//	@Failure		403	{object}	DefaultResponse[error]						"Недостаточно прав"
+//	@Failure		404	{object}	DefaultResponse[error]						"Шаблон не найден"
+//	@Failure		500	{object}	DefaultResponse[error]						"Внутренняя ошибка сервера"
+//	@Router			/reservation/slot-templates/{id} [delete]
+func (h *httpDelivery) deleteSlotTemplate(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.deleteSlotTemplate")
+	defer span.End()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 16)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid slot template id"))
+	}
+
+	res, err := h.service.SlotTemplates.DeleteSlotTemplate(ctx, int16(id))
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.SlotTemplateChange]{
+		Status: "ok",
+		Data:   *res,
+	})
+}
+
+type createSlotTemplateRequest struct {
+	Code      string `json:"code" validate:"required,max=32"`
+	StartTime string `json:"start_time" validate:"required"`
+	EndTime   string `json:"end_time" validate:"required"`
+	Position  int16  `json:"position" validate:"required,min=1"`
+	// IsActive defaults to true
+	IsActive *bool `json:"is_active"`
+}
+
+type updateSlotTemplateRequest struct {
+	Code      *string `json:"code" validate:"omitempty,min=1,max=32"`
+	StartTime *string `json:"start_time"`
+	EndTime   *string `json:"end_time"`
+	Position  *int16  `json:"position" validate:"omitempty,min=1"`
+	IsActive  *bool   `json:"is_active"`
+}
@@ -0,0 +1,278 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+func (h *httpDelivery) registerTwoFactorHandlers(auth *echo.Group) {
+	twoFactor := auth.Group("/2fa")
+
+	// second step of the login, authorized by the challenge token from /auth/login
+	twoFactor.POST("/verify", h.verifyTwoFactor)
+	twoFactor.POST("/setup", h.setupTwoFactor)
+
+	twoFactor.POST("/enroll", h.enrollTwoFactor, h.registerJWTMiddleware())
+	twoFactor.POST("/enable", h.enableTwoFactor, h.registerJWTMiddleware())
+	twoFactor.POST("/disable", h.disableTwoFactor, h.registerJWTMiddleware())
+	twoFactor.POST("/recovery-codes", h.regenerateRecoveryCodes, h.registerJWTMiddleware())
+}
+
+// verifyTwoFactor godoc
+//
+//	@Summary		Finish login with the second factor
+//	@Description	Принимает challenge_token из /auth/login и TOTP-код (или резервный код) и выдаёт токены.
+//	@Description	Если вход начался с two_factor=setup, код подтверждает подключение 2FA и в ответе приходят резервные коды.
+//	@Description	Неверные коды учитываются вместе с неверными паролями и могут заблокировать аккаунт.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		verifyTwoFactorRequest			true	"Verify request"
+//	@Success		200		{object}	DefaultResponse[loginResponse]	"Успех"
+//	@Failure		400		{object}	DefaultResponse[error]			"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]			"Неверный код или challenge истёк"
+//	@Failure		423		{object}	DefaultResponse[error]			"Аккаунт временно заблокирован"
+//	@Failure		429		{object}	DefaultResponse[error]			"Слишком много попыток входа"
+//	@Failure		500		{object}	DefaultResponse[error]			"Внутренняя ошибка сервера"
+//	@Router			/auth/2fa/verify [post]
+func (h *httpDelivery) verifyTwoFactor(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.verifyTwoFactor")
+	defer span.End()
+
+	var req verifyTwoFactorRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	res, err := h.service.Auth.CompleteTwoFactorLogin(ctx, req.ChallengeToken, req.Code, clientInfo(c, req.Device))
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[loginResponse]{
+		Status: "ok",
+		Data:   newLoginResponse(res),
+	})
+}
+
+// setupTwoFactor godoc
+//
+//	@Summary		Start mandatory 2FA enrollment during login
+//	@Description	Для входа с two_factor=setup: возвращает секрет и otpauth URI для приложения-аутентификатора.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		setupTwoFactorRequest					true	"Setup request"
+//	@Success		200		{object}	DefaultResponse[model.TotpEnrollment]	"Успех"
+//	@Failure		400		{object}	DefaultResponse[error]					"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]					"Challenge не найден или истёк"
+//	@Failure		409		{object}	DefaultResponse[error]					"2FA уже включена"
+//	@Failure		500		{object}	DefaultResponse[error]					"Внутренняя ошибка сервера"
+//	@Router			/auth/2fa/setup [post]
+func (h *httpDelivery) setupTwoFactor(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.setupTwoFactor")
+	defer span.End()
+
+	var req setupTwoFactorRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	res, err := h.service.Auth.StartTwoFactorSetup(ctx, req.ChallengeToken)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.TotpEnrollment]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+// enrollTwoFactor godoc
+//
+//	@Summary		Start 2FA enrollment
+//	@Description	Генерирует TOTP-секрет и otpauth URI. 2FA включается только после /auth/2fa/enable.
+//	@Tags			auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	DefaultResponse[model.TotpEnrollment]	"Успех"
+//	@Failure		401	{object}	DefaultResponse[error]					"Не авторизован"
+//	@Failure		409	{object}	DefaultResponse[error]					"2FA уже включена"
+//	@Failure		500	{object}	DefaultResponse[error]					"Внутренняя ошибка сервера"
+//	@Router			/auth/2fa/enroll [post]
+func (h *httpDelivery) enrollTwoFactor(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.enrollTwoFactor")
+	defer span.End()
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	res, err := h.service.TwoFactor.StartEnrollment(ctx, p.UserID)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.TotpEnrollment]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+// enableTwoFactor godoc
+//
+//	@Summary		Enable 2FA
+//	@Description	Подтверждает подключение 2FA первым кодом из приложения и возвращает резервные коды.
+//	@Description	Резервные коды показываются один раз.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		twoFactorCodeRequest		true	"Code request"
+//	@Success		200		{object}	DefaultResponse[[]string]	"Резервные коды"
+//	@Failure		400		{object}	DefaultResponse[error]		"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]		"Неверный код"
+//	@Failure		409		{object}	DefaultResponse[error]		"2FA уже включена"
+//	@Failure		500		{object}	DefaultResponse[error]		"Внутренняя ошибка сервера"
+//	@Router			/auth/2fa/enable [post]
+func (h *httpDelivery) enableTwoFactor(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.enableTwoFactor")
+	defer span.End()
+
+	var req twoFactorCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	codes, err := h.service.TwoFactor.Enable(ctx, p.UserID, req.Code)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[[]string]{
+		Status: "ok",
+		Data:   codes,
+	})
+}
+
+// disableTwoFactor godoc
+//
+//	@Summary		Disable 2FA
+//	@Description	Отключает 2FA по текущему коду или резервному коду. Недоступно, если 2FA обязательна для роли.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body	twoFactorCodeRequest	true	"Code request"
+//	@Success		204		"2FA отключена"
+//	@Failure		400		{object}	DefaultResponse[error]	"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]	"Неверный код"
+//	@Failure		403		{object}	DefaultResponse[error]	"2FA обязательна для роли"
+//	@Failure		423		{object}	DefaultResponse[error]	"Аккаунт временно заблокирован"
+//	@Failure		500		{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/auth/2fa/disable [post]
+func (h *httpDelivery) disableTwoFactor(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.disableTwoFactor")
+	defer span.End()
+
+	var req twoFactorCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	if err := h.service.TwoFactor.Disable(ctx, p.UserID, req.Code); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// regenerateRecoveryCodes godoc
+//
+//	@Summary		Regenerate 2FA recovery codes
+//	@Description	Выдаёт новые резервные коды, старые перестают работать.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		twoFactorCodeRequest		true	"Code request"
+//	@Success		200		{object}	DefaultResponse[[]string]	"Резервные коды"
+//	@Failure		400		{object}	DefaultResponse[error]		"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]		"Неверный код"
+//	@Failure		423		{object}	DefaultResponse[error]		"Аккаунт временно заблокирован"
+//	@Failure		500		{object}	DefaultResponse[error]		"Внутренняя ошибка сервера"
+//	@Router			/auth/2fa/recovery-codes [post]
+func (h *httpDelivery) regenerateRecoveryCodes(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.regenerateRecoveryCodes")
+	defer span.End()
+
+	var req twoFactorCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	codes, err := h.service.TwoFactor.RegenerateRecoveryCodes(ctx, p.UserID, req.Code)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[[]string]{
+		Status: "ok",
+		Data:   codes,
+	})
+}
+
+type verifyTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	// Code is a 6-digit TOTP code or a recovery code
+	Code string `json:"code" validate:"required"`
+	// Device is an optional label shown in the session list
+	Device string `json:"device" validate:"max=64"`
+}
+
+type setupTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+}
+
+type twoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
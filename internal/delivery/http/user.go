@@ -1,10 +1,12 @@
 package http
 
 import (
-	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
 	"github.com/podpivasniki1488/assyl-backend/protopb"
 )
 
@@ -14,24 +16,36 @@ func (h *httpDelivery) registerUserHandlers(v1 *echo.Group) {
 	user := v1.Group("/user")
 
 	user.Use(h.registerJWTMiddleware())
-	user.DELETE("", h.deleteUser, h.getJWTData())
+	user.DELETE("", h.deleteUser, h.requirePermission(model.PermUserDelete))
+	user.GET("", h.listUsers, h.requirePermission(model.PermUserList))
+	user.PATCH("/role", h.changeUserRole, h.requirePermission(model.PermUserManage))
+	user.PATCH("/approval", h.changeUserApproval, h.requirePermission(model.PermUserManage))
+	user.GET("/lockouts", h.listLockouts, h.requirePermission(model.PermUserList))
+	user.DELETE("/lockouts", h.clearLockout, h.requirePermission(model.PermUserManage))
 
+	h.registerProfileHandlers(user)
+	h.registerExportHandlers(user)
 }
 
 // deleteUser godoc
 //
 //	@Summary		Delete user (admin/god only)
 //	@Description	Удаляет пользователя по username. Доступно только ролям ADMIN и GOD. Нельзя удалить самого себя.
+//	@Description	mode=delete (по умолчанию) удаляет пользователя вместе с бронями, заявками, отзывами, постами и сообщениями чатов.
+//	@Description	mode=anonymize оставляет историю, но стирает персональные данные пользователя.
+//	@Description	В обоих режимах пользователь выходит из чатов, освобождает квартиры и теряет все сессии. В ответе — отчёт об удалённом.
+//	@Description	messages_pending=true означает, что сообщения чатов ещё не удалены: удаление повторится автоматически.
 //	@Tags			user
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Param			request	body	deleteUserRequest	true	"Delete user request"
-//	@Success		204		"Пользователь удалён"
-//	@Failure		400		{object}	DefaultResponse[error]	"Невалидный запрос"
-//	@Failure		401		{object}	DefaultResponse[error]	"Не авторизован / некорректный токен"
-//	@Failure		403		{object}	DefaultResponse[error]	"Недостаточно прав / попытка удалить себя"
-//	@Failure		500		{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Param			request	body		deleteUserRequest						true	"Delete user request"
+//	@Success		200		{object}	DefaultResponse[model.ErasureReport]	"Отчёт об удалении"
+//	@Failure		400		{object}	DefaultResponse[error]					"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]					"Не авторизован / некорректный токен"
+//	@Failure		403		{object}	DefaultResponse[error]					"Недостаточно прав / попытка удалить себя"
+//	@Failure		404		{object}	DefaultResponse[error]					"Пользователь не найден"
+//	@Failure		500		{object}	DefaultResponse[error]					"Внутренняя ошибка сервера"
 //	@Router			/user [delete]
 func (h *httpDelivery) deleteUser(c echo.Context) error {
 	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.deleteUser")
@@ -42,33 +56,316 @@ func (h *httpDelivery) deleteUser(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 	}
 
-	role, ok := c.Get("role").(string)
-	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("role not found in context"))
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
 	}
 
-	fmt.Println(protopb.Role_ADMIN.String())
-
-	if role != protopb.Role_ADMIN.String() && role != protopb.Role_GOD.String() {
-		return c.JSON(http.StatusForbidden, ErrorResponse("role not allowed"))
+	if req.Mode == "" {
+		req.Mode = model.ErasureModeDelete
 	}
 
-	username, ok := c.Get("username").(string)
+	p, ok := getPrincipal(c)
 	if !ok {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse("username not found in context"))
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
 	}
 
-	if username == req.Username {
+	if p.Username == req.Username {
 		return c.JSON(http.StatusForbidden, ErrorResponse("cannot delete yourself bro)"))
 	}
 
-	if err := h.service.UserManagement.DeleteUserByUsername(ctx, req.Username); err != nil {
+	report, err := h.service.UserManagement.DeleteUserByUsername(ctx, req.Username, req.Mode)
+	if err != nil {
 		return h.handleErrResponse(c, err)
 	}
 
-	return nil
+	return c.JSON(http.StatusOK, DefaultResponse[*model.ErasureReport]{
+		Status: "ok",
+		Data:   report,
+	})
 }
 
 type deleteUserRequest struct {
 	Username string `json:"username" validate:"required"`
+	// Mode is "delete" (default) or "anonymize"
+	Mode model.ErasureMode `json:"mode" validate:"omitempty,oneof=delete anonymize"`
+}
+
+// listUsers godoc
+//
+//	@Summary		List users (admin/god only)
+//	@Description	Возвращает пользователей постранично с фильтрами. Пароли не возвращаются.
+//	@Tags			user
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			page			query		int											false	"Страница (с 1)"
+//	@Param			page_size		query		int											false	"Размер страницы (до 100)"
+//	@Param			role			query		string										false	"Роль (GUEST, INHABITANT, ADMIN, GOD)"
+//	@Param			is_approved		query		bool										false	"Подтверждён ли пользователь"
+//	@Param			is_disabled		query		bool										false	"Отключён ли пользователь администратором"
+//	@Param			apartment_id	query		string										false	"ID квартиры"
+//	@Param			building_id		query		string										false	"ID блока"
+//	@Param			username_type	query		int											false	"Тип username (1 - none, 2 - email, 3 - phone)"
+//	@Param			search			query		string										false	"Поиск по username, имени и фамилии"
+//	@Success		200				{object}	DefaultResponse[model.Page[userResponse]]	"Успех"
+//	@Failure		400				{object}	DefaultResponse[error]						"Невалидный запрос"
+//	@Failure		401				{object}	DefaultResponse[error]						"Не авторизован"
+//	@Failure		403				{object}	DefaultResponse[error]						"Недостаточно прав"
+//	@Failure		500				{object}	DefaultResponse[error]						"Внутренняя ошибка сервера"
+//	@Router			/user [get]
+func (h *httpDelivery) listUsers(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.listUsers")
+	defer span.End()
+
+	var req listUsersRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	filter := model.GetUsersRequest{
+		Pagination: model.Pagination{
+			Page:     req.Page,
+			PageSize: req.PageSize,
+		},
+		IsApproved:   req.IsApproved,
+		IsDisabled:   req.IsDisabled,
+		ApartmentID:  req.ApartmentID,
+		BuildingID:   req.BuildingID,
+		UsernameType: req.UsernameType,
+		Search:       req.Search,
+	}
+
+	if req.Role != "" {
+		val, ok := protopb.Role_value[req.Role]
+		if !ok {
+			return c.JSON(http.StatusBadRequest, ErrorResponse("invalid role"))
+		}
+
+		role := protopb.Role(val)
+		filter.Role = &role
+	}
+
+	res, err := h.service.UserManagement.ListUsers(ctx, filter)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	items := make([]userResponse, 0, len(res.Items))
+	for _, u := range res.Items {
+		items = append(items, newUserResponse(u))
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[model.Page[userResponse]]{
+		Status: "ok",
+		Data: model.Page[userResponse]{
+			Items:    items,
+			Total:    res.Total,
+			Page:     res.Page,
+			PageSize: res.PageSize,
+		},
+	})
+}
+
+// changeUserRole godoc
+//
+//	@Summary		Change user role (admin/god only)
+//	@Description	Меняет роль пользователя. Назначать или снимать роли ADMIN и GOD может только GOD.
+//	@Description	При понижении роли все сессии пользователя завершаются.
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body	changeUserRoleRequest	true	"Change role request"
+//	@Success		204		"Роль изменена"
+//	@Failure		400		{object}	DefaultResponse[error]	"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]	"Не авторизован"
+//	@Failure		403		{object}	DefaultResponse[error]	"Недостаточно прав / попытка изменить себя"
+//	@Failure		404		{object}	DefaultResponse[error]	"Пользователь не найден"
+//	@Failure		500		{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/user/role [patch]
+func (h *httpDelivery) changeUserRole(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.changeUserRole")
+	defer span.End()
+
+	var req changeUserRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	val, ok := protopb.Role_value[req.Role]
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResponse("invalid role"))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	if err := h.service.UserManagement.ChangeRole(ctx, p.UserID, p.Role, req.Username, protopb.Role(val)); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// changeUserApproval godoc
+//
+//	@Summary		Approve or disapprove user (admin/god only)
+//	@Description	is_approved=true подтверждает пользователя и снимает отключение (disabled_at).
+//	@Description	is_approved=false отключает пользователя: все сессии завершаются, войти нельзя, пока администратор
+//	@Description	не подтвердит его снова. Подтверждение кода, сброс пароля и вход через OIDC отключение не снимают.
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body	changeUserApprovalRequest	true	"Change approval request"
+//	@Success		204		"Готово"
+//	@Failure		400		{object}	DefaultResponse[error]	"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]	"Не авторизован"
+//	@Failure		403		{object}	DefaultResponse[error]	"Недостаточно прав / попытка изменить себя"
+//	@Failure		404		{object}	DefaultResponse[error]	"Пользователь не найден"
+//	@Failure		500		{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/user/approval [patch]
+func (h *httpDelivery) changeUserApproval(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.changeUserApproval")
+	defer span.End()
+
+	var req changeUserApprovalRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	p, ok := getPrincipal(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse("principal not found in context"))
+	}
+
+	if err := h.service.UserManagement.SetApproval(ctx, p.UserID, p.Role, req.Username, *req.IsApproved); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// listLockouts godoc
+//
+//	@Summary		List locked accounts (admin/god only)
+//	@Description	Возвращает аккаунты, временно заблокированные после множества неудачных попыток входа.
+//	@Tags			user
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	DefaultResponse[[]model.LoginLockout]	"Успех"
+//	@Failure		401	{object}	DefaultResponse[error]					"Не авторизован"
+//	@Failure		403	{object}	DefaultResponse[error]					"Недостаточно прав"
+//	@Failure		500	{object}	DefaultResponse[error]					"Внутренняя ошибка сервера"
+//	@Router			/user/lockouts [get]
+func (h *httpDelivery) listLockouts(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.listLockouts")
+	defer span.End()
+
+	res, err := h.service.UserManagement.ListLockouts(ctx)
+	if err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DefaultResponse[[]model.LoginLockout]{
+		Status: "ok",
+		Data:   res,
+	})
+}
+
+// clearLockout godoc
+//
+//	@Summary		Clear account lockout (admin/god only)
+//	@Description	Снимает блокировку входа и сбрасывает счётчик неудачных попыток.
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body	clearLockoutRequest	true	"Clear lockout request"
+//	@Success		204		"Блокировка снята"
+//	@Failure		400		{object}	DefaultResponse[error]	"Невалидный запрос"
+//	@Failure		401		{object}	DefaultResponse[error]	"Не авторизован"
+//	@Failure		403		{object}	DefaultResponse[error]	"Недостаточно прав"
+//	@Failure		500		{object}	DefaultResponse[error]	"Внутренняя ошибка сервера"
+//	@Router			/user/lockouts [delete]
+func (h *httpDelivery) clearLockout(c echo.Context) error {
+	ctx, span := h.tracer.Start(c.Request().Context(), "httpDelivery.clearLockout")
+	defer span.End()
+
+	var req clearLockoutRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse(err.Error()))
+	}
+
+	if err := h.service.UserManagement.ClearLockout(ctx, req.Username); err != nil {
+		return h.handleErrResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+type listUsersRequest struct {
+	Page         int       `query:"page"`
+	PageSize     int       `query:"page_size"`
+	Role         string    `query:"role"`
+	IsApproved   *bool     `query:"is_approved"`
+	IsDisabled   *bool     `query:"is_disabled"`
+	ApartmentID  uuid.UUID `query:"apartment_id"`
+	BuildingID   uuid.UUID `query:"building_id"`
+	UsernameType int       `query:"username_type"`
+	Search       string    `query:"search"`
+}
+
+type clearLockoutRequest struct {
+	Username string `json:"username" validate:"required"`
+}
+
+type changeUserRoleRequest struct {
+	Username string `json:"username" validate:"required"`
+	Role     string `json:"role" validate:"required"`
+}
+
+type changeUserApprovalRequest struct {
+	Username   string `json:"username" validate:"required"`
+	IsApproved *bool  `json:"is_approved" validate:"required"`
+}
+
+// userResponse is the public view of model.User, it never includes the password hash
+type userResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	FirstName    string     `json:"first_name"`
+	LastName     string     `json:"last_name"`
+	Username     string     `json:"username"`
+	UsernameType int        `json:"username_type"`
+	IsApproved   bool       `json:"is_approved"`
+	DisabledAt   *time.Time `json:"disabled_at,omitempty"`
+	ApartmentID  uuid.UUID  `json:"apartment_id"`
+	Role         string     `json:"role"`
+}
+
+func newUserResponse(u model.User) userResponse {
+	return userResponse{
+		ID:           u.ID,
+		FirstName:    u.FirstName,
+		LastName:     u.LastName,
+		Username:     u.Username,
+		UsernameType: u.UsernameType,
+		IsApproved:   u.IsApproved,
+		DisabledAt:   u.DisabledAt,
+		ApartmentID:  u.ApartmentID,
+		Role:         u.RoleID.String(),
+	}
 }
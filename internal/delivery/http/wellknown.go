@@ -0,0 +1,35 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/podpivasniki1488/assyl-backend/pkg"
+)
+
+func (h *httpDelivery) registerWellKnownHandlers(v1 *echo.Group) {
+	wellKnown := v1.Group("/.well-known")
+
+	wellKnown.GET("/jwks.json", h.getJWKS)
+}
+
+// getJWKS godoc
+//
+//	@Summary		JSON Web Key Set
+//	@Description	Публичные ключи для проверки access токенов другими сервисами (RFC 7517).
+//	@Description	В режиме HS256 список ключей пуст.
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	pkg.JWKS	"Набор ключей"
+//	@Router			/.well-known/jwks.json [get]
+func (h *httpDelivery) getJWKS(c echo.Context) error {
+	_, span := h.tracer.Start(c.Request().Context(), "httpDelivery.getJWKS")
+	defer span.End()
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+
+	// served as is, not wrapped in DefaultResponse: clients expect the standard JWKS format
+	var res pkg.JWKS = h.jwtKeys.JWKS()
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -1,14 +1,128 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Apartment struct {
-	Id         uuid.UUID  `gorm:"type:uuid;not null;default:gen_random_uuid()" json:"id"`
-	Floor      uint8      `gorm:"not null;uniqueIndex:idx_floor_door" json:"floor"`
-	DoorNumber uint16     `gorm:"not null;uniqueIndex:idx_floor_door" json:"door_number"`
+	Id uuid.UUID `gorm:"type:uuid;not null;default:gen_random_uuid()" json:"id"`
+	// EntranceID is empty only for rows created before buildings existed, they are backfilled on start
+	EntranceID *uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_entrance_floor_door,priority:1" json:"entrance_id"`
+	Floor      uint8      `gorm:"not null;uniqueIndex:idx_entrance_floor_door,priority:2" json:"floor"`
+	DoorNumber uint16     `gorm:"not null;uniqueIndex:idx_entrance_floor_door,priority:3" json:"door_number"`
 	OwnerId    *uuid.UUID `gorm:"type:uuid;constraint:OnDelete:SET NULL;" json:"owner_id,omitempty"`
+	// Area is the total area in m², 0 while unknown
+	Area            float64       `gorm:"type:numeric(8,2);not null;default:0" json:"area"`
+	Rooms           uint8         `gorm:"not null;default:0" json:"rooms"`
+	CadastralNumber string        `gorm:"type:varchar;not null;default:'';uniqueIndex:idx_apartment_cadastral,where:cadastral_number <> ''" json:"cadastral_number"`
+	Type            ApartmentType `gorm:"type:varchar;not null;default:'residential'" json:"type"`
+	StorageUnit     string        `gorm:"type:varchar;not null;default:''" json:"storage_unit"`
 }
 
 func (a *Apartment) TableName() string {
 	return "apartments"
 }
+
+type ApartmentType string
+
+const (
+	ApartmentTypeResidential ApartmentType = "residential"
+	ApartmentTypeCommercial  ApartmentType = "commercial"
+)
+
+func (t ApartmentType) IsValid() bool {
+	return t == ApartmentTypeResidential || t == ApartmentTypeCommercial
+}
+
+// cadastralNumberRe matches numbers like 20:317:016:123:1/45, groups of digits separated by colons
+// with an optional part after a slash.
+var cadastralNumberRe = regexp.MustCompile(`^\d{2}(:\d{1,6}){2,5}(/[0-9A-Za-z-]{1,10})?$`)
+
+func ValidCadastralNumber(s string) bool {
+	return cadastralNumberRe.MatchString(s)
+}
+
+// ApartmentOwnership is a period during which a user owned an apartment, EndedAt is empty for the current owner.
+type ApartmentOwnership struct {
+	ID          uuid.UUID  `gorm:"primary_key;type:uuid;default:gen_random_uuid()" json:"id"`
+	ApartmentID uuid.UUID  `gorm:"type:uuid;not null;index" json:"apartment_id"`
+	OwnerID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"owner_id"`
+	StartedAt   time.Time  `gorm:"type:timestamp;not null" json:"started_at"`
+	EndedAt     *time.Time `gorm:"type:timestamp" json:"ended_at,omitempty"`
+}
+
+func (a *ApartmentOwnership) TableName() string {
+	return "apartment_ownership_history"
+}
+
+type GetApartmentsRequest struct {
+	Pagination
+	BuildingID uuid.UUID
+	// Entrance is the entrance number, in any building unless BuildingID is set
+	Entrance   uint8
+	Floor      *uint8
+	DoorNumber *uint16
+	// Bound filters by whether the apartment has an owner
+	Bound   *bool
+	OwnerID uuid.UUID
+	// WithResidents adds the approved household members to every apartment
+	WithResidents bool
+}
+
+// ApartmentDetails is an apartment together with the people living in it.
+type ApartmentDetails struct {
+	Apartment
+	BuildingID uuid.UUID           `json:"building_id"`
+	Building   string              `json:"building"`
+	Entrance   uint8               `json:"entrance"`
+	Residents  []ApartmentResident `json:"residents,omitempty"`
+}
+
+type ApartmentResident struct {
+	ApartmentID uuid.UUID  `json:"-"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Username    string     `json:"username"`
+	FirstName   string     `json:"first_name"`
+	LastName    string     `json:"last_name"`
+	Role        MemberRole `json:"role"`
+}
+
+// UpdateApartmentRequest changes only the fields that are set. The apartment moves to another
+// entrance when BuildingID or Entrance is set.
+type UpdateApartmentRequest struct {
+	BuildingID      uuid.UUID
+	Entrance        uint8
+	Floor           *uint8
+	DoorNumber      *uint16
+	Area            *float64
+	Rooms           *uint8
+	CadastralNumber *string
+	Type            *ApartmentType
+	StorageUnit     *string
+}
+
+type ApartmentAuditAction string
+
+const (
+	ApartmentAuditUpdate ApartmentAuditAction = "update"
+	ApartmentAuditDelete ApartmentAuditAction = "delete"
+	ApartmentAuditUnbind ApartmentAuditAction = "unbind"
+)
+
+// ApartmentAudit records an administrative change of an apartment. Entries outlive the apartment.
+type ApartmentAudit struct {
+	ID          uuid.UUID            `gorm:"primary_key;type:uuid;default:gen_random_uuid()" json:"id"`
+	ApartmentID uuid.UUID            `gorm:"type:uuid;not null;index" json:"apartment_id"`
+	ActorID     uuid.UUID            `gorm:"type:uuid;not null" json:"actor_id"`
+	Action      ApartmentAuditAction `gorm:"type:varchar;not null" json:"action"`
+	// Details is a human readable description of the change, e.g. "floor: 3 -> 4"
+	Details   string    `gorm:"type:varchar;not null" json:"details"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null" json:"created_at"`
+}
+
+func (a *ApartmentAudit) TableName() string {
+	return "apartment_audit_log"
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BindingStatus string
+
+const (
+	BindingStatusPending  BindingStatus = "pending"
+	BindingStatusApproved BindingStatus = "approved"
+	BindingStatusRejected BindingStatus = "rejected"
+)
+
+func (s BindingStatus) IsValid() bool {
+	switch s {
+	case BindingStatusPending, BindingStatusApproved, BindingStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// BindingRequest is a user's claim to own an apartment. An admin reviews it, usually by looking at
+// the proof document, and only an approved request makes the user the owner.
+type BindingRequest struct {
+	ID          uuid.UUID     `gorm:"primary_key;type:uuid;default:gen_random_uuid()" json:"id"`
+	ApartmentID uuid.UUID     `gorm:"type:uuid;not null;index;uniqueIndex:idx_binding_pending,where:status = 'pending'" json:"apartment_id"`
+	UserID      uuid.UUID     `gorm:"type:uuid;not null;index;uniqueIndex:idx_binding_pending,where:status = 'pending'" json:"user_id"`
+	Status      BindingStatus `gorm:"type:varchar;not null;default:'pending';index" json:"status"`
+	Comment     string        `gorm:"type:varchar;not null;default:''" json:"comment,omitempty"`
+	HasProof    bool          `gorm:"type:boolean;not null;default:false" json:"has_proof"`
+	// Reason is why the request was rejected
+	Reason     string     `gorm:"type:varchar;not null;default:''" json:"reason,omitempty"`
+	ReviewedBy *uuid.UUID `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `gorm:"type:timestamp" json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"type:timestamp;not null" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"type:timestamp;not null" json:"updated_at"`
+}
+
+func (b *BindingRequest) TableName() string {
+	return "apartment_binding_requests"
+}
+
+// BindingDocument is the proof attached to a binding request, e.g. a scan of the ownership certificate.
+// It is kept apart from the request so that listing requests does not load the files.
+type BindingDocument struct {
+	RequestID   uuid.UUID `gorm:"primary_key;type:uuid" json:"request_id"`
+	FileName    string    `gorm:"type:varchar;not null" json:"file_name"`
+	ContentType string    `gorm:"type:varchar;not null" json:"content_type"`
+	Data        []byte    `gorm:"type:bytea;not null" json:"-"`
+	CreatedAt   time.Time `gorm:"type:timestamp;not null" json:"created_at"`
+}
+
+func (b *BindingDocument) TableName() string {
+	return "apartment_binding_documents"
+}
+
+type CreateBindingRequest struct {
+	ApartmentID uuid.UUID
+	Comment     string
+	// Proof is optional
+	Proof *BindingDocument
+}
+
+type GetBindingRequestsRequest struct {
+	Pagination
+	Status      BindingStatus
+	ApartmentID uuid.UUID
+	UserID      uuid.UUID
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultBuildingName is the building created for a complex that has none yet, and the one
+// apartments made before buildings existed are moved to.
+const DefaultBuildingName = "1"
+
+// Building is a block of the residential complex.
+type Building struct {
+	ID        uuid.UUID `gorm:"primary_key;type:uuid;default:gen_random_uuid()" json:"id"`
+	Name      string    `gorm:"type:varchar;not null;uniqueIndex" json:"name"`
+	Address   string    `gorm:"type:varchar;not null;default:''" json:"address"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null" json:"created_at"`
+}
+
+func (b *Building) TableName() string {
+	return "buildings"
+}
+
+type Entrance struct {
+	ID         uuid.UUID `gorm:"primary_key;type:uuid;default:gen_random_uuid()" json:"id"`
+	BuildingID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_building_entrance" json:"building_id"`
+	Number     uint8     `gorm:"not null;uniqueIndex:idx_building_entrance" json:"number"`
+	CreatedAt  time.Time `gorm:"type:timestamp;not null" json:"created_at"`
+}
+
+func (e *Entrance) TableName() string {
+	return "entrances"
+}
+
+// EntranceFilter matches entrances by building and number, zero values match any.
+type EntranceFilter struct {
+	BuildingID   uuid.UUID
+	BuildingName string
+	Number       uint8
+}
+
+// ApartmentAddress locates an apartment in the complex. Building and entrance may be left out
+// as long as they are unambiguous, e.g. while the complex has a single building with one entrance.
+type ApartmentAddress struct {
+	BuildingID   uuid.UUID
+	BuildingName string
+	Entrance     uint8
+	Floor        uint8
+	DoorNumber   uint16
+}
+
+type BuildingDetails struct {
+	Building
+	Entrances []Entrance `json:"entrances"`
+}
@@ -7,11 +7,13 @@ import (
 )
 
 type ChannelMessage struct {
-	Id        uuid.UUID `gorm:"type:uuid;not null;default:gen_random_uuid()" json:"id"`
-	AuthorId  uuid.UUID `gorm:"type:uuid;not null" json:"author_id"`
-	Text      string    `gorm:"type:varchar;not null" json:"text"`
-	CreatedAt time.Time `gorm:"type:timestamp;not null" json:"created_at"`
-	UpdatedAt time.Time `gorm:"type:timestamp;not null" json:"updated_at"`
+	Id       uuid.UUID `gorm:"type:uuid;not null;default:gen_random_uuid()" json:"id"`
+	AuthorId uuid.UUID `gorm:"type:uuid;not null" json:"author_id"`
+	Text     string    `gorm:"type:varchar;not null" json:"text"`
+	// BuildingID limits the announcement to one building, nil means the whole complex
+	BuildingID *uuid.UUID `gorm:"type:uuid;index" json:"building_id,omitempty"`
+	CreatedAt  time.Time  `gorm:"type:timestamp;not null" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"type:timestamp;not null" json:"updated_at"`
 }
 
 func (ChannelMessage) TableName() string {
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ErasureMode string
+
+const (
+	// ErasureModeAnonymize keeps reservations, feedback, orders and chat history, but turns the user
+	// into a tombstone without any personal data, so the history stays consistent.
+	ErasureModeAnonymize ErasureMode = "anonymize"
+	// ErasureModeDelete removes the user together with everything the user authored.
+	ErasureModeDelete ErasureMode = "delete"
+)
+
+func (m ErasureMode) IsValid() bool {
+	switch m {
+	case ErasureModeAnonymize, ErasureModeDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// ErasureReport tells how many rows and documents an account erasure touched.
+// In ErasureModeAnonymize the counters mean "anonymized" for kept records and "removed" for the rest.
+type ErasureReport struct {
+	UserID             uuid.UUID   `json:"user_id"`
+	Mode               ErasureMode `json:"mode"`
+	Reservations       int64       `json:"reservations"`
+	ChatParticipants   int64       `json:"chat_participants"`
+	Feedbacks          int64       `json:"feedbacks"`
+	Orders             int64       `json:"orders"`
+	ChannelMessages    int64       `json:"channel_messages"`
+	Messages           int64       `json:"messages"`
+	ApartmentsReleased int64       `json:"apartments_released"`
+	Memberships        int64       `json:"memberships"`
+	BindingRequests    int64       `json:"binding_requests"`
+	Invitations        int64       `json:"invitations"`
+	Identities         int64       `json:"identities"`
+	RecoveryCodes      int64       `json:"recovery_codes"`
+	SessionsRevoked    bool        `json:"sessions_revoked"`
+	// MessagesPending means the chat messages could not be deleted yet, the cleanup is retried later
+	MessagesPending bool `json:"messages_pending"`
+}
+
+// ErasureJob is a chat message cleanup left after the user was deleted in Postgres. Mongo is not
+// part of the Postgres transaction, so the job is committed together with the erasure and removed
+// once the messages are gone.
+type ErasureJob struct {
+	UserID    uuid.UUID `gorm:"primary_key;type:uuid" json:"user_id"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null" json:"created_at"`
+}
+
+func (j *ErasureJob) TableName() string {
+	return "erasure_jobs"
+}
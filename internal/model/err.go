@@ -35,17 +35,80 @@ var (
 	ErrRecordNotFound = AppError{HttpStatusCode: http.StatusNotFound, Message: "record not found"}
 	ErrInvalidInput   = AppError{HttpStatusCode: http.StatusBadRequest, Message: "invalid input"}
 
+	ErrPermissionDenied = AppError{HttpStatusCode: http.StatusForbidden, Message: "permission denied"}
+	ErrSelfModification = AppError{HttpStatusCode: http.StatusForbidden, Message: "cannot change your own role or approval"}
+	ErrInvalidRole      = AppError{HttpStatusCode: http.StatusBadRequest, Message: "invalid role"}
+
 	ErrPasswordMatch     = AppError{HttpStatusCode: http.StatusUnauthorized, Message: "invalid username or password"}
 	ErrUserNotFound      = AppError{HttpStatusCode: http.StatusNotFound, Message: "user not found"}
 	ErrUserAlreadyExists = AppError{HttpStatusCode: http.StatusBadRequest, Message: "user already exists"}
 	ErrUserNotApproved   = AppError{HttpStatusCode: http.StatusUnauthorized, Message: "user not approved"}
+	ErrUserApproved      = AppError{HttpStatusCode: http.StatusConflict, Message: "user already approved"}
+	ErrUserDisabled      = AppError{HttpStatusCode: http.StatusForbidden, Message: "user disabled by an administrator"}
+	ErrCurrentPassword   = AppError{HttpStatusCode: http.StatusBadRequest, Message: "current password is not correct"}
+	ErrInvalidUsername   = AppError{HttpStatusCode: http.StatusBadRequest, Message: "username must be an email or a phone number"}
+	ErrLoginThrottled    = AppError{HttpStatusCode: http.StatusTooManyRequests, Message: "too many login attempts, try again later"}
+	ErrLoginLocked       = AppError{HttpStatusCode: http.StatusLocked, Message: "account is temporarily locked after too many failed logins"}
+
+	ErrTwoFactorInvalid           = AppError{HttpStatusCode: http.StatusUnauthorized, Message: "invalid two-factor code"}
+	ErrTwoFactorChallenge         = AppError{HttpStatusCode: http.StatusUnauthorized, Message: "two-factor challenge not found or expired"}
+	ErrTwoFactorNotEnabled        = AppError{HttpStatusCode: http.StatusBadRequest, Message: "two-factor authentication is not enabled"}
+	ErrTwoFactorAlreadyEnabled    = AppError{HttpStatusCode: http.StatusConflict, Message: "two-factor authentication is already enabled"}
+	ErrTwoFactorEnrollmentExpired = AppError{HttpStatusCode: http.StatusBadRequest, Message: "two-factor enrollment not started or expired"}
+	ErrTwoFactorEnforced          = AppError{HttpStatusCode: http.StatusForbidden, Message: "two-factor authentication is mandatory for this role"}
+
+	ErrOIDCUnknownProvider = AppError{HttpStatusCode: http.StatusNotFound, Message: "unknown identity provider"}
+	ErrOIDCInvalidState    = AppError{HttpStatusCode: http.StatusBadRequest, Message: "oidc login state not found or expired"}
+	ErrOIDCFailed          = AppError{HttpStatusCode: http.StatusUnauthorized, Message: "identity provider login failed"}
+
+	ErrInvalidRefreshToken = AppError{HttpStatusCode: http.StatusUnauthorized, Message: "invalid or expired refresh token"}
+	ErrSessionRevoked      = AppError{HttpStatusCode: http.StatusUnauthorized, Message: "session revoked"}
+	ErrSessionNotFound     = AppError{HttpStatusCode: http.StatusNotFound, Message: "session not found"}
+
+	ErrExportNotFound = AppError{HttpStatusCode: http.StatusNotFound, Message: "export not found or expired"}
+	ErrExportNotReady = AppError{HttpStatusCode: http.StatusConflict, Message: "export is not ready yet"}
+
+	ErrOtpNotFound      = AppError{HttpStatusCode: http.StatusBadRequest, Message: "otp code not found or expired"}
+	ErrOtpInvalid       = AppError{HttpStatusCode: http.StatusBadRequest, Message: "otp code is not correct"}
+	ErrOtpLocked        = AppError{HttpStatusCode: http.StatusTooManyRequests, Message: "too many otp attempts, try again later"}
+	ErrOtpCooldown      = AppError{HttpStatusCode: http.StatusTooManyRequests, Message: "otp code was sent recently, try again later"}
+	ErrUnknownOtpTarget = AppError{HttpStatusCode: http.StatusBadRequest, Message: "username is neither email nor phone"}
 
 	ErrApartmentNotFound     = AppError{HttpStatusCode: http.StatusNotFound, Message: "allocation not found"}
 	ErrApartmentAlreadyBound = AppError{HttpStatusCode: http.StatusConflict, Message: "apartment already bound"}
+	ErrApartmentExists       = AppError{HttpStatusCode: http.StatusConflict, Message: "apartment with this address or cadastral number already exists"}
+	ErrInvalidApartment      = AppError{HttpStatusCode: http.StatusBadRequest, Message: "invalid apartment attributes"}
+	ErrInvalidLayout         = AppError{HttpStatusCode: http.StatusBadRequest, Message: "invalid apartment layout"}
+	ErrApartmentBound        = AppError{HttpStatusCode: http.StatusConflict, Message: "apartment has an owner or residents, unbind it first"}
+	ErrAddressAmbiguous      = AppError{HttpStatusCode: http.StatusBadRequest, Message: "building and entrance are required to locate the apartment"}
+	ErrBuildingNotFound      = AppError{HttpStatusCode: http.StatusNotFound, Message: "building not found"}
+	ErrBuildingExists        = AppError{HttpStatusCode: http.StatusConflict, Message: "building with this name already exists"}
+	ErrBuildingNotEmpty      = AppError{HttpStatusCode: http.StatusConflict, Message: "building still has apartments"}
+	ErrEntranceNotFound      = AppError{HttpStatusCode: http.StatusNotFound, Message: "entrance not found"}
+	ErrEntranceExists        = AppError{HttpStatusCode: http.StatusConflict, Message: "entrance with this number already exists in the building"}
+	ErrEntranceNotEmpty      = AppError{HttpStatusCode: http.StatusConflict, Message: "entrance still has apartments"}
+	ErrBindingNotFound       = AppError{HttpStatusCode: http.StatusNotFound, Message: "binding request not found"}
+	ErrBindingExists         = AppError{HttpStatusCode: http.StatusConflict, Message: "binding request for this apartment is already pending"}
+	ErrBindingNotPending     = AppError{HttpStatusCode: http.StatusConflict, Message: "binding request is already reviewed"}
+	ErrBindingProofNotFound  = AppError{HttpStatusCode: http.StatusNotFound, Message: "binding request has no proof document"}
+	ErrBindingProofInvalid   = AppError{HttpStatusCode: http.StatusBadRequest, Message: "proof must be a PDF, JPEG or PNG file"}
+	ErrMemberNotFound        = AppError{HttpStatusCode: http.StatusNotFound, Message: "apartment member not found"}
+	ErrMemberAlreadyExists   = AppError{HttpStatusCode: http.StatusConflict, Message: "user is already a member of the apartment"}
+	ErrMemberNotPending      = AppError{HttpStatusCode: http.StatusConflict, Message: "membership request is not pending"}
+	ErrMemberIsOwner         = AppError{HttpStatusCode: http.StatusConflict, Message: "the owner can not be removed from the apartment"}
+	ErrInvalidMemberRole     = AppError{HttpStatusCode: http.StatusBadRequest, Message: "invalid member role"}
+	ErrNotApartmentMember    = AppError{HttpStatusCode: http.StatusForbidden, Message: "user is not an approved member of an apartment"}
+	ErrImportFile            = AppError{HttpStatusCode: http.StatusBadRequest, Message: "could not read the import file"}
+	ErrInvitationNotFound    = AppError{HttpStatusCode: http.StatusNotFound, Message: "invitation not found"}
+	ErrInvitationInvalid     = AppError{HttpStatusCode: http.StatusBadRequest, Message: "invitation code is invalid, expired or used up"}
 	ErrReservationNotFound   = AppError{HttpStatusCode: http.StatusNotFound, Message: "record not found"}
 	ErrCinemaBusy            = AppError{HttpStatusCode: http.StatusConflict, Message: "cinema busy"}
 	ErrTooManyPeople         = AppError{HttpStatusCode: http.StatusBadRequest, Message: "too many people"}
 	ErrReservationImpossible = AppError{HttpStatusCode: http.StatusBadRequest, Message: "reservation impossible"}
+	ErrSlotTemplateNotFound  = AppError{HttpStatusCode: http.StatusNotFound, Message: "slot template not found"}
+	ErrSlotTemplateExists    = AppError{HttpStatusCode: http.StatusConflict, Message: "slot template with this code or position already exists"}
+	ErrSlotTemplateOverlap   = AppError{HttpStatusCode: http.StatusConflict, Message: "slot template overlaps another active template"}
+	ErrInvalidSlotTime       = AppError{HttpStatusCode: http.StatusBadRequest, Message: "invalid slot time, expected HH:MM and end after start"}
 	ErrAdminsCannotBeDeleted = AppError{HttpStatusCode: http.StatusForbidden, Message: "admins can not be deleted"}
 
 	ErrChatNotFound         = AppError{HttpStatusCode: http.StatusNotFound, Message: "chat not found"}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ExportStatus string
+
+const (
+	ExportStatusPending ExportStatus = "pending"
+	ExportStatusReady   ExportStatus = "ready"
+	ExportStatusFailed  ExportStatus = "failed"
+)
+
+// ExportJob is a personal data export that is built in the background.
+type ExportJob struct {
+	ID          uuid.UUID    `json:"id"`
+	UserID      uuid.UUID    `json:"user_id"`
+	RequestedBy uuid.UUID    `json:"requested_by"`
+	Status      ExportStatus `json:"status"`
+	Error       string       `json:"error,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time    `json:"expires_at"`
+	// DownloadURL is set once the archive is ready
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// ExportResult holds either the archive, for small exports, or the background job building it.
+type ExportResult struct {
+	Archive []byte
+	Job     *ExportJob
+}
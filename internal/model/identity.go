@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links a user to an account at an external OpenID Connect provider.
+type UserIdentity struct {
+	ID        uuid.UUID `gorm:"primary_key;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Provider  string    `gorm:"type:varchar;not null;uniqueIndex:idx_identity_provider_subject" json:"provider"`
+	Subject   string    `gorm:"type:varchar;not null;uniqueIndex:idx_identity_provider_subject" json:"subject"`
+	Email     string    `gorm:"type:varchar" json:"email"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null" json:"created_at"`
+}
+
+func (i *UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+// ExternalIdentity is what a provider asserted about the user in a verified ID token.
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Phone         string
+	PhoneVerified bool
+	FirstName     string
+	LastName      string
+}
@@ -0,0 +1,75 @@
+package model
+
+import "github.com/google/uuid"
+
+type ImportFormat string
+
+const (
+	ImportFormatCSV  ImportFormat = "csv"
+	ImportFormatXLSX ImportFormat = "xlsx"
+)
+
+type ImportOptions struct {
+	// DryRun only reports what would change
+	DryRun bool
+	// SendInvitations tells newly created residents how to activate their account
+	SendInvitations bool
+}
+
+// ImportRow is one line of the spreadsheet. Resident columns are optional: a row without
+// a username only makes sure the apartment exists. Building and entrance may be left out while
+// the complex has only one of them.
+type ImportRow struct {
+	Line     int
+	Building string
+	Entrance uint8
+	// EntranceID is resolved from building and entrance before anything is applied
+	EntranceID uuid.UUID
+	Floor      uint8
+	DoorNumber uint16
+	FirstName  string
+	LastName   string
+	Username   string
+	Role       MemberRole
+}
+
+type ImportAction string
+
+const (
+	ImportActionCreate ImportAction = "create"
+	ImportActionUpdate ImportAction = "update"
+	ImportActionInvite ImportAction = "invite"
+)
+
+// ImportChange is one line of the diff: something the import created or changed, or would in a dry run.
+type ImportChange struct {
+	Line   int          `json:"line"`
+	Entity string       `json:"entity"`
+	Action ImportAction `json:"action"`
+	Key    string       `json:"key"`
+	Detail string       `json:"detail,omitempty"`
+}
+
+type ImportRowError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+type ImportCounts struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+// ImportReport describes an import. When there are row errors nothing is applied, fix the file and retry.
+type ImportReport struct {
+	DryRun          bool             `json:"dry_run"`
+	Applied         bool             `json:"applied"`
+	Rows            int              `json:"rows"`
+	Apartments      ImportCounts     `json:"apartments"`
+	Residents       ImportCounts     `json:"residents"`
+	Memberships     ImportCounts     `json:"memberships"`
+	InvitationsSent int              `json:"invitations_sent"`
+	Changes         []ImportChange   `json:"changes"`
+	Errors          []ImportRowError `json:"errors"`
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invitation lets a new resident join an apartment without an admin binding them by hand.
+// Only a hash of the code is stored, the code itself is shown once to the creator.
+type Invitation struct {
+	ID          uuid.UUID  `gorm:"primary_key;type:uuid;default:gen_random_uuid()" json:"id"`
+	CodeHash    string     `gorm:"type:varchar;not null;uniqueIndex" json:"-"`
+	ApartmentID uuid.UUID  `gorm:"type:uuid;not null;index" json:"apartment_id"`
+	CreatedBy   uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	MaxUses     int        `gorm:"type:int;not null" json:"max_uses"`
+	UsedCount   int        `gorm:"type:int;not null;default:0" json:"used_count"`
+	ExpiresAt   *time.Time `gorm:"type:timestamp" json:"expires_at,omitempty"`
+	RevokedAt   *time.Time `gorm:"type:timestamp" json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"type:timestamp;not null" json:"created_at"`
+	// MemberRole is the household role the invited user gets, never owner
+	MemberRole MemberRole `gorm:"type:varchar;not null;default:'family'" json:"member_role"`
+}
+
+func (i *Invitation) TableName() string {
+	return "apartment_invitations"
+}
+
+// Usable reports whether the invitation can still be redeemed at t.
+func (i *Invitation) Usable(t time.Time) bool {
+	if i.RevokedAt != nil || i.UsedCount >= i.MaxUses {
+		return false
+	}
+
+	return i.ExpiresAt == nil || t.Before(*i.ExpiresAt)
+}
+
+type CreateInvitationRequest struct {
+	ApartmentID uuid.UUID
+	MemberRole  MemberRole
+	MaxUses     int
+	// TTL of zero means the invitation does not expire
+	TTL time.Duration
+}
+
+type CreatedInvitation struct {
+	Invitation
+	// Code is returned only once, when the invitation is created
+	Code string `json:"code"`
+}
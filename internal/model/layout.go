@@ -0,0 +1,55 @@
+package model
+
+import "github.com/google/uuid"
+
+// MaxLayoutApartments limits the size of one layout, a single entrance never comes close.
+const MaxLayoutApartments = 2000
+
+// ApartmentLayout describes the apartments of one entrance: every floor from FromFloor to ToFloor
+// has doors FromDoor to ToDoor unless a floor exception says otherwise. Building and entrance may
+// be left out while the complex has only one of them.
+type ApartmentLayout struct {
+	BuildingID uuid.UUID     `json:"building_id"`
+	Entrance   uint8         `json:"entrance"`
+	FromFloor  uint8         `json:"from_floor"`
+	ToFloor    uint8         `json:"to_floor"`
+	FromDoor   uint16        `json:"from_door"`
+	ToDoor     uint16        `json:"to_door"`
+	Exceptions []LayoutFloor `json:"exceptions"`
+}
+
+// LayoutFloor overrides the doors of one floor. Skip leaves the floor without apartments,
+// e.g. a commercial ground floor.
+type LayoutFloor struct {
+	Floor    uint8  `json:"floor"`
+	FromDoor uint16 `json:"from_door"`
+	ToDoor   uint16 `json:"to_door"`
+	Skip     bool   `json:"skip"`
+}
+
+type LayoutOptions struct {
+	// DryRun only reports what would change
+	DryRun bool
+	// Prune deletes apartments of the entrance that are not in the layout, bound ones are never deleted
+	Prune bool
+}
+
+// LayoutApartment is an apartment of the report, ID is empty for apartments not created yet.
+type LayoutApartment struct {
+	ID         *uuid.UUID `json:"id,omitempty"`
+	Floor      uint8      `json:"floor"`
+	DoorNumber uint16     `json:"door_number"`
+}
+
+// LayoutReport describes a layout run. Extra apartments are in the entrance but not in the layout,
+// with Prune they are deleted. If some of them are bound, they are listed in Bound and nothing is applied.
+type LayoutReport struct {
+	DryRun     bool              `json:"dry_run"`
+	Applied    bool              `json:"applied"`
+	EntranceID uuid.UUID         `json:"entrance_id"`
+	Created    []LayoutApartment `json:"created"`
+	Existing   []LayoutApartment `json:"existing"`
+	Extra      []LayoutApartment `json:"extra"`
+	Deleted    []LayoutApartment `json:"deleted"`
+	Bound      []LayoutApartment `json:"bound"`
+}
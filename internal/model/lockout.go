@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+type LoginLockout struct {
+	Username    string    `json:"username"`
+	Failures    int       `json:"failures"`
+	LastIP      string    `json:"last_ip"`
+	LockedAt    time.Time `json:"locked_at"`
+	LockedUntil time.Time `json:"locked_until"`
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MemberRole string
+
+const (
+	MemberRoleOwner   MemberRole = "owner"
+	MemberRoleCoOwner MemberRole = "co_owner"
+	MemberRoleTenant  MemberRole = "tenant"
+	MemberRoleFamily  MemberRole = "family"
+)
+
+func (r MemberRole) IsValid() bool {
+	switch r {
+	case MemberRoleOwner, MemberRoleCoOwner, MemberRoleTenant, MemberRoleFamily:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanManage reports whether the role may approve, change and remove other members of the household.
+func (r MemberRole) CanManage() bool {
+	return r == MemberRoleOwner || r == MemberRoleCoOwner
+}
+
+type MemberStatus string
+
+const (
+	MemberStatusPending  MemberStatus = "pending"
+	MemberStatusApproved MemberStatus = "approved"
+	MemberStatusRejected MemberStatus = "rejected"
+)
+
+// ApartmentMember links a user to the household of an apartment. Only approved members count
+// for reservation quotas, may place orders and, as owners or co-owners, vote.
+//
+// Apartment.OwnerId and User.ApartmentID are kept in sync with it: OwnerId is the member with
+// MemberRoleOwner and ApartmentID points at the apartment the user was approved in. A user is an
+// approved member of one household, approval in another apartment removes the previous membership.
+type ApartmentMember struct {
+	ID          uuid.UUID    `gorm:"primary_key;type:uuid;default:gen_random_uuid()" json:"id"`
+	ApartmentID uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_apartment_member" json:"apartment_id"`
+	UserID      uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_apartment_member;index" json:"user_id"`
+	Role        MemberRole   `gorm:"type:varchar;not null" json:"role"`
+	Status      MemberStatus `gorm:"type:varchar;not null;index" json:"status"`
+	ApprovedBy  *uuid.UUID   `gorm:"type:uuid" json:"approved_by,omitempty"`
+	CreatedAt   time.Time    `gorm:"type:timestamp;not null" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"type:timestamp;not null" json:"updated_at"`
+}
+
+func (m *ApartmentMember) TableName() string {
+	return "apartment_members"
+}
+
+func (m *ApartmentMember) IsApproved() bool {
+	return m.Status == MemberStatusApproved
+}
+
+// CanVote reports whether the member has a vote in household decisions: approved owners and co-owners.
+func (m *ApartmentMember) CanVote() bool {
+	return m.IsApproved() && m.Role.CanManage()
+}
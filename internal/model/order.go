@@ -10,6 +10,8 @@ type Order struct {
 	OrderType protopb.OrderType `gorm:"type:smallint;not null" json:"order_type"`
 	UserID    uuid.UUID         `gorm:"type:uuid;not null" json:"user_id"`
 	Text      string            `gorm:"type:varchar;not null" json:"text"`
+	// ApartmentID is the household the order was placed for
+	ApartmentID *uuid.UUID `gorm:"type:uuid;index" json:"apartment_id,omitempty"`
 }
 
 func (Order) TableName() string {
@@ -0,0 +1,21 @@
+package model
+
+type OtpPurpose string
+
+const (
+	OtpPurposeRegistration  OtpPurpose = "registration"
+	OtpPurposePasswordReset OtpPurpose = "password_reset"
+	OtpPurposeLogin         OtpPurpose = "login"
+	// OtpPurposeUsernameChange codes are keyed by user id, not by username, and are not resendable:
+	// starting the change again sends a new code
+	OtpPurposeUsernameChange OtpPurpose = "username_change"
+)
+
+func (p OtpPurpose) IsValid() bool {
+	switch p {
+	case OtpPurposeRegistration, OtpPurposePasswordReset, OtpPurposeLogin:
+		return true
+	default:
+		return false
+	}
+}
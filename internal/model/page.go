@@ -0,0 +1,39 @@
+package model
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+type Page[T any] struct {
+	Items    []T   `json:"items"`
+	Total    int64 `json:"total"`
+	Page     int   `json:"page"`
+	PageSize int   `json:"page_size"`
+}
+
+type Pagination struct {
+	Page     int
+	PageSize int
+}
+
+// Normalize clamps page to >= 1 and page size to (0, MaxPageSize].
+func (p Pagination) Normalize() Pagination {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+
+	if p.PageSize <= 0 {
+		p.PageSize = DefaultPageSize
+	}
+
+	if p.PageSize > MaxPageSize {
+		p.PageSize = MaxPageSize
+	}
+
+	return p
+}
+
+func (p Pagination) Offset() int {
+	return (p.Page - 1) * p.PageSize
+}
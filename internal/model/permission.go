@@ -0,0 +1,73 @@
+package model
+
+import "github.com/podpivasniki1488/assyl-backend/protopb"
+
+type Permission string
+
+const (
+	PermReservationApprove Permission = "reservation.approve"
+	PermReservationViewAll Permission = "reservation.view_all"
+	PermSlotManage         Permission = "slot.manage"
+	PermApartmentCreate    Permission = "apartment.create"
+	PermApartmentBindAny   Permission = "apartment.bind_any"
+	PermBindingReview      Permission = "apartment.bind_review"
+	PermApartmentImport    Permission = "apartment.import"
+	PermApartmentManage    Permission = "apartment.manage"
+	PermBuildingManage     Permission = "building.manage"
+	PermChannelPost        Permission = "channel.post"
+	PermFeedbackView       Permission = "feedback.view"
+	PermOrderViewAll       Permission = "order.view_all"
+	PermUserDelete         Permission = "user.delete"
+	PermUserList           Permission = "user.list"
+	PermUserManage         Permission = "user.manage"
+	PermUserExport         Permission = "user.export"
+	// PermApartmentMembersManage allows managing members of any apartment, owners and co-owners manage only their own
+	PermApartmentMembersManage Permission = "apartment.members_manage"
+	// PermInvitationManage allows inviting to any apartment, owners may invite only to their own
+	PermInvitationManage Permission = "invitation.manage"
+	// PermUserManageAdmins allows granting/revoking ADMIN and GOD roles and touching such accounts
+	PermUserManageAdmins Permission = "user.manage_admins"
+)
+
+var adminPermissions = []Permission{
+	PermReservationApprove,
+	PermReservationViewAll,
+	PermSlotManage,
+	PermApartmentCreate,
+	PermApartmentBindAny,
+	PermBindingReview,
+	PermApartmentImport,
+	PermApartmentManage,
+	PermBuildingManage,
+	PermApartmentMembersManage,
+	PermInvitationManage,
+	PermChannelPost,
+	PermFeedbackView,
+	PermOrderViewAll,
+	PermUserDelete,
+	PermUserList,
+	PermUserManage,
+	PermUserExport,
+}
+
+// rolePermissions is the single source of truth for what each role may do.
+// Actions available to every authenticated user are not listed here.
+var rolePermissions = map[protopb.Role]map[Permission]bool{
+	protopb.Role_GUEST:      permissionSet(),
+	protopb.Role_INHABITANT: permissionSet(),
+	protopb.Role_ADMIN:      permissionSet(adminPermissions...),
+	protopb.Role_GOD:        permissionSet(append(adminPermissions, PermUserManageAdmins)...),
+}
+
+func permissionSet(perms ...Permission) map[Permission]bool {
+	res := make(map[Permission]bool, len(perms))
+	for _, p := range perms {
+		res[p] = true
+	}
+
+	return res
+}
+
+func HasPermission(role protopb.Role, perm Permission) bool {
+	return rolePermissions[role][perm]
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/podpivasniki1488/assyl-backend/protopb"
+)
+
+var (
+	adminAndGod = []protopb.Role{protopb.Role_ADMIN, protopb.Role_GOD}
+	godOnly     = []protopb.Role{protopb.Role_GOD}
+)
+
+// permissionMatrix lists the roles that have each permission, every other role is denied.
+// GUEST and INHABITANT were never allowed any of these actions before the matrix existed.
+var permissionMatrix = map[Permission][]protopb.Role{
+	PermReservationApprove:     adminAndGod,
+	PermReservationViewAll:     adminAndGod,
+	PermSlotManage:             adminAndGod,
+	PermApartmentCreate:        adminAndGod,
+	PermApartmentBindAny:       adminAndGod,
+	PermBindingReview:          adminAndGod,
+	PermApartmentImport:        adminAndGod,
+	PermApartmentManage:        adminAndGod,
+	PermBuildingManage:         adminAndGod,
+	PermChannelPost:            adminAndGod,
+	PermFeedbackView:           adminAndGod,
+	PermOrderViewAll:           adminAndGod,
+	PermUserDelete:             adminAndGod,
+	PermUserList:               adminAndGod,
+	PermUserManage:             adminAndGod,
+	PermUserExport:             adminAndGod,
+	PermApartmentMembersManage: adminAndGod,
+	PermInvitationManage:       adminAndGod,
+	PermUserManageAdmins:       godOnly,
+}
+
+var allRoles = []protopb.Role{
+	protopb.Role_STATUS_UNSPECIFIED,
+	protopb.Role_GUEST,
+	protopb.Role_INHABITANT,
+	protopb.Role_ADMIN,
+	protopb.Role_GOD,
+}
+
+func TestHasPermission(t *testing.T) {
+	for perm, allowed := range permissionMatrix {
+		for _, role := range allRoles {
+			want := false
+			for _, r := range allowed {
+				want = want || r == role
+			}
+
+			t.Run(role.String()+"/"+string(perm), func(t *testing.T) {
+				if got := HasPermission(role, perm); got != want {
+					t.Errorf("HasPermission(%s, %s) = %v, want %v", role, perm, got, want)
+				}
+			})
+		}
+	}
+}
+
+// TestPermissionMatrixComplete fails when a role is granted a permission the matrix does not know,
+// so a new permission cannot be added without deciding here who has it.
+func TestPermissionMatrixComplete(t *testing.T) {
+	for role, perms := range rolePermissions {
+		for perm := range perms {
+			if _, ok := permissionMatrix[perm]; !ok {
+				t.Errorf("%s has %s, which is missing from permissionMatrix", role, perm)
+			}
+		}
+	}
+}
+
+func TestHasPermissionGuest(t *testing.T) {
+	if n := len(rolePermissions[protopb.Role_GUEST]); n != 0 {
+		t.Errorf("GUEST has %d permissions, want none", n)
+	}
+}
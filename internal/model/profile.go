@@ -0,0 +1,38 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// Profile is the user's own view of the account. It never carries the password hash or 2FA secrets.
+type Profile struct {
+	ID           uuid.UUID         `json:"id"`
+	FirstName    string            `json:"first_name"`
+	LastName     string            `json:"last_name"`
+	Username     string            `json:"username"`
+	UsernameType int               `json:"username_type"`
+	AvatarURL    string            `json:"avatar_url,omitempty"`
+	IsApproved   bool              `json:"is_approved"`
+	Role         string            `json:"role"`
+	TotpEnabled  bool              `json:"totp_enabled"`
+	Apartment    *ProfileApartment `json:"apartment,omitempty"`
+}
+
+type ProfileApartment struct {
+	ID         uuid.UUID `json:"id"`
+	Building   string    `json:"building"`
+	Entrance   uint8     `json:"entrance"`
+	Floor      uint8     `json:"floor"`
+	DoorNumber uint16    `json:"door_number"`
+	IsOwner    bool      `json:"is_owner"`
+	// MemberRole is empty when the user is not an approved member of the household
+	MemberRole MemberRole `json:"member_role,omitempty"`
+	CanVote    bool       `json:"can_vote"`
+}
+
+// UpdateProfileRequest changes only the fields that are set. An empty AvatarURL removes the avatar.
+type UpdateProfileRequest struct {
+	FirstName *string
+	LastName  *string
+	AvatarURL *string
+}
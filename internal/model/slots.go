@@ -9,7 +9,7 @@ import (
 type SlotTemplate struct {
 	ID        int16  `gorm:"primaryKey;type:smallserial" json:"id"`
 	Code      string `gorm:"type:text;uniqueIndex;not null" json:"code"`
-	StartTime string `gorm:"type:time;not null" json:"start_time"` // можно time.Time, но аккуратно с датой; проще string "10:00:00"
+	StartTime string `gorm:"type:time;not null" json:"start_time"` // время дня в таймзоне кинотеатра, "10:00:00"
 	EndTime   string `gorm:"type:time;not null" json:"end_time"`
 	Position  int16  `gorm:"type:smallint;uniqueIndex;not null" json:"position"`
 	IsActive  bool   `gorm:"type:boolean;not null;default:true" json:"is_active"`
@@ -17,6 +17,57 @@ type SlotTemplate struct {
 
 func (SlotTemplate) TableName() string { return "slot_templates" }
 
+// ParseSlotTime parses a time of day, "10:00" or "10:00:00", into the offset from midnight.
+func ParseSlotTime(s string) (time.Duration, error) {
+	t, err := time.Parse(time.TimeOnly, s)
+	if err != nil {
+		if t, err = time.Parse("15:04", s); err != nil {
+			return 0, ErrInvalidSlotTime.WithErr(err)
+		}
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, nil
+}
+
+// VenueDay returns midnight of the calendar day of date in the venue timezone tz. Dates come from
+// the API as calendar days, so the clock and location of date are ignored.
+func VenueDay(date time.Time, tz *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, tz)
+}
+
+// SlotAt returns the moment of the offset on the day of date in tz.
+func SlotAt(date time.Time, offset time.Duration, tz *time.Location) time.Time {
+	y, m, d := date.In(tz).Date()
+	return time.Date(y, m, d, int(offset/time.Hour), int(offset%time.Hour/time.Minute), int(offset%time.Minute/time.Second), 0, tz)
+}
+
+// UpdateSlotTemplateRequest changes only the fields that are set.
+type UpdateSlotTemplateRequest struct {
+	Code      *string
+	StartTime *string
+	EndTime   *string
+	Position  *int16
+	IsActive  *bool
+}
+
+// SlotTemplateChange reports how a template change reached the daily slots that have not started yet.
+// Reserved slots keep their times, so existing reservations stay as they were booked.
+type SlotTemplateChange struct {
+	Template SlotTemplate `json:"template"`
+	// UpdatedSlots got the new times or position
+	UpdatedSlots int64 `json:"updated_slots"`
+	// RemovedSlots were free slots of a deleted or deactivated template
+	RemovedSlots int64 `json:"removed_slots"`
+	// ReservedSlots are reserved and keep the times they were booked for
+	ReservedSlots int64 `json:"reserved_slots"`
+	// DisabledSlots are free slots whose new times overlap a reservation of the day, they are not offered
+	DisabledSlots int64 `json:"disabled_slots"`
+	// ConflictDates are the days of DisabledSlots
+	ConflictDates []time.Time `json:"conflict_dates,omitempty"`
+}
+
+// DailySlot is a template on a given day. There is one per day and template, the unique index is
+// created by the slot repository's backfill.
 type DailySlot struct {
 	ID         int64     `gorm:"primaryKey;type:bigserial" json:"id"`
 	SlotDate   time.Time `gorm:"type:date;not null;index:ix_daily_slots_date" json:"slot_date"`
@@ -0,0 +1,166 @@
+package model
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func almaty(t *testing.T) *time.Location {
+	t.Helper()
+
+	tz, err := time.LoadLocation("Asia/Almaty")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tz
+}
+
+func TestVenueDay(t *testing.T) {
+	tz := almaty(t)
+	utc5 := time.FixedZone("UTC+5", 5*60*60)
+
+	tests := []struct {
+		name string
+		date time.Time
+		want time.Time
+	}{
+		{
+			name: "date from the API",
+			date: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 3, 9, 19, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "one minute before local midnight",
+			date: time.Date(2026, 3, 10, 23, 59, 0, 0, tz),
+			want: time.Date(2026, 3, 9, 19, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "local midnight",
+			date: time.Date(2026, 3, 11, 0, 0, 0, 0, tz),
+			want: time.Date(2026, 3, 10, 19, 0, 0, 0, time.UTC),
+		},
+		{
+			// the calendar day of the value is kept, not the day of the instant in the venue zone
+			name: "instant seen from UTC",
+			date: time.Date(2026, 3, 10, 19, 30, 0, 0, time.UTC),
+			want: time.Date(2026, 3, 9, 19, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "same instant seen from UTC+5",
+			date: time.Date(2026, 3, 11, 0, 30, 0, 0, utc5),
+			want: time.Date(2026, 3, 10, 19, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := VenueDay(tt.date, tz)
+			if !got.Equal(tt.want) {
+				t.Errorf("VenueDay(%v) = %v, want %v", tt.date, got.UTC(), tt.want)
+			}
+
+			if got.Location() != tz {
+				t.Errorf("VenueDay(%v) is in %v, want %v", tt.date, got.Location(), tz)
+			}
+		})
+	}
+}
+
+func TestSlotAt(t *testing.T) {
+	tz := almaty(t)
+	utc5 := time.FixedZone("UTC+5", 5*60*60)
+
+	tests := []struct {
+		name   string
+		date   time.Time
+		offset time.Duration
+		want   time.Time
+	}{
+		{
+			name:   "last minute of the day",
+			date:   time.Date(2026, 3, 10, 0, 0, 0, 0, tz),
+			offset: 23*time.Hour + 59*time.Minute,
+			want:   time.Date(2026, 3, 10, 18, 59, 0, 0, time.UTC),
+		},
+		{
+			name:   "midnight",
+			date:   time.Date(2026, 3, 11, 0, 0, 0, 0, tz),
+			offset: 0,
+			want:   time.Date(2026, 3, 10, 19, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "seconds",
+			date:   time.Date(2026, 3, 10, 0, 0, 0, 0, tz),
+			offset: 10*time.Hour + 30*time.Minute + 15*time.Second,
+			want:   time.Date(2026, 3, 10, 5, 30, 15, 0, time.UTC),
+		},
+		{
+			// the day is taken from the instant in the venue zone, whatever zone it is seen from
+			name:   "instant seen from UTC",
+			date:   time.Date(2026, 3, 10, 19, 30, 0, 0, time.UTC),
+			offset: 10 * time.Hour,
+			want:   time.Date(2026, 3, 11, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "same instant seen from UTC+5",
+			date:   time.Date(2026, 3, 11, 0, 30, 0, 0, utc5),
+			offset: 10 * time.Hour,
+			want:   time.Date(2026, 3, 11, 5, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SlotAt(tt.date, tt.offset, tz)
+			if !got.Equal(tt.want) {
+				t.Errorf("SlotAt(%v, %v) = %v, want %v", tt.date, tt.offset, got.UTC(), tt.want)
+			}
+
+			if got.Location() != tz {
+				t.Errorf("SlotAt(%v, %v) is in %v, want %v", tt.date, tt.offset, got.Location(), tz)
+			}
+		})
+	}
+}
+
+func TestSlotAtVenueDay(t *testing.T) {
+	tz := almaty(t)
+
+	// a slot placed on a venue day stays on that day in the venue zone, even when it ends at 23:59
+	day := VenueDay(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), tz)
+	for _, offset := range []time.Duration{0, 23*time.Hour + 59*time.Minute} {
+		y, m, d := SlotAt(day, offset, tz).In(tz).Date()
+		if y != 2026 || m != time.March || d != 10 {
+			t.Errorf("SlotAt(%v, %v) is on %d-%02d-%02d, want 2026-03-10", day, offset, y, m, d)
+		}
+	}
+}
+
+func TestParseSlotTime(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "10:00", want: 10 * time.Hour},
+		{in: "23:59:59", want: 23*time.Hour + 59*time.Minute + 59*time.Second},
+		{in: "00:00:00", want: 0},
+		{in: "24:00", wantErr: true},
+		{in: "10", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSlotTime(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSlotTime(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseSlotTime(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorStep tells the client what is left to finish the login after the password was accepted.
+type TwoFactorStep string
+
+const (
+	// TwoFactorStepVerify means a TOTP or recovery code must be sent with the challenge token
+	TwoFactorStepVerify TwoFactorStep = "verify"
+	// TwoFactorStepSetup means the role requires 2FA but the user has not enrolled yet
+	TwoFactorStepSetup TwoFactorStep = "setup"
+)
+
+type LoginResult struct {
+	Tokens *TokenPair
+	User   *User
+	// TwoFactor is set instead of Tokens when the second factor is still required
+	TwoFactor      TwoFactorStep
+	ChallengeToken string
+	// RecoveryCodes are returned once, when enrollment was completed during login
+	RecoveryCodes []string
+}
+
+type TotpEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"primary_key;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"type:varchar;not null" json:"-"`
+	UsedAt    *time.Time `gorm:"type:timestamp" json:"used_at"`
+	CreatedAt time.Time  `gorm:"type:timestamp;not null" json:"created_at"`
+}
+
+func (r *RecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
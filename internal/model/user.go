@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/podpivasniki1488/assyl-backend/protopb"
 )
@@ -11,12 +13,35 @@ type User struct {
 	LastName     string       `gorm:"type:varchar;not null" json:"last_name"`
 	Username     string       `gorm:"type:varchar;not null;uniqueIndex:idx_user_username" json:"username"`
 	UsernameType int          `gorm:"type:int;not null" default:"1" json:"username_type"`
-	Password     string       `gorm:"type:varchar;not null" json:"password"`
+	Password     string       `gorm:"type:varchar;not null" json:"-"`
 	IsApproved   bool         `gorm:"type:boolean;not null" default:"false" json:"is_approved"`
 	ApartmentID  uuid.UUID    `gorm:"type:uuid;OnDelete:SET NULL" json:"apartment_id"`
 	RoleID       protopb.Role `gorm:"type:smallint;not null" json:"role_id"`
+	TotpSecret   string       `gorm:"type:varchar" json:"-"`
+	TotpEnabled  bool         `gorm:"type:boolean;not null;default:false" json:"totp_enabled"`
+	InvitedBy    *uuid.UUID   `gorm:"type:uuid" json:"invited_by,omitempty"`
+	AvatarURL    string       `gorm:"type:varchar" json:"avatar_url,omitempty"`
+	// DisabledAt is set by an admin and cleared only by an admin. IsApproved only says the username
+	// was confirmed, the user's own flows (confirm, password reset, OIDC) may set it.
+	DisabledAt *time.Time `gorm:"type:timestamptz" json:"disabled_at,omitempty"`
 }
 
 func (u *User) TableName() string {
 	return "users"
 }
+
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+type GetUsersRequest struct {
+	Pagination
+	Role         *protopb.Role
+	IsApproved   *bool
+	IsDisabled   *bool
+	ApartmentID  uuid.UUID
+	BuildingID   uuid.UUID
+	UsernameType int
+	// Search matches username, first or last name
+	Search string
+}
@@ -3,6 +3,7 @@ package apartment
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
@@ -30,6 +31,7 @@ func (a *apartment) CreateApartment(ctx context.Context, req model.Apartment) (*
 	var res model.Apartment
 	query := a.db.
 		WithContext(ctx).
+		Where("entrance_id = ?", req.EntranceID).
 		Where("floor = ?", req.Floor).
 		Where("door_number = ?", req.DoorNumber).
 		First(&res)
@@ -50,14 +52,19 @@ func (a *apartment) CreateApartment(ctx context.Context, req model.Apartment) (*
 	return &res, nil
 }
 
-func (a *apartment) GetApartmentByFloorAndNum(ctx context.Context, floor uint8, doorNum uint16) (*model.Apartment, error) {
-	ctx, span := a.tracer.Start(ctx, "apartmentRepo.GetApartment")
+func (a *apartment) GetApartmentByAddress(
+	ctx context.Context,
+	entranceID uuid.UUID,
+	floor uint8,
+	doorNum uint16,
+) (*model.Apartment, error) {
+	ctx, span := a.tracer.Start(ctx, "apartmentRepo.GetApartmentByAddress")
 	defer span.End()
 
 	var res model.Apartment
 	query := a.db.
 		WithContext(ctx).
-		Where("floor = ? AND door_number = ?", floor, doorNum).
+		Where("entrance_id = ? AND floor = ? AND door_number = ?", entranceID, floor, doorNum).
 		First(&res)
 
 	if query.Error != nil {
@@ -96,14 +103,272 @@ func (a *apartment) UpdateApartment(ctx context.Context, updatedApp *model.Apart
 	ctx, span := a.tracer.Start(ctx, "apartmentRepo.UpdateApartment")
 	defer span.End()
 
-	findApp := a.db.
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if updatedApp.OwnerId != nil {
+			if _, err := SetOwner(tx, updatedApp.Id, updatedApp.OwnerId, nil); err != nil {
+				return err
+			}
+		}
+
+		// the owner is already saved, Omit keeps Updates from writing it without the history
+		if err := tx.
+			Model(&model.Apartment{}).
+			Where("id = ?", updatedApp.Id).
+			Omit("owner_id").
+			Updates(updatedApp).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return model.ErrApartmentExists
+			}
+
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		return nil
+	})
+}
+
+func (a *apartment) FindByFilters(ctx context.Context, req *model.GetApartmentsRequest) ([]model.Apartment, int64, error) {
+	ctx, span := a.tracer.Start(ctx, "apartmentRepo.FindByFilters")
+	defer span.End()
+
+	query := a.db.WithContext(ctx).Model(&model.Apartment{})
+
+	if req.BuildingID != uuid.Nil {
+		query = query.Where("entrance_id IN (SELECT id FROM entrances WHERE building_id = ?)", req.BuildingID)
+	}
+
+	if req.Entrance != 0 {
+		query = query.Where("entrance_id IN (SELECT id FROM entrances WHERE number = ?)", req.Entrance)
+	}
+
+	if req.Floor != nil {
+		query = query.Where("floor = ?", *req.Floor)
+	}
+
+	if req.DoorNumber != nil {
+		query = query.Where("door_number = ?", *req.DoorNumber)
+	}
+
+	if req.Bound != nil {
+		if *req.Bound {
+			query = query.Where("owner_id IS NOT NULL")
+		} else {
+			query = query.Where("owner_id IS NULL")
+		}
+	}
+
+	if req.OwnerID != uuid.Nil {
+		query = query.Where("owner_id = ?", req.OwnerID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	page := req.Pagination.Normalize()
+
+	var res []model.Apartment
+	if err := query.
+		Order("floor asc, door_number asc").
+		Offset(page.Offset()).
+		Limit(page.PageSize).
+		Find(&res).Error; err != nil {
+		return nil, 0, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return res, total, nil
+}
+
+func (a *apartment) FindResidents(ctx context.Context, apartmentIDs []uuid.UUID) ([]model.ApartmentResident, error) {
+	ctx, span := a.tracer.Start(ctx, "apartmentRepo.FindResidents")
+	defer span.End()
+
+	var res []model.ApartmentResident
+	if len(apartmentIDs) == 0 {
+		return res, nil
+	}
+
+	if err := a.db.
 		WithContext(ctx).
-		Where("id = ?", updatedApp.Id).
-		Updates(updatedApp)
+		Table("apartment_members m").
+		Select("m.apartment_id, m.user_id, u.username, u.first_name, u.last_name, m.role").
+		Joins("JOIN users u ON u.id = m.user_id").
+		Where("m.apartment_id IN ? AND m.status = ?", apartmentIDs, model.MemberStatusApproved).
+		Order("m.created_at").
+		Scan(&res).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return res, nil
+}
 
-	if findApp.Error != nil {
-		return model.ErrDBUnexpected.WithErr(findApp.Error)
+func (a *apartment) ChangeApartment(ctx context.Context, id uuid.UUID, fields map[string]any, audit *model.ApartmentAudit) error {
+	ctx, span := a.tracer.Start(ctx, "apartmentRepo.ChangeApartment")
+	defer span.End()
+
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Model(&model.Apartment{}).
+			Where("id = ?", id).
+			Updates(fields).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return model.ErrApartmentExists
+			}
+
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		if err := tx.Create(audit).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		return nil
+	})
+}
+
+func (a *apartment) DeleteApartment(ctx context.Context, id uuid.UUID, audit *model.ApartmentAudit) error {
+	ctx, span := a.tracer.Start(ctx, "apartmentRepo.DeleteApartment")
+	defer span.End()
+
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var bound int64
+		if err := tx.
+			Model(&model.ApartmentMember{}).
+			Where("apartment_id = ? AND status = ?", id, model.MemberStatusApproved).
+			Count(&bound).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		if bound > 0 {
+			return model.ErrApartmentBound
+		}
+
+		// owner_id IS NULL guards against a bind that happened after the check above
+		res := tx.
+			Where("id = ? AND owner_id IS NULL", id).
+			Delete(&model.Apartment{})
+		if res.Error != nil {
+			return model.ErrDBUnexpected.WithErr(res.Error)
+		}
+
+		if res.RowsAffected == 0 {
+			return model.ErrApartmentBound
+		}
+
+		if err := deleteDependents(tx, []uuid.UUID{id}); err != nil {
+			return err
+		}
+
+		if err := tx.Create(audit).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		return nil
+	})
+}
+
+// deleteDependents removes what refers to deleted apartments: memberships, invitations and binding
+// requests with their documents, a pending request could not be approved anymore.
+func deleteDependents(tx *gorm.DB, apartmentIDs []uuid.UUID) error {
+	requests := tx.Model(&model.BindingRequest{}).Select("id").Where("apartment_id IN ?", apartmentIDs)
+
+	if err := tx.
+		Where("request_id IN (?)", requests).
+		Delete(&model.BindingDocument{}).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	for _, dependent := range []any{&model.BindingRequest{}, &model.ApartmentMember{}, &model.Invitation{}} {
+		if err := tx.
+			Where("apartment_id IN ?", apartmentIDs).
+			Delete(dependent).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
 	}
 
 	return nil
 }
+
+func (a *apartment) Unbind(ctx context.Context, id uuid.UUID, audit *model.ApartmentAudit) ([]uuid.UUID, error) {
+	ctx, span := a.tracer.Start(ctx, "apartmentRepo.Unbind")
+	defer span.End()
+
+	var userIDs []uuid.UUID
+
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Model(&model.User{}).
+			Where("apartment_id = ?", id).
+			Pluck("id", &userIDs).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		if err := tx.
+			Model(&model.User{}).
+			Where("apartment_id = ?", id).
+			Update("apartment_id", uuid.Nil).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		if _, err := SetOwner(tx, id, nil, nil); err != nil {
+			return err
+		}
+
+		if err := tx.
+			Where("apartment_id = ?", id).
+			Delete(&model.ApartmentMember{}).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		if err := tx.
+			Model(&model.Invitation{}).
+			Where("apartment_id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		if err := tx.Create(audit).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+func (a *apartment) FindAudit(
+	ctx context.Context,
+	apartmentID uuid.UUID,
+	page model.Pagination,
+) ([]model.ApartmentAudit, int64, error) {
+	ctx, span := a.tracer.Start(ctx, "apartmentRepo.FindAudit")
+	defer span.End()
+
+	query := a.db.
+		WithContext(ctx).
+		Model(&model.ApartmentAudit{}).
+		Where("apartment_id = ?", apartmentID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	page = page.Normalize()
+
+	var res []model.ApartmentAudit
+	if err := query.
+		Order("created_at desc").
+		Offset(page.Offset()).
+		Limit(page.PageSize).
+		Find(&res).Error; err != nil {
+		return nil, 0, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return res, total, nil
+}
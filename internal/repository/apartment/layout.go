@@ -0,0 +1,92 @@
+package apartment
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (a *apartment) FindByEntrance(ctx context.Context, entranceID uuid.UUID) ([]model.Apartment, error) {
+	ctx, span := a.tracer.Start(ctx, "apartmentRepo.FindByEntrance")
+	defer span.End()
+
+	var res []model.Apartment
+	if err := a.db.
+		WithContext(ctx).
+		Where("entrance_id = ?", entranceID).
+		Order("floor, door_number").
+		Find(&res).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return res, nil
+}
+
+func (a *apartment) ApplyLayout(
+	ctx context.Context,
+	create []model.Apartment,
+	deleteIDs []uuid.UUID,
+	audit []model.ApartmentAudit,
+) error {
+	ctx, span := a.tracer.Start(ctx, "apartmentRepo.ApplyLayout")
+	defer span.End()
+
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(create) > 0 {
+			// an apartment created concurrently at the same address is left as it is
+			if err := tx.
+				Clauses(clause.OnConflict{
+					Columns:   []clause.Column{{Name: "entrance_id"}, {Name: "floor"}, {Name: "door_number"}},
+					DoNothing: true,
+				}).
+				CreateInBatches(create, 500).Error; err != nil {
+				return model.ErrDBUnexpected.WithErr(err)
+			}
+		}
+
+		if len(deleteIDs) == 0 {
+			return nil
+		}
+
+		var bound int64
+		if err := tx.
+			Model(&model.Apartment{}).
+			Where("id IN ?", deleteIDs).
+			Where("owner_id IS NOT NULL OR EXISTS (?)", tx.
+				Model(&model.ApartmentMember{}).
+				Select("1").
+				Where("apartment_members.apartment_id = apartments.id AND apartment_members.status = ?", model.MemberStatusApproved)).
+			Count(&bound).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		if bound > 0 {
+			return model.ErrApartmentBound
+		}
+
+		// owner_id IS NULL guards against a bind that happened after the check above
+		res := tx.
+			Where("id IN ? AND owner_id IS NULL", deleteIDs).
+			Delete(&model.Apartment{})
+		if res.Error != nil {
+			return model.ErrDBUnexpected.WithErr(res.Error)
+		}
+
+		if res.RowsAffected != int64(len(deleteIDs)) {
+			return model.ErrApartmentBound
+		}
+
+		if err := deleteDependents(tx, deleteIDs); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&audit).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		return nil
+	})
+}
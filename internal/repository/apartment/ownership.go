@@ -0,0 +1,177 @@
+package apartment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// backfillOwnershipQuery opens a history period for owners bound before the history existed.
+// Their real start is unknown, the period starts at the first run.
+const backfillOwnershipQuery = `
+INSERT INTO apartment_ownership_history (apartment_id, owner_id, started_at)
+SELECT a.id, a.owner_id, now()
+FROM apartments a
+WHERE a.owner_id IS NOT NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM apartment_ownership_history h
+    WHERE h.apartment_id = a.id AND h.ended_at IS NULL
+  )`
+
+// backfillOrphanQueries drop binding requests and their documents left behind by apartments
+// deleted before the requests were deleted with them.
+var backfillOrphanQueries = []string{`
+DELETE FROM apartment_binding_documents d
+WHERE NOT EXISTS (
+  SELECT 1 FROM apartment_binding_requests r
+  JOIN apartments a ON a.id = r.apartment_id
+  WHERE r.id = d.request_id
+)`, `
+DELETE FROM apartment_binding_requests r
+WHERE NOT EXISTS (SELECT 1 FROM apartments a WHERE a.id = r.apartment_id)`,
+}
+
+// Backfill is safe to run on every start.
+func Backfill(db *gorm.DB) error {
+	if err := db.Exec(backfillOwnershipQuery).Error; err != nil {
+		return err
+	}
+
+	for _, q := range backfillOrphanQueries {
+		if err := db.Exec(q).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OwnerCond decides from the current owner, nil if there is none, whether SetOwner may proceed.
+type OwnerCond func(current *uuid.UUID) bool
+
+// Unowned lets SetOwner proceed only for an apartment without an owner.
+func Unowned(current *uuid.UUID) bool {
+	return current == nil
+}
+
+// OwnedBy lets SetOwner proceed only while userID is the owner.
+func OwnedBy(userID uuid.UUID) OwnerCond {
+	return func(current *uuid.UUID) bool {
+		return current != nil && *current == userID
+	}
+}
+
+// LockOwner locks the apartment row until the caller's transaction ends and returns its owner,
+// nil if there is none. Decisions that depend on the owner are made under this lock.
+func LockOwner(tx *gorm.DB, apartmentID uuid.UUID) (*uuid.UUID, error) {
+	var current model.Apartment
+	if err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "owner_id").
+		Where("id = ?", apartmentID).
+		First(&current).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrApartmentNotFound
+		}
+
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return current.OwnerId, nil
+}
+
+// SetOwner changes Apartment.OwnerId, nil releases the apartment, and records the change in
+// apartment_ownership_history. Every owner change goes through it, inside the caller's transaction;
+// the apartment row stays locked until the transaction ends. With cond set the owner is changed only
+// while cond holds. It reports whether the owner changed.
+func SetOwner(tx *gorm.DB, apartmentID uuid.UUID, ownerID *uuid.UUID, cond OwnerCond) (bool, error) {
+	currentOwner, err := LockOwner(tx, apartmentID)
+	if err != nil {
+		return false, err
+	}
+
+	if cond != nil && !cond(currentOwner) {
+		return false, nil
+	}
+
+	if sameOwner(currentOwner, ownerID) {
+		return false, nil
+	}
+
+	if err := tx.
+		Model(&model.Apartment{}).
+		Where("id = ?", apartmentID).
+		Update("owner_id", ownerID).Error; err != nil {
+		return false, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	now := time.Now()
+
+	if err := tx.
+		Model(&model.ApartmentOwnership{}).
+		Where("apartment_id = ? AND ended_at IS NULL", apartmentID).
+		Update("ended_at", now).Error; err != nil {
+		return false, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	if ownerID == nil {
+		return true, nil
+	}
+
+	if err := tx.Create(&model.ApartmentOwnership{
+		ApartmentID: apartmentID,
+		OwnerID:     *ownerID,
+		StartedAt:   now,
+	}).Error; err != nil {
+		return false, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return true, nil
+}
+
+// ReleaseOwner releases every apartment the user owns and closes their history periods.
+// It returns the number of apartments released.
+func ReleaseOwner(tx *gorm.DB, userID uuid.UUID) (int64, error) {
+	if err := tx.
+		Model(&model.ApartmentOwnership{}).
+		Where("owner_id = ? AND ended_at IS NULL", userID).
+		Update("ended_at", time.Now()).Error; err != nil {
+		return 0, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	res := tx.Model(&model.Apartment{}).Where("owner_id = ?", userID).Update("owner_id", nil)
+	if res.Error != nil {
+		return 0, model.ErrDBUnexpected.WithErr(res.Error)
+	}
+
+	return res.RowsAffected, nil
+}
+
+func sameOwner(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+func (a *apartment) FindOwnershipHistory(ctx context.Context, apartmentID uuid.UUID) ([]model.ApartmentOwnership, error) {
+	ctx, span := a.tracer.Start(ctx, "apartmentRepo.FindOwnershipHistory")
+	defer span.End()
+
+	var res []model.ApartmentOwnership
+	if err := a.db.
+		WithContext(ctx).
+		Where("apartment_id = ?", apartmentID).
+		Order("started_at desc").
+		Find(&res).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return res, nil
+}
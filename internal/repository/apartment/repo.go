@@ -8,8 +8,30 @@ import (
 )
 
 type ApartmentRepo interface {
+	// CreateApartment returns the apartment at the same entrance, floor and door number if there is one
 	CreateApartment(ctx context.Context, req model.Apartment) (*model.Apartment, error)
-	GetApartmentByFloorAndNum(ctx context.Context, floor uint8, doorNum uint16) (*model.Apartment, error)
+	GetApartmentByAddress(ctx context.Context, entranceID uuid.UUID, floor uint8, doorNum uint16) (*model.Apartment, error)
 	GetApartmentByID(ctx context.Context, id uuid.UUID) (*model.Apartment, error)
+	// UpdateApartment saves the non-zero fields, a new OwnerId is recorded in the ownership history
 	UpdateApartment(ctx context.Context, updatedApp *model.Apartment) error
+	FindByFilters(ctx context.Context, req *model.GetApartmentsRequest) ([]model.Apartment, int64, error)
+	// FindResidents returns approved members of the apartments with their names
+	FindResidents(ctx context.Context, apartmentIDs []uuid.UUID) ([]model.ApartmentResident, error)
+	// ChangeApartment updates the given fields and writes the audit entry in one transaction.
+	// ErrApartmentExists if another apartment already has the address.
+	ChangeApartment(ctx context.Context, id uuid.UUID, fields map[string]any, audit *model.ApartmentAudit) error
+	// DeleteApartment removes an unbound apartment together with its pending requests and invitations.
+	// ErrApartmentBound if it has an owner or approved residents.
+	DeleteApartment(ctx context.Context, id uuid.UUID, audit *model.ApartmentAudit) error
+	// Unbind clears Apartment.OwnerId and User.ApartmentID of every resident, drops the memberships
+	// and revokes open invitations. It returns the ids of the users that were unbound.
+	Unbind(ctx context.Context, id uuid.UUID, audit *model.ApartmentAudit) ([]uuid.UUID, error)
+	FindAudit(ctx context.Context, apartmentID uuid.UUID, page model.Pagination) ([]model.ApartmentAudit, int64, error)
+	FindByEntrance(ctx context.Context, entranceID uuid.UUID) ([]model.Apartment, error)
+	// ApplyLayout creates the apartments, skipping addresses that already exist, and deletes the given ones
+	// with their pending requests and invitations in one transaction. ErrApartmentBound if any of the
+	// deleted apartments has an owner or approved residents, then nothing is changed.
+	ApplyLayout(ctx context.Context, create []model.Apartment, deleteIDs []uuid.UUID, audit []model.ApartmentAudit) error
+	// FindOwnershipHistory returns the ownership periods of the apartment, the latest first
+	FindOwnershipHistory(ctx context.Context, apartmentID uuid.UUID) ([]model.ApartmentOwnership, error)
 }
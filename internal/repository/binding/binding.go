@@ -0,0 +1,306 @@
+package binding
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/apartment"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/member"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+type bindingRepository struct {
+	db     *gorm.DB
+	tracer trace.Tracer
+	debug  bool
+}
+
+func NewBindingRepository(db *gorm.DB, debug bool) BindingRepo {
+	return &bindingRepository{
+		db:     db,
+		tracer: otel.Tracer("bindingRepository"),
+		debug:  debug,
+	}
+}
+
+func (r *bindingRepository) Create(ctx context.Context, req *model.BindingRequest, doc *model.BindingDocument) error {
+	ctx, span := r.tracer.Start(ctx, "bindingRepository.Create")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	if r.debug {
+		db = db.Debug()
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		return create(tx, req, doc)
+	})
+}
+
+func (r *bindingRepository) CreateApproved(
+	ctx context.Context,
+	req *model.BindingRequest,
+	doc *model.BindingDocument,
+	rejectReason string,
+) ([]model.BindingRequest, error) {
+	ctx, span := r.tracer.Start(ctx, "bindingRepository.CreateApproved")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	if r.debug {
+		db = db.Debug()
+	}
+
+	var rejected []model.BindingRequest
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// stored as pending first, so the approval goes through the same checks as a reviewed one
+		pending := *req
+		pending.Status = model.BindingStatusPending
+		pending.Reason = ""
+		pending.ReviewedBy = nil
+		pending.ReviewedAt = nil
+
+		if err := create(tx, &pending, doc); err != nil {
+			return err
+		}
+
+		req.ID = pending.ID
+		req.HasProof = pending.HasProof
+
+		var err error
+		rejected, err = approve(tx, req, rejectReason)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rejected, nil
+}
+
+func create(tx *gorm.DB, req *model.BindingRequest, doc *model.BindingDocument) error {
+	req.HasProof = doc != nil
+
+	if err := tx.Create(req).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return model.ErrBindingExists
+		}
+
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	if doc == nil {
+		return nil
+	}
+
+	doc.RequestID = req.ID
+
+	if err := tx.Create(doc).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return nil
+}
+
+func (r *bindingRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.BindingRequest, error) {
+	ctx, span := r.tracer.Start(ctx, "bindingRepository.FindByID")
+	defer span.End()
+
+	query := r.db.WithContext(ctx).Where("id = ?", id)
+	if r.debug {
+		query = query.Debug()
+	}
+
+	var res model.BindingRequest
+	if err := query.First(&res).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrBindingNotFound
+		}
+
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return &res, nil
+}
+
+func (r *bindingRepository) FindByFilters(
+	ctx context.Context,
+	req *model.GetBindingRequestsRequest,
+) ([]model.BindingRequest, int64, error) {
+	ctx, span := r.tracer.Start(ctx, "bindingRepository.FindByFilters")
+	defer span.End()
+
+	query := r.db.WithContext(ctx).Model(&model.BindingRequest{})
+
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	if req.ApartmentID != uuid.Nil {
+		query = query.Where("apartment_id = ?", req.ApartmentID)
+	}
+
+	if req.UserID != uuid.Nil {
+		query = query.Where("user_id = ?", req.UserID)
+	}
+
+	if r.debug {
+		query = query.Debug()
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	page := req.Pagination.Normalize()
+
+	var res []model.BindingRequest
+	if err := query.
+		Order("created_at asc").
+		Offset(page.Offset()).
+		Limit(page.PageSize).
+		Find(&res).Error; err != nil {
+		return nil, 0, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return res, total, nil
+}
+
+func (r *bindingRepository) FindDocument(ctx context.Context, requestID uuid.UUID) (*model.BindingDocument, error) {
+	ctx, span := r.tracer.Start(ctx, "bindingRepository.FindDocument")
+	defer span.End()
+
+	query := r.db.WithContext(ctx).Where("request_id = ?", requestID)
+	if r.debug {
+		query = query.Debug()
+	}
+
+	var res model.BindingDocument
+	if err := query.First(&res).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrBindingProofNotFound
+		}
+
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return &res, nil
+}
+
+func (r *bindingRepository) Review(ctx context.Context, req *model.BindingRequest) error {
+	ctx, span := r.tracer.Start(ctx, "bindingRepository.Review")
+	defer span.End()
+
+	query := r.db.WithContext(ctx)
+	if r.debug {
+		query = query.Debug()
+	}
+
+	return review(query, req)
+}
+
+func (r *bindingRepository) Approve(
+	ctx context.Context,
+	req *model.BindingRequest,
+	rejectReason string,
+) ([]model.BindingRequest, error) {
+	ctx, span := r.tracer.Start(ctx, "bindingRepository.Approve")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	if r.debug {
+		db = db.Debug()
+	}
+
+	var rejected []model.BindingRequest
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		rejected, err = approve(tx, req, rejectReason)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rejected, nil
+}
+
+func approve(tx *gorm.DB, req *model.BindingRequest, rejectReason string) ([]model.BindingRequest, error) {
+	req.Status = model.BindingStatusApproved
+
+	// held until commit, so a concurrent approval for the same apartment waits and then sees the new owner
+	owner, err := apartment.LockOwner(tx, req.ApartmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if owner != nil && *owner != req.UserID {
+		return nil, model.ErrApartmentAlreadyBound
+	}
+
+	if err = review(tx, req); err != nil {
+		return nil, err
+	}
+
+	if err = member.Approve(tx, &model.ApartmentMember{
+		ApartmentID: req.ApartmentID,
+		UserID:      req.UserID,
+		Role:        model.MemberRoleOwner,
+		ApprovedBy:  req.ReviewedBy,
+	}); err != nil {
+		return nil, err
+	}
+
+	var rejected []model.BindingRequest
+	if err = tx.
+		Where("apartment_id = ? AND status = ? AND id <> ?", req.ApartmentID, model.BindingStatusPending, req.ID).
+		Find(&rejected).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	for i := range rejected {
+		rejected[i].Status = model.BindingStatusRejected
+		rejected[i].Reason = rejectReason
+		rejected[i].ReviewedBy = req.ReviewedBy
+		rejected[i].ReviewedAt = req.ReviewedAt
+		rejected[i].UpdatedAt = req.UpdatedAt
+
+		if err = review(tx, &rejected[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return rejected, nil
+}
+
+// review moves a pending request to req.Status, ErrBindingNotPending if it is not pending anymore.
+func review(tx *gorm.DB, req *model.BindingRequest) error {
+	res := tx.
+		Model(&model.BindingRequest{}).
+		Where("id = ? AND status = ?", req.ID, model.BindingStatusPending).
+		Updates(map[string]any{
+			"status":      req.Status,
+			"reason":      req.Reason,
+			"reviewed_by": req.ReviewedBy,
+			"reviewed_at": req.ReviewedAt,
+			"updated_at":  req.UpdatedAt,
+		})
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return model.ErrBindingNotPending
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package binding
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+type BindingRepo interface {
+	// Create stores the request with its proof document, if any, in one transaction.
+	// ErrBindingExists if the user already has a pending request for the apartment.
+	Create(ctx context.Context, req *model.BindingRequest, doc *model.BindingDocument) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.BindingRequest, error)
+	// FindByFilters returns requests oldest first, so the review queue is worked through in order
+	FindByFilters(ctx context.Context, req *model.GetBindingRequestsRequest) ([]model.BindingRequest, int64, error)
+	FindDocument(ctx context.Context, requestID uuid.UUID) (*model.BindingDocument, error)
+	// Review moves a pending request to approved or rejected, ErrBindingNotPending if it was reviewed already
+	Review(ctx context.Context, req *model.BindingRequest) error
+	// Approve makes the requester the owner of the apartment and moves the request to approved in one
+	// transaction that holds the apartment row locked. The other pending requests for the apartment are
+	// rejected with rejectReason and returned. ErrApartmentAlreadyBound if the apartment has another owner,
+	// ErrBindingNotPending if the request was reviewed already.
+	Approve(ctx context.Context, req *model.BindingRequest, rejectReason string) ([]model.BindingRequest, error)
+	// CreateApproved stores the request and approves it like Approve, all in one transaction.
+	// req carries the review fields; if the approval fails nothing is stored.
+	CreateApproved(
+		ctx context.Context,
+		req *model.BindingRequest,
+		doc *model.BindingDocument,
+		rejectReason string,
+	) ([]model.BindingRequest, error)
+}
@@ -0,0 +1,291 @@
+package building
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// backfillQueries give a complex without buildings a default one with entrance 1 and move
+// apartments created before buildings existed into it. The old floor+door index is dropped,
+// uniqueness is now per entrance.
+var backfillQueries = []string{`
+INSERT INTO buildings (name, address, created_at)
+SELECT @name, '', now()
+WHERE NOT EXISTS (SELECT 1 FROM buildings) OR EXISTS (SELECT 1 FROM apartments WHERE entrance_id IS NULL)
+ON CONFLICT (name) DO NOTHING`, `
+INSERT INTO entrances (building_id, number, created_at)
+SELECT b.id, 1, now()
+FROM buildings b
+WHERE b.name = @name
+  AND (NOT EXISTS (SELECT 1 FROM entrances) OR EXISTS (SELECT 1 FROM apartments WHERE entrance_id IS NULL))
+ON CONFLICT (building_id, number) DO NOTHING`, `
+UPDATE apartments
+SET entrance_id = (
+    SELECT e.id FROM entrances e JOIN buildings b ON b.id = e.building_id
+    WHERE b.name = @name AND e.number = 1
+)
+WHERE entrance_id IS NULL`, `
+DROP INDEX IF EXISTS idx_floor_door`,
+}
+
+type buildingRepository struct {
+	db     *gorm.DB
+	tracer trace.Tracer
+	debug  bool
+}
+
+func NewBuildingRepository(db *gorm.DB, debug bool) BuildingRepo {
+	return &buildingRepository{
+		db:     db,
+		tracer: otel.Tracer("buildingRepository"),
+		debug:  debug,
+	}
+}
+
+// Backfill is safe to run on every start.
+func Backfill(db *gorm.DB) error {
+	for _, q := range backfillQueries {
+		if err := db.Exec(q, map[string]any{"name": model.DefaultBuildingName}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *buildingRepository) CreateBuilding(ctx context.Context, b *model.Building) error {
+	ctx, span := r.tracer.Start(ctx, "buildingRepository.CreateBuilding")
+	defer span.End()
+
+	query := r.db.WithContext(ctx)
+	if r.debug {
+		query = query.Debug()
+	}
+
+	if err := query.Create(b).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return model.ErrBuildingExists
+		}
+
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return nil
+}
+
+func (r *buildingRepository) FindBuildings(ctx context.Context) ([]model.Building, error) {
+	ctx, span := r.tracer.Start(ctx, "buildingRepository.FindBuildings")
+	defer span.End()
+
+	query := r.db.WithContext(ctx).Order("name")
+	if r.debug {
+		query = query.Debug()
+	}
+
+	var res []model.Building
+	if err := query.Find(&res).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return res, nil
+}
+
+func (r *buildingRepository) FindBuildingByID(ctx context.Context, id uuid.UUID) (*model.Building, error) {
+	ctx, span := r.tracer.Start(ctx, "buildingRepository.FindBuildingByID")
+	defer span.End()
+
+	query := r.db.WithContext(ctx).Where("id = ?", id)
+	if r.debug {
+		query = query.Debug()
+	}
+
+	var res model.Building
+	if err := query.First(&res).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrBuildingNotFound
+		}
+
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return &res, nil
+}
+
+func (r *buildingRepository) UpdateBuilding(ctx context.Context, b *model.Building) error {
+	ctx, span := r.tracer.Start(ctx, "buildingRepository.UpdateBuilding")
+	defer span.End()
+
+	query := r.db.WithContext(ctx)
+	if r.debug {
+		query = query.Debug()
+	}
+
+	if err := query.
+		Model(&model.Building{}).
+		Where("id = ?", b.ID).
+		Updates(map[string]any{
+			"name":    b.Name,
+			"address": b.Address,
+		}).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return model.ErrBuildingExists
+		}
+
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return nil
+}
+
+func (r *buildingRepository) DeleteBuilding(ctx context.Context, id uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "buildingRepository.DeleteBuilding")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	if r.debug {
+		db = db.Debug()
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var apartments int64
+		if err := tx.
+			Model(&model.Apartment{}).
+			Where("entrance_id IN (SELECT id FROM entrances WHERE building_id = ?)", id).
+			Count(&apartments).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		if apartments > 0 {
+			return model.ErrBuildingNotEmpty
+		}
+
+		if err := tx.
+			Where("building_id = ?", id).
+			Delete(&model.Entrance{}).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		res := tx.Where("id = ?", id).Delete(&model.Building{})
+		if res.Error != nil {
+			return model.ErrDBUnexpected.WithErr(res.Error)
+		}
+
+		if res.RowsAffected == 0 {
+			return model.ErrBuildingNotFound
+		}
+
+		return nil
+	})
+}
+
+func (r *buildingRepository) CreateEntrance(ctx context.Context, e *model.Entrance) error {
+	ctx, span := r.tracer.Start(ctx, "buildingRepository.CreateEntrance")
+	defer span.End()
+
+	query := r.db.WithContext(ctx)
+	if r.debug {
+		query = query.Debug()
+	}
+
+	if err := query.Create(e).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return model.ErrEntranceExists
+		}
+
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return nil
+}
+
+func (r *buildingRepository) FindEntrances(ctx context.Context, filter model.EntranceFilter) ([]model.Entrance, error) {
+	ctx, span := r.tracer.Start(ctx, "buildingRepository.FindEntrances")
+	defer span.End()
+
+	query := r.db.WithContext(ctx).Model(&model.Entrance{})
+
+	if filter.BuildingID != uuid.Nil {
+		query = query.Where("building_id = ?", filter.BuildingID)
+	}
+
+	if filter.BuildingName != "" {
+		query = query.Where("building_id IN (SELECT id FROM buildings WHERE name = ?)", filter.BuildingName)
+	}
+
+	if filter.Number != 0 {
+		query = query.Where("number = ?", filter.Number)
+	}
+
+	if r.debug {
+		query = query.Debug()
+	}
+
+	var res []model.Entrance
+	if err := query.Order("number").Find(&res).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return res, nil
+}
+
+func (r *buildingRepository) FindEntrancesByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Entrance, error) {
+	ctx, span := r.tracer.Start(ctx, "buildingRepository.FindEntrancesByIDs")
+	defer span.End()
+
+	var res []model.Entrance
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	query := r.db.WithContext(ctx).Where("id IN ?", ids)
+	if r.debug {
+		query = query.Debug()
+	}
+
+	if err := query.Find(&res).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return res, nil
+}
+
+func (r *buildingRepository) DeleteEntrance(ctx context.Context, id uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "buildingRepository.DeleteEntrance")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	if r.debug {
+		db = db.Debug()
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var apartments int64
+		if err := tx.
+			Model(&model.Apartment{}).
+			Where("entrance_id = ?", id).
+			Count(&apartments).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		if apartments > 0 {
+			return model.ErrEntranceNotEmpty
+		}
+
+		res := tx.Where("id = ?", id).Delete(&model.Entrance{})
+		if res.Error != nil {
+			return model.ErrDBUnexpected.WithErr(res.Error)
+		}
+
+		if res.RowsAffected == 0 {
+			return model.ErrEntranceNotFound
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,25 @@
+package building
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+type BuildingRepo interface {
+	// CreateBuilding stores a new building, ErrBuildingExists if the name is taken
+	CreateBuilding(ctx context.Context, b *model.Building) error
+	FindBuildings(ctx context.Context) ([]model.Building, error)
+	FindBuildingByID(ctx context.Context, id uuid.UUID) (*model.Building, error)
+	// UpdateBuilding saves name and address, ErrBuildingExists if the name is taken
+	UpdateBuilding(ctx context.Context, b *model.Building) error
+	// DeleteBuilding removes the building and its entrances, ErrBuildingNotEmpty if it has apartments
+	DeleteBuilding(ctx context.Context, id uuid.UUID) error
+	// CreateEntrance stores a new entrance, ErrEntranceExists if the building already has one with the number
+	CreateEntrance(ctx context.Context, e *model.Entrance) error
+	FindEntrances(ctx context.Context, filter model.EntranceFilter) ([]model.Entrance, error)
+	FindEntrancesByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Entrance, error)
+	// DeleteEntrance removes an entrance, ErrEntranceNotEmpty if it has apartments
+	DeleteEntrance(ctx context.Context, id uuid.UUID) error
+}
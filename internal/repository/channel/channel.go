@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
@@ -37,12 +39,38 @@ func (c *chanRepo) InsertNewMessage(ctx context.Context, msg model.ChannelMessag
 	return nil
 }
 
-func (c *chanRepo) GetMessageByTime(ctx context.Context, from, to time.Time) ([]model.ChannelMessage, error) {
+func (c *chanRepo) GetMessageByTime(
+	ctx context.Context,
+	from, to time.Time,
+	buildingID uuid.UUID,
+) ([]model.ChannelMessage, error) {
 	ctx, span := c.tracer.Start(ctx, "channelRepo.GetMessageByTime")
 	defer span.End()
 
 	query := c.db.WithContext(ctx).Where("created_at BETWEEN ? AND ?", from, to)
 
+	if buildingID != uuid.Nil {
+		query = query.Where("building_id IS NULL OR building_id = ?", buildingID)
+	}
+
+	if c.debug {
+		query = query.Debug()
+	}
+
+	var res []model.ChannelMessage
+	if err := query.Find(&res).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return res, nil
+}
+
+func (c *chanRepo) GetMessagesByAuthor(ctx context.Context, authorID uuid.UUID) ([]model.ChannelMessage, error) {
+	ctx, span := c.tracer.Start(ctx, "channelRepo.GetMessagesByAuthor")
+	defer span.End()
+
+	query := c.db.WithContext(ctx).Where("author_id = ?", authorID).Order("created_at")
+
 	if c.debug {
 		query = query.Debug()
 	}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
 )
 
 type ChanRepo interface {
 	InsertNewMessage(ctx context.Context, msg model.ChannelMessage) error
-	GetMessageByTime(ctx context.Context, from, to time.Time) ([]model.ChannelMessage, error)
+	// GetMessageByTime returns messages of the period, with buildingID set only those for the whole
+	// complex and for that building
+	GetMessageByTime(ctx context.Context, from, to time.Time, buildingID uuid.UUID) ([]model.ChannelMessage, error)
+	GetMessagesByAuthor(ctx context.Context, authorID uuid.UUID) ([]model.ChannelMessage, error)
 }
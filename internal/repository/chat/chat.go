@@ -9,6 +9,7 @@ import (
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
@@ -142,3 +143,44 @@ func (c *chat) CreateChat(ctx context.Context, creatorID uuid.UUID, participants
 
 	return &newChat.ID, nil
 }
+
+func (c *chat) GetMessagesBySender(ctx context.Context, senderID uuid.UUID) ([]model.Message, error) {
+	ctx, span := c.tracer.Start(ctx, "chatRepo.GetMessagesBySender")
+	defer span.End()
+
+	coll := c.mongoClient.
+		Database("assyl").
+		Collection("messages")
+
+	cursor, err := coll.Find(
+		ctx,
+		bson.M{"sender_id": senderID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	var res []model.Message
+	if err = cursor.All(ctx, &res); err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return res, nil
+}
+
+func (c *chat) CountMessagesBySender(ctx context.Context, senderID uuid.UUID) (int64, error) {
+	ctx, span := c.tracer.Start(ctx, "chatRepo.CountMessagesBySender")
+	defer span.End()
+
+	coll := c.mongoClient.
+		Database("assyl").
+		Collection("messages")
+
+	n, err := coll.CountDocuments(ctx, bson.M{"sender_id": senderID})
+	if err != nil {
+		return 0, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return n, nil
+}
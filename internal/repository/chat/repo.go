@@ -12,4 +12,6 @@ type ChatRepo interface {
 	CheckUserInChat(ctx context.Context, userID, chatID uuid.UUID) (bool, error)
 	ChangeLastChatInfo(ctx context.Context, msg string, userID, chatID uuid.UUID) error
 	CreateChat(ctx context.Context, creatorID uuid.UUID, participantsIDs []uuid.UUID) (*uuid.UUID, error)
+	GetMessagesBySender(ctx context.Context, senderID uuid.UUID) ([]model.Message, error)
+	CountMessagesBySender(ctx context.Context, senderID uuid.UUID) (int64, error)
 }
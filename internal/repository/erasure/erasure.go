@@ -0,0 +1,325 @@
+package erasure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/apartment"
+	"github.com/podpivasniki1488/assyl-backend/protopb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+type erasureRepository struct {
+	pgDB        *gorm.DB
+	mongoClient *mongo.Client
+	tracer      trace.Tracer
+	debug       bool
+}
+
+func NewErasureRepository(pgDB *gorm.DB, mongoClient *mongo.Client, debug bool) ErasureRepo {
+	return &erasureRepository{
+		pgDB:        pgDB,
+		mongoClient: mongoClient,
+		tracer:      otel.Tracer("erasureRepository"),
+		debug:       debug,
+	}
+}
+
+func (e *erasureRepository) EraseUser(ctx context.Context, userID uuid.UUID, mode model.ErasureMode) (*model.ErasureReport, error) {
+	ctx, span := e.tracer.Start(ctx, "erasureRepository.EraseUser")
+	defer span.End()
+
+	db := e.pgDB.WithContext(ctx)
+	if e.debug {
+		db = db.Debug()
+	}
+
+	report := &model.ErasureReport{
+		UserID: userID,
+		Mode:   mode,
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.User{}).Where("id = ?", userID).Count(&count).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		if count == 0 {
+			return model.ErrUserNotFound
+		}
+
+		if err := e.eraseCommon(tx, userID, report); err != nil {
+			return err
+		}
+
+		var err error
+		if mode == model.ErasureModeDelete {
+			err = e.eraseAuthored(tx, userID, report)
+		} else {
+			err = e.anonymizeAuthored(tx, userID, report)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if mode == model.ErasureModeAnonymize {
+			// messages are kept under the tombstone, counting them changes nothing
+			return e.countMessages(ctx, userID, report)
+		}
+
+		// Mongo can not be rolled back with Postgres, so the messages are deleted only after the commit
+		// and the job makes sure it happens even if that step fails
+		if err = tx.Create(&model.ErasureJob{UserID: userID}).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if mode == model.ErasureModeDelete {
+		deleted, err := e.runJobs(ctx)
+		if err != nil {
+			// the user is already erased, the failed job stays and is retried
+			span.RecordError(err)
+		}
+
+		n, ok := deleted[userID]
+		report.Messages = n
+		report.MessagesPending = !ok
+	}
+
+	return report, nil
+}
+
+func (e *erasureRepository) RetryPending(ctx context.Context) (int, error) {
+	ctx, span := e.tracer.Start(ctx, "erasureRepository.RetryPending")
+	defer span.End()
+
+	deleted, err := e.runJobs(ctx)
+
+	return len(deleted), err
+}
+
+// runJobs deletes the chat messages of every pending job and drops the finished jobs. Deleting by sender
+// is idempotent, so a job that ran in Mongo but was not dropped is safely run again.
+func (e *erasureRepository) runJobs(ctx context.Context) (map[uuid.UUID]int64, error) {
+	db := e.pgDB.WithContext(ctx)
+	if e.debug {
+		db = db.Debug()
+	}
+
+	var jobs []model.ErasureJob
+	if err := db.Order("created_at asc").Find(&jobs).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	deleted := make(map[uuid.UUID]int64, len(jobs))
+
+	for _, job := range jobs {
+		res, err := e.messages().DeleteMany(ctx, bson.M{"sender_id": job.UserID})
+		if err != nil {
+			return deleted, model.ErrDBUnexpected.WithErr(err)
+		}
+
+		if err = db.Where("user_id = ?", job.UserID).Delete(&model.ErasureJob{}).Error; err != nil {
+			return deleted, model.ErrDBUnexpected.WithErr(err)
+		}
+
+		deleted[job.UserID] = res.DeletedCount
+	}
+
+	return deleted, nil
+}
+
+// eraseCommon drops what is personal in both modes: credentials, external identities,
+// chat memberships, pending invitations, household memberships, binding requests with their
+// proof documents and apartment ownership.
+func (e *erasureRepository) eraseCommon(tx *gorm.DB, userID uuid.UUID, report *model.ErasureReport) error {
+	res := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{})
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+	report.RecoveryCodes = res.RowsAffected
+
+	res = tx.Where("user_id = ?", userID).Delete(&model.UserIdentity{})
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+	report.Identities = res.RowsAffected
+
+	res = tx.Where("user_id = ?", userID).Delete(&model.ChatParticipant{})
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+	report.ChatParticipants = res.RowsAffected
+
+	res = tx.Where("user_id = ?", userID).Delete(&model.ApartmentMember{})
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+	report.Memberships = res.RowsAffected
+
+	if err := tx.
+		Where("request_id IN (SELECT id FROM apartment_binding_requests WHERE user_id = ?)", userID).
+		Delete(&model.BindingDocument{}).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	res = tx.Where("user_id = ?", userID).Delete(&model.BindingRequest{})
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+	report.BindingRequests = res.RowsAffected
+
+	released, err := apartment.ReleaseOwner(tx, userID)
+	if err != nil {
+		return err
+	}
+	report.ApartmentsReleased = released
+
+	res = tx.Model(&model.Invitation{}).
+		Where("created_by = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+	report.Invitations = res.RowsAffected
+
+	return nil
+}
+
+func (e *erasureRepository) eraseAuthored(tx *gorm.DB, userID uuid.UUID, report *model.ErasureReport) error {
+	if err := tx.
+		Where("reservation_id IN (SELECT id FROM cinema_reservations WHERE user_id = ?)", userID).
+		Delete(&model.ReservationSlot{}).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	res := tx.Where("user_id = ?", userID).Delete(&model.CinemaReservation{})
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+	report.Reservations = res.RowsAffected
+
+	res = tx.Where("user_id = ?", userID).Delete(&model.Feedback{})
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+	report.Feedbacks = res.RowsAffected
+
+	res = tx.Where("user_id = ?", userID).Delete(&model.Order{})
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+	report.Orders = res.RowsAffected
+
+	if err := tx.Where("owner_id = ?", userID).Delete(&model.ApartmentOwnership{}).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	res = tx.Where("author_id = ?", userID).Delete(&model.ChannelMessage{})
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+	report.ChannelMessages = res.RowsAffected
+
+	// the preview is a copy of the user's last message
+	if err := tx.Model(&model.Chat{}).
+		Where("last_participant_id = ?", userID).
+		Updates(map[string]any{
+			"last_participant_id":  nil,
+			"last_message_preview": "",
+		}).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	res = tx.Where("created_by = ?", userID).Delete(&model.Invitation{})
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+	report.Invitations = res.RowsAffected
+
+	if err := tx.Model(&model.User{}).Where("invited_by = ?", userID).Update("invited_by", nil).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	if err := tx.Where("id = ?", userID).Delete(&model.User{}).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return nil
+}
+
+// anonymizeAuthored keeps the user's row as a tombstone, so reservations, feedback, orders
+// and channel posts still point at an existing user, and strips personal data from them.
+func (e *erasureRepository) anonymizeAuthored(tx *gorm.DB, userID uuid.UUID, report *model.ErasureReport) error {
+	res := tx.Model(&model.CinemaReservation{}).Where("user_id = ?", userID).Update("phone_num", "")
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+	report.Reservations = res.RowsAffected
+
+	if err := tx.Model(&model.Feedback{}).Where("user_id = ?", userID).Count(&report.Feedbacks).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	if err := tx.Model(&model.Order{}).Where("user_id = ?", userID).Count(&report.Orders).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	if err := tx.Model(&model.ChannelMessage{}).Where("author_id = ?", userID).Count(&report.ChannelMessages).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	// an empty password hash never matches, so the tombstone can not log in
+	if err := tx.Model(&model.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]any{
+			"first_name":    "Deleted",
+			"last_name":     "user",
+			"username":      fmt.Sprintf("deleted:%s", userID),
+			"username_type": 1, // none: neither email nor phone
+			"password":      "",
+			"is_approved":   false,
+			"apartment_id":  nil,
+			"role_id":       protopb.Role_GUEST,
+			"totp_secret":   "",
+			"totp_enabled":  false,
+			"invited_by":    nil,
+			"avatar_url":    "",
+		}).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return nil
+}
+
+// countMessages counts the user's chat messages, which are kept under the anonymized sender.
+func (e *erasureRepository) countMessages(ctx context.Context, userID uuid.UUID, report *model.ErasureReport) error {
+	n, err := e.messages().CountDocuments(ctx, bson.M{"sender_id": userID})
+	if err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	report.Messages = n
+
+	return nil
+}
+
+func (e *erasureRepository) messages() *mongo.Collection {
+	return e.mongoClient.
+		Database("assyl").
+		Collection("messages")
+}
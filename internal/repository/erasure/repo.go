@@ -0,0 +1,19 @@
+package erasure
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+type ErasureRepo interface {
+	// EraseUser removes or anonymizes the user and everything that points at the user, in Postgres
+	// and in the Mongo messages collection. Postgres changes are made in one transaction, chat messages
+	// are deleted after it commits. If that fails the report has MessagesPending and RetryPending
+	// finishes the job later.
+	EraseUser(ctx context.Context, userID uuid.UUID, mode model.ErasureMode) (*model.ErasureReport, error)
+	// RetryPending deletes the chat messages of erased users whose cleanup failed and returns
+	// how many jobs were finished
+	RetryPending(ctx context.Context) (int, error)
+}
@@ -0,0 +1,219 @@
+package invitation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/member"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type invitationRepository struct {
+	db     *gorm.DB
+	tracer trace.Tracer
+	debug  bool
+}
+
+func NewInvitationRepository(db *gorm.DB, debug bool) InvitationRepo {
+	return &invitationRepository{
+		db:     db,
+		tracer: otel.Tracer("invitationRepository"),
+		debug:  debug,
+	}
+}
+
+func (i *invitationRepository) Create(ctx context.Context, inv *model.Invitation) error {
+	ctx, span := i.tracer.Start(ctx, "invitationRepository.Create")
+	defer span.End()
+
+	query := i.db.WithContext(ctx)
+	if i.debug {
+		query = query.Debug()
+	}
+
+	if err := query.Create(inv).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return nil
+}
+
+func (i *invitationRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Invitation, error) {
+	ctx, span := i.tracer.Start(ctx, "invitationRepository.FindByID")
+	defer span.End()
+
+	return i.findOne(ctx, "id = ?", id)
+}
+
+func (i *invitationRepository) FindByCodeHash(ctx context.Context, codeHash string) (*model.Invitation, error) {
+	ctx, span := i.tracer.Start(ctx, "invitationRepository.FindByCodeHash")
+	defer span.End()
+
+	return i.findOne(ctx, "code_hash = ?", codeHash)
+}
+
+func (i *invitationRepository) FindByApartmentID(ctx context.Context, apartmentID uuid.UUID) ([]model.Invitation, error) {
+	ctx, span := i.tracer.Start(ctx, "invitationRepository.FindByApartmentID")
+	defer span.End()
+
+	query := i.db.
+		WithContext(ctx).
+		Where("apartment_id = ?", apartmentID).
+		Order("created_at desc")
+
+	if i.debug {
+		query = query.Debug()
+	}
+
+	var res []model.Invitation
+	if err := query.Find(&res).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return res, nil
+}
+
+func (i *invitationRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	ctx, span := i.tracer.Start(ctx, "invitationRepository.Revoke")
+	defer span.End()
+
+	query := i.db.
+		WithContext(ctx).
+		Model(&model.Invitation{}).
+		Where("id = ? AND revoked_at IS NULL", id)
+
+	if i.debug {
+		query = query.Debug()
+	}
+
+	if err := query.Update("revoked_at", time.Now()).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return nil
+}
+
+func (i *invitationRepository) Redeem(ctx context.Context, codeHash string, userID uuid.UUID) (*model.Invitation, error) {
+	ctx, span := i.tracer.Start(ctx, "invitationRepository.Redeem")
+	defer span.End()
+
+	db := i.db.WithContext(ctx)
+	if i.debug {
+		db = db.Debug()
+	}
+
+	var inv *model.Invitation
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		inv, err = redeem(tx, codeHash, userID)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return inv, nil
+}
+
+func (i *invitationRepository) CreateUserAndRedeem(ctx context.Context, user *model.User, codeHash string) (*model.Invitation, error) {
+	ctx, span := i.tracer.Start(ctx, "invitationRepository.CreateUserAndRedeem")
+	defer span.End()
+
+	db := i.db.WithContext(ctx)
+	if i.debug {
+		db = db.Debug()
+	}
+
+	var inv *model.Invitation
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return model.ErrUserAlreadyExists
+			}
+
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		var err error
+		inv, err = redeem(tx, codeHash, user.ID)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return inv, nil
+}
+
+func redeem(tx *gorm.DB, codeHash string, userID uuid.UUID) (*model.Invitation, error) {
+	var inv model.Invitation
+
+	// the row lock keeps concurrent redemptions from going over MaxUses
+	if err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("code_hash = ?", codeHash).
+		First(&inv).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrInvitationInvalid
+		}
+
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	if !inv.Usable(time.Now()) {
+		return nil, model.ErrInvitationInvalid
+	}
+
+	inv.UsedCount++
+	if err := tx.Model(&inv).Update("used_count", inv.UsedCount).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	if err := tx.
+		Model(&model.User{}).
+		Where("id = ?", userID).
+		Update("invited_by", inv.CreatedBy).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	// the invitation is the owner's approval, a pending request of the user is approved by it.
+	// The role is never owner: ownership is given only by an approved binding request.
+	if err := member.Approve(tx, &model.ApartmentMember{
+		ApartmentID: inv.ApartmentID,
+		UserID:      userID,
+		Role:        inv.MemberRole,
+		ApprovedBy:  &inv.CreatedBy,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &inv, nil
+}
+
+func (i *invitationRepository) findOne(ctx context.Context, cond string, args ...any) (*model.Invitation, error) {
+	query := i.db.WithContext(ctx).Where(cond, args...)
+	if i.debug {
+		query = query.Debug()
+	}
+
+	var res model.Invitation
+	if err := query.First(&res).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrInvitationNotFound
+		}
+
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return &res, nil
+}
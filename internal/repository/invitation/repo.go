@@ -0,0 +1,23 @@
+package invitation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+type InvitationRepo interface {
+	Create(ctx context.Context, inv *model.Invitation) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Invitation, error)
+	FindByCodeHash(ctx context.Context, codeHash string) (*model.Invitation, error)
+	FindByApartmentID(ctx context.Context, apartmentID uuid.UUID) ([]model.Invitation, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	// Redeem uses one slot of the invitation and binds the user to its apartment in one transaction:
+	// sets InvitedBy and approves a membership with the invitation's role, see member.Approve.
+	// Ownership is not given by invitations, only by an approved binding request.
+	Redeem(ctx context.Context, codeHash string, userID uuid.UUID) (*model.Invitation, error)
+	// CreateUserAndRedeem creates the user and redeems the invitation for it in one transaction,
+	// if the invitation cannot be redeemed the user is not created. ErrUserAlreadyExists if the username is taken.
+	CreateUserAndRedeem(ctx context.Context, user *model.User, codeHash string) (*model.Invitation, error)
+}
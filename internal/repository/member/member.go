@@ -0,0 +1,326 @@
+package member
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/apartment"
+	"github.com/podpivasniki1488/assyl-backend/protopb"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// backfillQueries create memberships for bindings made before apartment_members existed:
+// apartment owners become owners, other residents become family members.
+var backfillQueries = []string{`
+INSERT INTO apartment_members (apartment_id, user_id, role, status, created_at, updated_at)
+SELECT a.id, a.owner_id, 'owner', 'approved', now(), now()
+FROM apartments a
+JOIN users u ON u.id = a.owner_id
+ON CONFLICT (apartment_id, user_id) DO NOTHING`, `
+INSERT INTO apartment_members (apartment_id, user_id, role, status, created_at, updated_at)
+SELECT u.apartment_id, u.id, 'family', 'approved', now(), now()
+FROM users u
+JOIN apartments a ON a.id = u.apartment_id
+ON CONFLICT (apartment_id, user_id) DO NOTHING`,
+}
+
+type memberRepository struct {
+	db     *gorm.DB
+	tracer trace.Tracer
+	debug  bool
+}
+
+func NewMemberRepository(db *gorm.DB, debug bool) MemberRepo {
+	return &memberRepository{
+		db:     db,
+		tracer: otel.Tracer("memberRepository"),
+		debug:  debug,
+	}
+}
+
+// Backfill is safe to run on every start, existing memberships are left untouched.
+func Backfill(db *gorm.DB) error {
+	for _, q := range backfillQueries {
+		if err := db.Exec(q).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *memberRepository) Create(ctx context.Context, m *model.ApartmentMember) error {
+	ctx, span := r.tracer.Start(ctx, "memberRepository.Create")
+	defer span.End()
+
+	query := r.db.WithContext(ctx)
+	if r.debug {
+		query = query.Debug()
+	}
+
+	if err := query.Create(m).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return model.ErrMemberAlreadyExists
+		}
+
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return nil
+}
+
+func (r *memberRepository) Find(ctx context.Context, apartmentID, userID uuid.UUID) (*model.ApartmentMember, error) {
+	ctx, span := r.tracer.Start(ctx, "memberRepository.Find")
+	defer span.End()
+
+	query := r.db.
+		WithContext(ctx).
+		Where("apartment_id = ? AND user_id = ?", apartmentID, userID)
+
+	if r.debug {
+		query = query.Debug()
+	}
+
+	var res model.ApartmentMember
+	if err := query.First(&res).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrMemberNotFound
+		}
+
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return &res, nil
+}
+
+func (r *memberRepository) FindByApartment(
+	ctx context.Context,
+	apartmentID uuid.UUID,
+	status model.MemberStatus,
+) ([]model.ApartmentMember, error) {
+	ctx, span := r.tracer.Start(ctx, "memberRepository.FindByApartment")
+	defer span.End()
+
+	query := r.db.
+		WithContext(ctx).
+		Where("apartment_id = ?", apartmentID).
+		Order("created_at")
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if r.debug {
+		query = query.Debug()
+	}
+
+	var res []model.ApartmentMember
+	if err := query.Find(&res).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return res, nil
+}
+
+func (r *memberRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]model.ApartmentMember, error) {
+	ctx, span := r.tracer.Start(ctx, "memberRepository.FindByUser")
+	defer span.End()
+
+	query := r.db.
+		WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at")
+
+	if r.debug {
+		query = query.Debug()
+	}
+
+	var res []model.ApartmentMember
+	if err := query.Find(&res).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return res, nil
+}
+
+func (r *memberRepository) Reopen(ctx context.Context, id uuid.UUID, role model.MemberRole) error {
+	ctx, span := r.tracer.Start(ctx, "memberRepository.Reopen")
+	defer span.End()
+
+	return r.update(ctx, id, map[string]any{
+		"role":        role,
+		"status":      model.MemberStatusPending,
+		"approved_by": nil,
+	})
+}
+
+func (r *memberRepository) Approve(ctx context.Context, m *model.ApartmentMember) error {
+	ctx, span := r.tracer.Start(ctx, "memberRepository.Approve")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	if r.debug {
+		db = db.Debug()
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		return Approve(tx, m)
+	})
+}
+
+// Approve is memberRepository.Approve inside the caller's transaction.
+func Approve(tx *gorm.DB, m *model.ApartmentMember) error {
+	m.Status = model.MemberStatusApproved
+
+	if err := leaveOtherHouseholds(tx, m.UserID, m.ApartmentID); err != nil {
+		return err
+	}
+
+	if err := tx.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "apartment_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role", "status", "approved_by", "updated_at"}),
+		}).
+		Create(m).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	if err := tx.
+		Model(&model.User{}).
+		Where("id = ?", m.UserID).
+		Update("apartment_id", m.ApartmentID).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	if err := tx.
+		Model(&model.User{}).
+		Where("id = ? AND role_id = ?", m.UserID, protopb.Role_GUEST).
+		Update("role_id", protopb.Role_INHABITANT).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	if m.Role != model.MemberRoleOwner {
+		// the user may have been the owner before
+		_, err := apartment.SetOwner(tx, m.ApartmentID, nil, apartment.OwnedBy(m.UserID))
+		return err
+	}
+
+	// an apartment has a single owner, the previous one stays in the household as a co-owner
+	if err := tx.
+		Model(&model.ApartmentMember{}).
+		Where("apartment_id = ? AND user_id <> ? AND role = ?", m.ApartmentID, m.UserID, model.MemberRoleOwner).
+		Updates(map[string]any{
+			"role":       model.MemberRoleCoOwner,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	_, err := apartment.SetOwner(tx, m.ApartmentID, &m.UserID, nil)
+	return err
+}
+
+// leaveOtherHouseholds removes the user's approved memberships outside apartmentID, so that the user
+// is not counted in two households, and releases the apartments the user owned there.
+func leaveOtherHouseholds(tx *gorm.DB, userID, apartmentID uuid.UUID) error {
+	var previous []model.ApartmentMember
+	if err := tx.
+		Where("user_id = ? AND apartment_id <> ? AND status = ?", userID, apartmentID, model.MemberStatusApproved).
+		Find(&previous).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	for _, p := range previous {
+		if err := tx.Where("id = ?", p.ID).Delete(&model.ApartmentMember{}).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		if _, err := apartment.SetOwner(tx, p.ApartmentID, nil, apartment.OwnedBy(userID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *memberRepository) Reject(ctx context.Context, id uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "memberRepository.Reject")
+	defer span.End()
+
+	return r.update(ctx, id, map[string]any{
+		"status": model.MemberStatusRejected,
+	})
+}
+
+func (r *memberRepository) ChangeRole(ctx context.Context, id uuid.UUID, role model.MemberRole) error {
+	ctx, span := r.tracer.Start(ctx, "memberRepository.ChangeRole")
+	defer span.End()
+
+	return r.update(ctx, id, map[string]any{
+		"role": role,
+	})
+}
+
+func (r *memberRepository) Remove(ctx context.Context, apartmentID, userID uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "memberRepository.Remove")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	if r.debug {
+		db = db.Debug()
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		res := tx.
+			Where("apartment_id = ? AND user_id = ?", apartmentID, userID).
+			Delete(&model.ApartmentMember{})
+
+		if res.Error != nil {
+			return model.ErrDBUnexpected.WithErr(res.Error)
+		}
+
+		if res.RowsAffected == 0 {
+			return model.ErrMemberNotFound
+		}
+
+		if err := tx.
+			Model(&model.User{}).
+			Where("id = ? AND apartment_id = ?", userID, apartmentID).
+			Update("apartment_id", uuid.Nil).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		_, err := apartment.SetOwner(tx, apartmentID, nil, apartment.OwnedBy(userID))
+		return err
+	})
+}
+
+func (r *memberRepository) update(ctx context.Context, id uuid.UUID, fields map[string]any) error {
+	fields["updated_at"] = time.Now()
+
+	query := r.db.
+		WithContext(ctx).
+		Model(&model.ApartmentMember{}).
+		Where("id = ?", id)
+
+	if r.debug {
+		query = query.Debug()
+	}
+
+	res := query.Updates(fields)
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return model.ErrMemberNotFound
+	}
+
+	return nil
+}
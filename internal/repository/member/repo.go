@@ -0,0 +1,29 @@
+package member
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+type MemberRepo interface {
+	// Create stores a new membership, ErrMemberAlreadyExists if the user already has one in the apartment
+	Create(ctx context.Context, m *model.ApartmentMember) error
+	// Find returns the membership of the user in the apartment, ErrMemberNotFound if there is none
+	Find(ctx context.Context, apartmentID, userID uuid.UUID) (*model.ApartmentMember, error)
+	// FindByApartment returns members of the apartment, all of them if status is empty
+	FindByApartment(ctx context.Context, apartmentID uuid.UUID, status model.MemberStatus) ([]model.ApartmentMember, error)
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]model.ApartmentMember, error)
+	// Reopen turns a rejected membership back into a pending request with the new role
+	Reopen(ctx context.Context, id uuid.UUID, role model.MemberRole) error
+	// Approve saves an approved membership and syncs the legacy fields in one transaction:
+	// points User.ApartmentID at the apartment, promotes a GUEST to INHABITANT and sets or clears
+	// Apartment.OwnerId depending on whether the role is owner. A user belongs to one household:
+	// approved memberships in other apartments are removed and their ownership released.
+	Approve(ctx context.Context, m *model.ApartmentMember) error
+	Reject(ctx context.Context, id uuid.UUID) error
+	ChangeRole(ctx context.Context, id uuid.UUID, role model.MemberRole) error
+	// Remove deletes the membership and clears User.ApartmentID if it points at the apartment
+	Remove(ctx context.Context, apartmentID, userID uuid.UUID) error
+}
@@ -0,0 +1,61 @@
+package messaging
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Message struct {
+	To     string
+	Text   string
+	SentAt time.Time
+}
+
+// Fake keeps every message in memory instead of sending it. Used for local runs and tests.
+type Fake struct {
+	mux      sync.Mutex
+	messages []Message
+}
+
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+func (f *Fake) SendMessage(_ context.Context, to, text string) error {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	f.messages = append(f.messages, Message{
+		To:     to,
+		Text:   text,
+		SentAt: time.Now(),
+	})
+
+	return nil
+}
+
+// Messages returns a copy of everything sent so far.
+func (f *Fake) Messages() []Message {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	res := make([]Message, len(f.messages))
+	copy(res, f.messages)
+
+	return res
+}
+
+// Last returns the latest message sent to the recipient.
+func (f *Fake) Last(to string) (Message, bool) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	for i := len(f.messages) - 1; i >= 0; i-- {
+		if f.messages[i].To == to {
+			return f.messages[i], true
+		}
+	}
+
+	return Message{}, false
+}
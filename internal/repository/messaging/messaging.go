@@ -0,0 +1,50 @@
+package messaging
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	ProviderWhatsApp = "whatsapp"
+	ProviderSMS      = "sms"
+	ProviderFake     = "fake"
+)
+
+type Config struct {
+	Provider string
+	// Debug allows the fake provider, which drops every message
+	Debug bool
+
+	WhatsAppURL           string
+	WhatsAppToken         string
+	WhatsAppPhoneNumberID string
+
+	SMSGatewayURL string
+	SMSAPIKey     string
+	SMSSender     string
+}
+
+// NewMessagingRepo picks the provider by cfg.Provider. The provider is required: the fake one only keeps
+// messages in memory, so it is refused outside of debug, where phone codes would be lost silently.
+func NewMessagingRepo(cfg Config) (MessagingRepo, error) {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	switch cfg.Provider {
+	case ProviderWhatsApp:
+		return newWhatsApp(httpClient, cfg.WhatsAppURL, cfg.WhatsAppToken, cfg.WhatsAppPhoneNumberID)
+	case ProviderSMS:
+		return newSMSGateway(httpClient, cfg.SMSGatewayURL, cfg.SMSAPIKey, cfg.SMSSender)
+	case ProviderFake:
+		if !cfg.Debug {
+			return nil, fmt.Errorf("messaging provider %q is only allowed in debug", ProviderFake)
+		}
+
+		return NewFake(), nil
+	case "":
+		return nil, fmt.Errorf("messaging provider is not set")
+	default:
+		return nil, fmt.Errorf("unknown messaging provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,65 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewMessagingRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "empty provider", cfg: Config{}, wantErr: true},
+		{name: "unknown provider", cfg: Config{Provider: "pigeon", Debug: true}, wantErr: true},
+		{name: "fake outside debug", cfg: Config{Provider: ProviderFake}, wantErr: true},
+		{name: "fake in debug", cfg: Config{Provider: ProviderFake, Debug: true}},
+		{name: "sms without url", cfg: Config{Provider: ProviderSMS, SMSAPIKey: "key"}, wantErr: true},
+		{name: "sms", cfg: Config{Provider: ProviderSMS, SMSGatewayURL: "https://sms.example.com", SMSAPIKey: "key"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewMessagingRepo(tt.cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %T", repo)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestFake(t *testing.T) {
+	f := NewFake()
+	ctx := context.Background()
+
+	if _, ok := f.Last("+77010000000"); ok {
+		t.Fatal("expected no messages")
+	}
+
+	for _, text := range []string{"first", "second"} {
+		if err := f.SendMessage(ctx, "+77010000000", text); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := f.SendMessage(ctx, "+77020000000", "other"); err != nil {
+		t.Fatal(err)
+	}
+
+	if m, ok := f.Last("+77010000000"); !ok || m.Text != "second" {
+		t.Fatalf("Last = %+v, %v, want second", m, ok)
+	}
+
+	if n := len(f.Messages()); n != 3 {
+		t.Fatalf("got %d messages, want 3", n)
+	}
+}
@@ -0,0 +1,7 @@
+package messaging
+
+import "context"
+
+type MessagingRepo interface {
+	SendMessage(ctx context.Context, to, text string) error
+}
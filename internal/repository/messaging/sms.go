@@ -0,0 +1,71 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// smsGateway posts messages as JSON {"from","to","text"} to a generic HTTP SMS gateway
+// authenticated with an API key header.
+type smsGateway struct {
+	httpClient *http.Client
+	tracer     trace.Tracer
+	url        string
+	apiKey     string
+	sender     string
+}
+
+func newSMSGateway(httpClient *http.Client, url, apiKey, sender string) (MessagingRepo, error) {
+	if url == "" || apiKey == "" {
+		return nil, errors.New("sms provider requires gateway url and api key")
+	}
+
+	return &smsGateway{
+		httpClient: httpClient,
+		tracer:     otel.Tracer("smsGatewayRepo"),
+		url:        url,
+		apiKey:     apiKey,
+		sender:     sender,
+	}, nil
+}
+
+type smsRequest struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
+
+func (s *smsGateway) SendMessage(ctx context.Context, to, text string) error {
+	ctx, span := s.tracer.Start(ctx, "smsGatewayRepo.SendMessage")
+	defer span.End()
+
+	body, err := json.Marshal(smsRequest{
+		From: s.sender,
+		To:   to,
+		Text: text,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("X-API-Key", s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	if err = doRequest(s.httpClient, req); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
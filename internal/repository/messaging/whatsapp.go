@@ -0,0 +1,105 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const defaultWhatsAppURL = "https://graph.facebook.com/v19.0"
+
+// whatsApp sends plain text messages through the WhatsApp Business Cloud API.
+type whatsApp struct {
+	httpClient    *http.Client
+	tracer        trace.Tracer
+	baseURL       string
+	token         string
+	phoneNumberID string
+}
+
+func newWhatsApp(httpClient *http.Client, baseURL, token, phoneNumberID string) (MessagingRepo, error) {
+	if token == "" || phoneNumberID == "" {
+		return nil, errors.New("whatsapp provider requires token and phone number id")
+	}
+
+	if baseURL == "" {
+		baseURL = defaultWhatsAppURL
+	}
+
+	return &whatsApp{
+		httpClient:    httpClient,
+		tracer:        otel.Tracer("whatsAppRepo"),
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		token:         token,
+		phoneNumberID: phoneNumberID,
+	}, nil
+}
+
+type whatsAppText struct {
+	Body string `json:"body"`
+}
+
+type whatsAppRequest struct {
+	MessagingProduct string       `json:"messaging_product"`
+	To               string       `json:"to"`
+	Type             string       `json:"type"`
+	Text             whatsAppText `json:"text"`
+}
+
+func (w *whatsApp) SendMessage(ctx context.Context, to, text string) error {
+	ctx, span := w.tracer.Start(ctx, "whatsAppRepo.SendMessage")
+	defer span.End()
+
+	body, err := json.Marshal(whatsAppRequest{
+		MessagingProduct: "whatsapp",
+		To:               strings.TrimPrefix(to, "+"),
+		Type:             "text",
+		Text:             whatsAppText{Body: text},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		fmt.Sprintf("%s/%s/messages", w.baseURL, w.phoneNumberID),
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+w.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	if err = doRequest(w.httpClient, req); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func doRequest(httpClient *http.Client, req *http.Request) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("messaging provider responded %d: %s", resp.StatusCode, respBody)
+	}
+
+	return nil
+}
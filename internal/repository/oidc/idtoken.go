@@ -0,0 +1,158 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/pkg"
+)
+
+// keysRefreshInterval limits how often an unknown kid makes us refetch the provider's JWKS
+const keysRefreshInterval = time.Minute
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce               string `json:"nonce"`
+	Email               string `json:"email"`
+	EmailVerified       bool   `json:"email_verified"`
+	PhoneNumber         string `json:"phone_number"`
+	PhoneNumberVerified bool   `json:"phone_number_verified"`
+	GivenName           string `json:"given_name"`
+	FamilyName          string `json:"family_name"`
+}
+
+func (p *provider) verifyIDToken(ctx context.Context, rawIDToken, nonce string) (*model.ExternalIdentity, error) {
+	var claims idTokenClaims
+
+	_, err := jwt.ParseWithClaims(
+		rawIDToken,
+		&claims,
+		func(token *jwt.Token) (any, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.keys.get(ctx, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256", "ES256", "ES384", "EdDSA"}),
+		jwt.WithIssuer(p.cfg.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Nonce != nonce {
+		return nil, errors.New("id token nonce mismatch")
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("id token has no subject")
+	}
+
+	return &model.ExternalIdentity{
+		Provider:      p.cfg.Name,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Phone:         claims.PhoneNumber,
+		PhoneVerified: claims.PhoneNumberVerified,
+		FirstName:     claims.GivenName,
+		LastName:      claims.FamilyName,
+	}, nil
+}
+
+type keyCache struct {
+	httpClient *http.Client
+	jwksURI    string
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	refreshedAt time.Time
+}
+
+func newKeyCache(httpClient *http.Client, jwksURI string) *keyCache {
+	return &keyCache{
+		httpClient: httpClient,
+		jwksURI:    jwksURI,
+	}
+}
+
+// get returns the key by kid. Unknown kids trigger a refetch, so provider key rotation is picked up.
+func (k *keyCache) get(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if key, ok := k.lookup(kid); ok {
+		return key, nil
+	}
+
+	if time.Since(k.refreshedAt) < keysRefreshInterval {
+		return nil, pkg.ErrUnknownJWTKey
+	}
+
+	if err := k.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	if key, ok := k.lookup(kid); ok {
+		return key, nil
+	}
+
+	return nil, pkg.ErrUnknownJWTKey
+}
+
+// lookup falls back to the only key when the token has no kid
+func (k *keyCache) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(k.keys) == 1 {
+		for _, key := range k.keys {
+			return key, true
+		}
+	}
+
+	key, ok := k.keys[kid]
+
+	return key, ok
+}
+
+func (k *keyCache) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.jwksURI, nil)
+	if err != nil {
+		return err
+	}
+
+	var set pkg.JWKS
+	if err = getJSON(k.httpClient, req, &set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		pub, err := jwk.PublicKey()
+		if err != nil {
+			// providers may publish key types we do not use, skip them
+			continue
+		}
+
+		keys[jwk.Kid] = pub
+	}
+
+	if len(keys) == 0 {
+		return fmt.Errorf("no usable keys at %s", k.jwksURI)
+	}
+
+	k.keys = keys
+	k.refreshedAt = time.Now()
+
+	return nil
+}
@@ -0,0 +1,243 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ProviderConfig struct {
+	Name         string   `json:"name"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	RedirectURL  string   `json:"redirect_url"`
+	Scopes       []string `json:"scopes"`
+}
+
+// ParseProviders reads provider configs from a JSON array, e.g. the OIDC_PROVIDERS env.
+func ParseProviders(raw string) ([]ProviderConfig, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var res []ProviderConfig
+	if err := json.Unmarshal([]byte(raw), &res); err != nil {
+		return nil, fmt.Errorf("parse oidc providers: %w", err)
+	}
+
+	return res, nil
+}
+
+type oidcRepo struct {
+	httpClient *http.Client
+	tracer     trace.Tracer
+	providers  map[string]*provider
+}
+
+func NewOIDCRepo(configs []ProviderConfig) (OIDCRepo, error) {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	repo := &oidcRepo{
+		httpClient: httpClient,
+		tracer:     otel.Tracer("oidcRepo"),
+		providers:  make(map[string]*provider, len(configs)),
+	}
+
+	for _, cfg := range configs {
+		if cfg.Name == "" || cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+			return nil, errors.New("oidc provider requires name, issuer, client_id and redirect_url")
+		}
+
+		if _, ok := repo.providers[cfg.Name]; ok {
+			return nil, fmt.Errorf("duplicate oidc provider %q", cfg.Name)
+		}
+
+		repo.providers[cfg.Name] = &provider{
+			cfg:        cfg,
+			httpClient: httpClient,
+		}
+	}
+
+	return repo, nil
+}
+
+func (o *oidcRepo) Providers() []string {
+	res := make([]string, 0, len(o.providers))
+	for name := range o.providers {
+		res = append(res, name)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func (o *oidcRepo) AuthCodeURL(ctx context.Context, providerName, state, nonce, codeChallenge string) (string, error) {
+	ctx, span := o.tracer.Start(ctx, "oidcRepo.AuthCodeURL")
+	defer span.End()
+
+	p, ok := o.providers[providerName]
+	if !ok {
+		return "", model.ErrOIDCUnknownProvider
+	}
+
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	scopes := append([]string{"openid"}, p.cfg.Scopes...)
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return meta.AuthorizationEndpoint + sep + query.Encode(), nil
+}
+
+type tokenResponse struct {
+	IDToken     string `json:"id_token"`
+	AccessToken string `json:"access_token"`
+}
+
+func (o *oidcRepo) Exchange(ctx context.Context, providerName, code, codeVerifier, nonce string) (*model.ExternalIdentity, error) {
+	ctx, span := o.tracer.Start(ctx, "oidcRepo.Exchange")
+	defer span.End()
+
+	p, ok := o.providers[providerName]
+	if !ok {
+		return nil, model.ErrOIDCUnknownProvider
+	}
+
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("code_verifier", codeVerifier)
+
+	if p.cfg.ClientSecret != "" {
+		form.Set("client_secret", p.cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var tokens tokenResponse
+	if err = getJSON(o.httpClient, req, &tokens); err != nil {
+		span.RecordError(err)
+		return nil, model.ErrOIDCFailed.WithErr(err)
+	}
+
+	if tokens.IDToken == "" {
+		return nil, model.ErrOIDCFailed.WithErr(errors.New("token response has no id_token"))
+	}
+
+	identity, err := p.verifyIDToken(ctx, tokens.IDToken, nonce)
+	if err != nil {
+		span.RecordError(err)
+		return nil, model.ErrOIDCFailed.WithErr(err)
+	}
+
+	return identity, nil
+}
+
+// provider caches the discovery document and signing keys of one issuer.
+type provider struct {
+	cfg        ProviderConfig
+	httpClient *http.Client
+
+	mu   sync.Mutex
+	meta *discovery
+	keys *keyCache
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func (p *provider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.meta != nil {
+		return p.meta, nil
+	}
+
+	wellKnown := strings.TrimRight(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta discovery
+	if err = getJSON(p.httpClient, req, &meta); err != nil {
+		return nil, model.ErrOIDCFailed.WithErr(err)
+	}
+
+	if meta.Issuer != p.cfg.Issuer {
+		return nil, model.ErrOIDCFailed.WithErr(fmt.Errorf("issuer mismatch: %q != %q", meta.Issuer, p.cfg.Issuer))
+	}
+
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, model.ErrOIDCFailed.WithErr(errors.New("incomplete discovery document"))
+	}
+
+	p.meta = &meta
+	p.keys = newKeyCache(p.httpClient, meta.JWKSURI)
+
+	return p.meta, nil
+}
+
+func getJSON(httpClient *http.Client, req *http.Request, dst any) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("oidc provider responded %d: %s", resp.StatusCode, respBody)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(dst)
+}
@@ -0,0 +1,301 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/podpivasniki1488/assyl-backend/pkg"
+)
+
+const testClientID = "assyl"
+
+type fakeAuthRequest struct {
+	nonce         string
+	codeChallenge string
+	email         string
+}
+
+// fakeIdP is an OpenID provider that signs in the login_hint without asking anything.
+// claims may change the ID token before it is signed.
+type fakeIdP struct {
+	t      *testing.T
+	srv    *httptest.Server
+	keys   *pkg.JWTKeySet
+	claims func(jwt.MapClaims)
+
+	mu    sync.Mutex
+	codes map[string]fakeAuthRequest
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := pkg.NewJWTKeySet(pkg.JWTConfig{
+		Algorithm:     pkg.JWTAlgEdDSA,
+		SigningKeyPEM: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idp := &fakeIdP{
+		t:      t,
+		keys:   keys,
+		claims: func(jwt.MapClaims) {},
+		codes:  make(map[string]fakeAuthRequest),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(w, map[string]any{
+			"issuer":                 idp.srv.URL,
+			"authorization_endpoint": idp.srv.URL + "/authorize",
+			"token_endpoint":         idp.srv.URL + "/token",
+			"jwks_uri":               idp.srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(w, idp.keys.JWKS())
+	})
+	mux.HandleFunc("GET /authorize", idp.authorize)
+	mux.HandleFunc("POST /token", idp.token)
+
+	idp.srv = httptest.NewServer(mux)
+	t.Cleanup(idp.srv.Close)
+
+	return idp
+}
+
+func (f *fakeIdP) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	code := base64.RawURLEncoding.EncodeToString([]byte(q.Get("state")))
+
+	f.mu.Lock()
+	f.codes[code] = fakeAuthRequest{
+		nonce:         q.Get("nonce"),
+		codeChallenge: q.Get("code_challenge"),
+		email:         q.Get("login_hint"),
+	}
+	f.mu.Unlock()
+
+	redirectURI, _ := url.Parse(q.Get("redirect_uri"))
+	params := redirectURI.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	redirectURI.RawQuery = params.Encode()
+
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (f *fakeIdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	req, ok := f.codes[r.PostForm.Get("code")]
+	delete(f.codes, r.PostForm.Get("code"))
+	f.mu.Unlock()
+
+	challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(challenge[:]) != req.codeChallenge {
+		w.WriteHeader(http.StatusBadRequest)
+		writeTestJSON(w, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            f.srv.URL,
+		"aud":            testClientID,
+		"sub":            "fake|" + req.email,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          req.nonce,
+		"email":          req.email,
+		"email_verified": true,
+		"given_name":     "Fake",
+		"family_name":    "Resident",
+	}
+	f.claims(claims)
+
+	idToken, err := f.keys.Sign(claims)
+	if err != nil {
+		f.t.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	writeTestJSON(w, map[string]string{"id_token": idToken, "access_token": "token"})
+}
+
+func writeTestJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// login runs the authorization request and returns the code the provider redirected back with.
+func (f *fakeIdP) login(t *testing.T, repo OIDCRepo, email, state, nonce, verifier string) string {
+	t.Helper()
+
+	challenge := sha256.Sum256([]byte(verifier))
+
+	authURL, err := repo.AuthCodeURL(context.Background(), "fake", state, nonce, base64.RawURLEncoding.EncodeToString(challenge[:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authURL += "&login_hint=" + url.QueryEscape(email)
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if callback.Query().Get("state") != state {
+		t.Fatalf("callback state = %q, want %q", callback.Query().Get("state"), state)
+	}
+
+	return callback.Query().Get("code")
+}
+
+func newTestRepo(t *testing.T, idp *fakeIdP) OIDCRepo {
+	t.Helper()
+
+	repo, err := NewOIDCRepo([]ProviderConfig{{
+		Name:        "fake",
+		Issuer:      idp.srv.URL,
+		ClientID:    testClientID,
+		RedirectURL: "https://assyl.example.com/v1/auth/oidc/fake/callback",
+		Scopes:      []string{"email"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return repo
+}
+
+func TestExchange(t *testing.T) {
+	idp := newFakeIdP(t)
+	repo := newTestRepo(t, idp)
+
+	code := idp.login(t, repo, "resident@example.com", "state-1", "nonce-1", "verifier-1")
+
+	identity, err := repo.Exchange(context.Background(), "fake", code, "verifier-1", "nonce-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if identity.Provider != "fake" || identity.Subject != "fake|resident@example.com" ||
+		identity.Email != "resident@example.com" || !identity.EmailVerified ||
+		identity.FirstName != "Fake" || identity.LastName != "Resident" {
+		t.Errorf("unexpected identity %+v", identity)
+	}
+}
+
+func TestExchangeUnverifiedEmail(t *testing.T) {
+	idp := newFakeIdP(t)
+	idp.claims = func(c jwt.MapClaims) {
+		c["email_verified"] = false
+	}
+	repo := newTestRepo(t, idp)
+
+	code := idp.login(t, repo, "resident@example.com", "state-1", "nonce-1", "verifier-1")
+
+	identity, err := repo.Exchange(context.Background(), "fake", code, "verifier-1", "nonce-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an unverified email must never be used to link an existing account
+	if identity.EmailVerified {
+		t.Error("email reported as verified")
+	}
+}
+
+func TestExchangeRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   func(jwt.MapClaims)
+		verifier string
+		nonce    string
+	}{
+		{name: "wrong pkce verifier", verifier: "other-verifier"},
+		{name: "wrong nonce", nonce: "other-nonce"},
+		{name: "other audience", claims: func(c jwt.MapClaims) { c["aud"] = "other-client" }},
+		{name: "other issuer", claims: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "expired", claims: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "no subject", claims: func(c jwt.MapClaims) { delete(c, "sub") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newFakeIdP(t)
+			if tt.claims != nil {
+				idp.claims = tt.claims
+			}
+			repo := newTestRepo(t, idp)
+
+			code := idp.login(t, repo, "resident@example.com", "state-1", "nonce-1", "verifier-1")
+
+			verifier, nonce := "verifier-1", "nonce-1"
+			if tt.verifier != "" {
+				verifier = tt.verifier
+			}
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+
+			if identity, err := repo.Exchange(context.Background(), "fake", code, verifier, nonce); err == nil {
+				t.Fatalf("login accepted: %+v", identity)
+			}
+		})
+	}
+}
+
+func TestExchangeUnknownProvider(t *testing.T) {
+	idp := newFakeIdP(t)
+	repo := newTestRepo(t, idp)
+
+	if _, err := repo.Exchange(context.Background(), "other", "code", "verifier", "nonce"); err == nil {
+		t.Fatal("unknown provider accepted")
+	}
+}
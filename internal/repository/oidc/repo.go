@@ -0,0 +1,16 @@
+package oidc
+
+import (
+	"context"
+
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+type OIDCRepo interface {
+	// Providers returns the names of configured providers
+	Providers() []string
+	// AuthCodeURL builds the authorization request with PKCE (S256) and nonce
+	AuthCodeURL(ctx context.Context, provider, state, nonce, codeChallenge string) (string, error)
+	// Exchange redeems the authorization code and verifies the returned ID token
+	Exchange(ctx context.Context, provider, code, codeVerifier, nonce string) (*model.ExternalIdentity, error)
+}
@@ -3,10 +3,17 @@ package repository
 import (
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
 	"github.com/podpivasniki1488/assyl-backend/internal/repository/apartment"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/binding"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/building"
 	"github.com/podpivasniki1488/assyl-backend/internal/repository/channel"
 	"github.com/podpivasniki1488/assyl-backend/internal/repository/chat"
 	"github.com/podpivasniki1488/assyl-backend/internal/repository/email"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/erasure"
 	"github.com/podpivasniki1488/assyl-backend/internal/repository/feedback"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/invitation"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/member"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/messaging"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/oidc"
 	"github.com/podpivasniki1488/assyl-backend/internal/repository/order"
 	"github.com/podpivasniki1488/assyl-backend/internal/repository/reservation"
 	"github.com/podpivasniki1488/assyl-backend/internal/repository/slot"
@@ -19,6 +26,7 @@ import (
 type Repository struct {
 	UserRepo        user.UserRepo
 	EmailRepo       email.EmailRepo
+	MessagingRepo   messaging.MessagingRepo
 	ApartmentRepo   apartment.ApartmentRepo
 	ReservationRepo reservation.ReservationRepo
 	ChannelRepo     channel.ChanRepo
@@ -26,6 +34,12 @@ type Repository struct {
 	OrderRepo       order.OrderRepo
 	ChatRepo        chat.ChatRepo
 	SlotRepo        slot.SlotRepo
+	InvitationRepo  invitation.InvitationRepo
+	MemberRepo      member.MemberRepo
+	BuildingRepo    building.BuildingRepo
+	BindingRepo     binding.BindingRepo
+	OIDCRepo        oidc.OIDCRepo
+	ErasureRepo     erasure.ErasureRepo
 }
 
 func MustInitDb(dsn string) *gorm.DB {
@@ -33,7 +47,8 @@ func MustInitDb(dsn string) *gorm.DB {
 		postgres.Open(
 			dsn,
 		),
-		&gorm.Config{},
+		// unique and foreign key violations come back as gorm.ErrDuplicatedKey / gorm.ErrForeignKeyViolated
+		&gorm.Config{TranslateError: true},
 	)
 	if err != nil {
 		panic(err)
@@ -41,6 +56,9 @@ func MustInitDb(dsn string) *gorm.DB {
 
 	if err = db.AutoMigrate(
 		&model.User{},
+		&model.RecoveryCode{},
+		&model.Building{},
+		&model.Entrance{},
 		&model.Apartment{},
 		&model.CinemaReservation{},
 		&model.ChannelMessage{},
@@ -51,17 +69,49 @@ func MustInitDb(dsn string) *gorm.DB {
 		&model.SlotTemplate{},
 		&model.DailySlot{},
 		&model.ReservationSlot{},
+		&model.Invitation{},
+		&model.UserIdentity{},
+		&model.ApartmentMember{},
+		&model.ApartmentAudit{},
+		&model.ApartmentOwnership{},
+		&model.BindingRequest{},
+		&model.BindingDocument{},
+		&model.ErasureJob{},
 	); err != nil {
 		panic(err)
 	}
 
+	if err = building.Backfill(db); err != nil {
+		panic(err)
+	}
+
+	if err = member.Backfill(db); err != nil {
+		panic(err)
+	}
+
+	if err = apartment.Backfill(db); err != nil {
+		panic(err)
+	}
+
+	if err = slot.Backfill(db); err != nil {
+		panic(err)
+	}
+
 	return db
 }
 
-func NewRepository(db *gorm.DB, mongoClient *mongo.Client, debug bool, gmailUsername, gmailPsw string) *Repository {
+func NewRepository(
+	db *gorm.DB,
+	mongoClient *mongo.Client,
+	messagingRepo messaging.MessagingRepo,
+	oidcRepo oidc.OIDCRepo,
+	debug bool,
+	gmailUsername, gmailPsw string,
+) *Repository {
 	return &Repository{
 		UserRepo:        user.NewUserRepository(db, debug),
 		EmailRepo:       email.NewEmailRepo(gmailUsername, gmailPsw),
+		MessagingRepo:   messagingRepo,
 		ApartmentRepo:   apartment.NewApartmentRepo(db),
 		ReservationRepo: reservation.NewReservationRepository(db),
 		ChannelRepo:     channel.NewChanRepository(db),
@@ -69,5 +119,11 @@ func NewRepository(db *gorm.DB, mongoClient *mongo.Client, debug bool, gmailUser
 		OrderRepo:       order.NewOrderRepository(db),
 		SlotRepo:        slot.NewSlotRepo(db),
 		ChatRepo:        chat.NewChatRepo(db, mongoClient, debug),
+		InvitationRepo:  invitation.NewInvitationRepository(db, debug),
+		MemberRepo:      member.NewMemberRepository(db, debug),
+		BuildingRepo:    building.NewBuildingRepository(db, debug),
+		BindingRepo:     binding.NewBindingRepository(db, debug),
+		OIDCRepo:        oidcRepo,
+		ErasureRepo:     erasure.NewErasureRepository(db, mongoClient, debug),
 	}
 }
@@ -13,4 +13,15 @@ type SlotRepo interface {
 	GetDailySlots(ctx context.Context, date time.Time, tz *time.Location) ([]model.DailySlot, error)
 	GetFreeDailySlots(ctx context.Context, date time.Time, tz *time.Location) ([]model.DailySlot, error)
 	GetDailySlotIDsByPositions(ctx context.Context, date time.Time, positions []int16, tz *time.Location) (map[int16]uint64, error)
+
+	// FindTemplates returns all templates, inactive ones included, ordered by position
+	FindTemplates(ctx context.Context) ([]model.SlotTemplate, error)
+	FindTemplateByID(ctx context.Context, id int16) (*model.SlotTemplate, error)
+	// CreateTemplate returns ErrSlotTemplateExists if the code or position is taken
+	CreateTemplate(ctx context.Context, t *model.SlotTemplate) error
+	// UpdateTemplate saves the template and applies it to the daily slots that have not started yet
+	// in one transaction. Reserved slots keep their times, free slots that would overlap them are disabled.
+	UpdateTemplate(ctx context.Context, t *model.SlotTemplate, tz *time.Location) (*model.SlotTemplateChange, error)
+	// DeleteTemplate removes the template with its free future slots, reserved ones are only disabled
+	DeleteTemplate(ctx context.Context, t *model.SlotTemplate) (*model.SlotTemplateChange, error)
 }
@@ -39,34 +39,21 @@ func (r *slotRepo) EnsureDailySlots(ctx context.Context, date time.Time, tz *tim
 	}
 
 	// date (date-only): отрежем время
-	y, m, d := date.In(tz).Date()
-	dayStart := time.Date(y, m, d, 0, 0, 0, 0, tz)
+	dayStart := model.SlotAt(date, 0, tz)
 
-	toTime := func(h, min int) time.Time {
-		return time.Date(y, m, d, h, min, 0, 0, tz)
-	}
+	slots := make([]model.DailySlot, 0, len(templates))
+	for _, t := range templates {
+		start, err := model.ParseSlotTime(t.StartTime)
+		if err != nil {
+			return err
+		}
 
-	// ВАЖНО: start_time/end_time в templates храню как строки "10:00:00"
-	// Чтобы быстро, просто сопоставим по position.
-	// Если хочешь строго из БД time, можно парсить.
-	makeRangeByPosition := func(pos int16) (time.Time, time.Time) {
-		switch pos {
-		case 1:
-			return toTime(10, 0), toTime(12, 0)
-		case 2:
-			return toTime(14, 0), toTime(16, 0)
-		case 3:
-			return toTime(18, 0), toTime(20, 0)
-		case 4:
-			return toTime(22, 0), toTime(23, 30)
-		default:
-			return dayStart, dayStart
+		end, err := model.ParseSlotTime(t.EndTime)
+		if err != nil {
+			return err
 		}
-	}
 
-	slots := make([]model.DailySlot, 0, len(templates))
-	for _, t := range templates {
-		startAt, endAt := makeRangeByPosition(t.Position)
+		startAt, endAt := model.SlotAt(date, start, tz), model.SlotAt(date, end, tz)
 		slots = append(slots, model.DailySlot{
 			SlotDate:   dayStart,
 			TemplateID: t.ID,
@@ -77,12 +64,30 @@ func (r *slotRepo) EnsureDailySlots(ctx context.Context, date time.Time, tz *tim
 		})
 	}
 
+	if len(slots) == 0 {
+		return nil
+	}
+
+	// слоты, которые уже есть на эту дату, не трогаем: их меняет только изменение шаблона
 	if err = r.db.WithContext(ctx).
-		Clauses(clause.OnConflict{DoNothing: true}).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "slot_date"}, {Name: "template_id"}},
+			DoNothing: true,
+		}).
 		Create(&slots).Error; err != nil {
 		return model.ErrDBUnexpected.WithErr(err)
 	}
 
+	// слот нового шаблона может попасть на бронь, сохранившую время до изменения шаблонов
+	if err = r.db.WithContext(ctx).
+		Model(&model.DailySlot{}).
+		Where("slot_date = ? AND is_enabled = true", dayStart).
+		Where(freeSlot).
+		Where(overlapsReserved(gorm.Expr("daily_slots.start_at"), gorm.Expr("daily_slots.end_at"))).
+		Update("is_enabled", false).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
 	return nil
 }
 
@@ -92,7 +97,7 @@ func (r *slotRepo) GetDailySlots(ctx context.Context, date time.Time, tz *time.L
 
 	var ds []model.DailySlot
 	if err := r.db.WithContext(ctx).
-		Where("slot_date = ?", day).
+		Where("slot_date = ? AND is_enabled = true", day).
 		Order("position asc").
 		Find(&ds).Error; err != nil {
 		return nil, model.ErrDBUnexpected.WithErr(err)
@@ -135,7 +140,7 @@ func (r *slotRepo) GetDailySlotIDsByPositions(ctx context.Context, date time.Tim
 	err := r.db.WithContext(ctx).
 		Table("daily_slots").
 		Select("position, id").
-		Where("slot_date = ? AND position IN ?", day, positions).
+		Where("slot_date = ? AND position IN ? AND is_enabled = true", day, positions).
 		Scan(&rows).Error
 	if err != nil {
 		return nil, model.ErrDBUnexpected.WithErr(err)
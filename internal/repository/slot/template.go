@@ -0,0 +1,252 @@
+package slot
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// backfillQueries merge daily slots that were created more than once for the same day and template
+// before the unique index existed: reservations move to the first copy, the other copies are dropped.
+// The index is created here and not by AutoMigrate, which would fail on the duplicates.
+var backfillQueries = []string{`
+WITH dup AS (
+	SELECT id, min(id) OVER (PARTITION BY slot_date, template_id) AS keep_id
+	FROM daily_slots
+)
+UPDATE reservation_slots rs
+SET daily_slot_id = dup.keep_id
+FROM dup
+WHERE rs.daily_slot_id = dup.id AND dup.id <> dup.keep_id`, `
+DELETE FROM daily_slots ds
+USING daily_slots keep
+WHERE keep.slot_date = ds.slot_date AND keep.template_id = ds.template_id AND keep.id < ds.id`, `
+CREATE UNIQUE INDEX IF NOT EXISTS ux_daily_slots_date_template ON daily_slots (slot_date, template_id)`,
+}
+
+func Backfill(db *gorm.DB) error {
+	for _, q := range backfillQueries {
+		if err := db.Exec(q).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// futureSlots are the daily slots of the template that have not started yet.
+const futureSlots = "template_id = ? AND start_at > ?"
+
+// reservedSlot matches a daily slot that has a reservation.
+const reservedSlot = "EXISTS (SELECT 1 FROM reservation_slots rs WHERE rs.daily_slot_id = daily_slots.id)"
+
+const freeSlot = "NOT " + reservedSlot
+
+// overlapsReserved matches a daily slot that, placed at startAt-endAt, would overlap a reserved slot
+// of the same day. Reserved slots keep the times they were booked for, so after a template change
+// a free slot can land on them; the hall is single, such a slot must not be offered.
+func overlapsReserved(startAt, endAt any) clause.Expr {
+	return gorm.Expr(`EXISTS (
+		SELECT 1 FROM daily_slots o
+		JOIN reservation_slots rs ON rs.daily_slot_id = o.id
+		WHERE o.slot_date = daily_slots.slot_date AND o.id <> daily_slots.id AND o.start_at < ? AND o.end_at > ?
+	)`, endAt, startAt)
+}
+
+func (r *slotRepo) FindTemplates(ctx context.Context) ([]model.SlotTemplate, error) {
+	var t []model.SlotTemplate
+
+	if err := r.db.WithContext(ctx).
+		Order("position asc").
+		Find(&t).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return t, nil
+}
+
+func (r *slotRepo) FindTemplateByID(ctx context.Context, id int16) (*model.SlotTemplate, error) {
+	var t model.SlotTemplate
+
+	if err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&t).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrSlotTemplateNotFound
+		}
+
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return &t, nil
+}
+
+func (r *slotRepo) CreateTemplate(ctx context.Context, t *model.SlotTemplate) error {
+	if err := r.db.WithContext(ctx).Create(t).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return model.ErrSlotTemplateExists
+		}
+
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return nil
+}
+
+func (r *slotRepo) UpdateTemplate(ctx context.Context, t *model.SlotTemplate, tz *time.Location) (*model.SlotTemplateChange, error) {
+	change := &model.SlotTemplateChange{Template: *t}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Model(&model.SlotTemplate{}).
+			Where("id = ?", t.ID).
+			Select("code", "start_time", "end_time", "position", "is_active").
+			Updates(t).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return model.ErrSlotTemplateExists
+			}
+
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		if !t.IsActive {
+			return disableFutureSlots(tx, t.ID, change)
+		}
+
+		return syncFutureSlots(tx, t, tz, change)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+func (r *slotRepo) DeleteTemplate(ctx context.Context, t *model.SlotTemplate) (*model.SlotTemplateChange, error) {
+	change := &model.SlotTemplateChange{Template: *t}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := disableFutureSlots(tx, t.ID, change); err != nil {
+			return err
+		}
+
+		// past and reserved daily slots keep template_id as a record of what was booked
+		if err := tx.Where("id = ?", t.ID).Delete(&model.SlotTemplate{}).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// syncFutureSlots moves the slots that have not started yet to the template's times and position.
+// Reserved slots keep their times, the reservation was made for them, but follow the position.
+// Free slots whose new times overlap a reserved slot of the day are disabled.
+func syncFutureSlots(tx *gorm.DB, t *model.SlotTemplate, tz *time.Location, change *model.SlotTemplateChange) error {
+	now := time.Now()
+	startAt := gorm.Expr("(daily_slots.slot_date + CAST(? AS time)) AT TIME ZONE ?", t.StartTime, tz.String())
+	endAt := gorm.Expr("(daily_slots.slot_date + CAST(? AS time)) AT TIME ZONE ?", t.EndTime, tz.String())
+	overlaps := overlapsReserved(startAt, endAt)
+
+	if err := tx.
+		Model(&model.DailySlot{}).
+		Where(futureSlots, t.ID, now).
+		Where(freeSlot).
+		Where(overlaps).
+		Order("slot_date asc").
+		Pluck("slot_date", &change.ConflictDates).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+	change.DisabledSlots = int64(len(change.ConflictDates))
+
+	if change.DisabledSlots > 0 {
+		if err := tx.
+			Model(&model.DailySlot{}).
+			Where(futureSlots, t.ID, now).
+			Where(freeSlot).
+			Where(overlaps).
+			Updates(map[string]any{
+				"start_at":   startAt,
+				"end_at":     endAt,
+				"position":   t.Position,
+				"is_enabled": false,
+			}).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+	}
+
+	res := tx.
+		Model(&model.DailySlot{}).
+		Where(futureSlots, t.ID, now).
+		Where(freeSlot).
+		Where("NOT ?", overlaps).
+		Where("(start_at, end_at, position, is_enabled) IS DISTINCT FROM (?, ?, ?, true)", startAt, endAt, t.Position).
+		Updates(map[string]any{
+			"start_at":   startAt,
+			"end_at":     endAt,
+			"position":   t.Position,
+			"is_enabled": true,
+		})
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+	change.UpdatedSlots = res.RowsAffected
+
+	if err := tx.
+		Model(&model.DailySlot{}).
+		Where(futureSlots, t.ID, now).
+		Where(reservedSlot).
+		Updates(map[string]any{
+			"position":   t.Position,
+			"is_enabled": true,
+		}).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	if err := tx.
+		Model(&model.DailySlot{}).
+		Where(futureSlots, t.ID, now).
+		Where(reservedSlot).
+		Where("(start_at, end_at) IS DISTINCT FROM (?, ?)", startAt, endAt).
+		Count(&change.ReservedSlots).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return nil
+}
+
+// disableFutureSlots removes the free slots of the template that have not started yet.
+// Reserved ones stay for their reservations but are disabled, so the position can be reused.
+func disableFutureSlots(tx *gorm.DB, templateID int16, change *model.SlotTemplateChange) error {
+	now := time.Now()
+
+	res := tx.
+		Where(futureSlots, templateID, now).
+		Where(freeSlot).
+		Delete(&model.DailySlot{})
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+	change.RemovedSlots = res.RowsAffected
+
+	res = tx.
+		Model(&model.DailySlot{}).
+		Where(futureSlots, templateID, now).
+		Where(reservedSlot).
+		Update("is_enabled", false)
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+	change.ReservedSlots = res.RowsAffected
+
+	return nil
+}
@@ -2,16 +2,43 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/protopb"
 )
 
 type UserRepo interface {
 	FindById(ctx context.Context, id uuid.UUID) (*model.User, error)
 	FindByUsername(ctx context.Context, username string) (*model.User, error)
 	FindByApartmentId(ctx context.Context, apartmentId uuid.UUID) ([]model.User, error)
-	DeleteByUsername(ctx context.Context, username string) error
+	FindByRoles(ctx context.Context, roles ...protopb.Role) ([]model.User, error)
+	FindByFilters(ctx context.Context, req *model.GetUsersRequest) ([]model.User, int64, error)
 	CreateUser(ctx context.Context, user *model.User) error
+	// UpdateUser saves the user except DisabledAt, which only SetDisabled changes
 	UpdateUser(ctx context.Context, user *model.User) (*model.User, error)
+	// SetDisabled disables the user, or enables it again with a nil disabledAt
+	SetDisabled(ctx context.Context, userID uuid.UUID, disabledAt *time.Time) error
+	// ChangeUsername swaps username and its type in one statement, only if the user still has oldUsername.
+	// ErrUserAlreadyExists if the new username is taken.
+	ChangeUsername(ctx context.Context, userID uuid.UUID, oldUsername, newUsername string, usernameType int) error
+	// ReplaceRecoveryCodes drops all recovery codes of the user and stores the new ones
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	// UseRecoveryCode marks an unused code as used, ErrTwoFactorInvalid if there is no such code
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error
+	// FindWithTotpSecret returns every user that has a TOTP secret stored
+	FindWithTotpSecret(ctx context.Context) ([]model.User, error)
+	// ReplaceTotpSecret stores newSecret only if the user still has oldSecret
+	ReplaceTotpSecret(ctx context.Context, userID uuid.UUID, oldSecret, newSecret string) error
+	FindIdentity(ctx context.Context, provider, subject string) (*model.UserIdentity, error)
+	CreateIdentity(ctx context.Context, identity *model.UserIdentity) error
+	// CreateUserWithIdentity creates the user and links the external identity in one transaction
+	CreateUserWithIdentity(ctx context.Context, user *model.User, identity *model.UserIdentity) error
+	// TakeOverWithIdentity hands an unconfirmed account to the owner of a verified external identity:
+	// the password is replaced, 2FA and recovery codes are dropped, the role is reset to GUEST, apartment
+	// ownership, memberships, pending binding requests and invitations are dropped, the user is approved
+	// and the identity linked, all in one transaction. ErrUserApproved if the account was confirmed or
+	// disabled in the meantime.
+	TakeOverWithIdentity(ctx context.Context, userID uuid.UUID, password string, identity *model.UserIdentity) error
 }
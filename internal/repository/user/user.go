@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository/apartment"
+	"github.com/podpivasniki1488/assyl-backend/protopb"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
@@ -68,6 +72,97 @@ func (u *userRepository) FindByApartmentId(ctx context.Context, apartmentId uuid
 	return users, nil
 }
 
+func (u *userRepository) FindByRoles(ctx context.Context, roles ...protopb.Role) ([]model.User, error) {
+	ctx, span := u.tracer.Start(ctx, "userRepository.FindByRoles")
+	defer span.End()
+
+	query := u.db.
+		WithContext(ctx).
+		Where("role_id IN ?", roles)
+
+	if u.debug {
+		query = query.Debug()
+	}
+
+	var users []model.User
+	if err := query.Find(&users).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return users, nil
+}
+
+// likeEscaper makes the search text match literally inside a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (u *userRepository) FindByFilters(ctx context.Context, req *model.GetUsersRequest) ([]model.User, int64, error) {
+	ctx, span := u.tracer.Start(ctx, "userRepository.FindByFilters")
+	defer span.End()
+
+	query := u.db.WithContext(ctx).Model(&model.User{})
+
+	if req.Role != nil {
+		query = query.Where("role_id = ?", *req.Role)
+	}
+
+	if req.IsApproved != nil {
+		query = query.Where("is_approved = ?", *req.IsApproved)
+	}
+
+	if req.IsDisabled != nil {
+		if *req.IsDisabled {
+			query = query.Where("disabled_at IS NOT NULL")
+		} else {
+			query = query.Where("disabled_at IS NULL")
+		}
+	}
+
+	if req.ApartmentID != uuid.Nil {
+		query = query.Where("apartment_id = ?", req.ApartmentID)
+	}
+
+	if req.BuildingID != uuid.Nil {
+		query = query.Where(
+			"apartment_id IN (SELECT a.id FROM apartments a JOIN entrances e ON e.id = a.entrance_id WHERE e.building_id = ?)",
+			req.BuildingID,
+		)
+	}
+
+	if req.UsernameType != 0 {
+		query = query.Where("username_type = ?", req.UsernameType)
+	}
+
+	if req.Search != "" {
+		pattern := "%" + likeEscaper.Replace(req.Search) + "%"
+		query = query.Where(
+			`username ILIKE ? ESCAPE '\' OR first_name ILIKE ? ESCAPE '\' OR last_name ILIKE ? ESCAPE '\'`,
+			pattern, pattern, pattern,
+		)
+	}
+
+	if u.debug {
+		query = query.Debug()
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	page := req.Pagination.Normalize()
+
+	var users []model.User
+	if err := query.
+		Order("username asc").
+		Offset(page.Offset()).
+		Limit(page.PageSize).
+		Find(&users).Error; err != nil {
+		return nil, 0, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return users, total, nil
+}
+
 func (u *userRepository) FindByUsername(ctx context.Context, username string) (*model.User, error) {
 	ctx, span := u.tracer.Start(ctx, "userRepository.FindByusername")
 	defer span.End()
@@ -111,7 +206,9 @@ func (u *userRepository) UpdateUser(ctx context.Context, user *model.User) (*mod
 
 	user.ID = findResp.ID
 
-	if err := u.db.WithContext(ctx).Save(user).Error; err != nil {
+	// the user is often loaded before a slow step such as an OTP check, saving the stale flag
+	// would undo an admin disabling the account in the meantime
+	if err := u.db.WithContext(ctx).Omit("disabled_at").Save(user).Error; err != nil {
 		return nil, model.ErrDBUnexpected.WithErr(err)
 	}
 
@@ -126,6 +223,69 @@ func (u *userRepository) UpdateUser(ctx context.Context, user *model.User) (*mod
 	return &updateResp, nil
 }
 
+func (u *userRepository) SetDisabled(ctx context.Context, userID uuid.UUID, disabledAt *time.Time) error {
+	ctx, span := u.tracer.Start(ctx, "userRepository.SetDisabled")
+	defer span.End()
+
+	query := u.db.
+		WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", userID)
+
+	if u.debug {
+		query = query.Debug()
+	}
+
+	res := query.Update("disabled_at", disabledAt)
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return model.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (u *userRepository) ChangeUsername(
+	ctx context.Context,
+	userID uuid.UUID,
+	oldUsername, newUsername string,
+	usernameType int,
+) error {
+	ctx, span := u.tracer.Start(ctx, "userRepository.ChangeUsername")
+	defer span.End()
+
+	query := u.db.
+		WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ? AND username = ?", userID, oldUsername)
+
+	if u.debug {
+		query = query.Debug()
+	}
+
+	// idx_user_username stays the source of truth for uniqueness, even if two users race for the same username
+	res := query.Updates(map[string]any{
+		"username":      newUsername,
+		"username_type": usernameType,
+	})
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrDuplicatedKey) {
+			return model.ErrUserAlreadyExists
+		}
+
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return model.ErrUserNotFound
+	}
+
+	return nil
+}
+
 func (u *userRepository) CreateUser(ctx context.Context, user *model.User) error {
 	ctx, span := u.tracer.Start(ctx, "userRepository.CreateUser")
 	defer span.End()
@@ -151,22 +311,274 @@ func (u *userRepository) CreateUser(ctx context.Context, user *model.User) error
 	return nil
 }
 
-func (u *userRepository) DeleteByUsername(ctx context.Context, username string) error {
-	ctx, span := u.tracer.Start(ctx, "userRepository.DeleteByUsername")
+func (u *userRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	ctx, span := u.tracer.Start(ctx, "userRepository.ReplaceRecoveryCodes")
+	defer span.End()
+
+	db := u.db.WithContext(ctx)
+	if u.debug {
+		db = db.Debug()
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		if len(codeHashes) == 0 {
+			return nil
+		}
+
+		codes := make([]model.RecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, model.RecoveryCode{
+				UserID:   userID,
+				CodeHash: hash,
+			})
+		}
+
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return nil
+}
+
+func (u *userRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	ctx, span := u.tracer.Start(ctx, "userRepository.UseRecoveryCode")
 	defer span.End()
 
-	var user model.User
 	query := u.db.
 		WithContext(ctx).
-		Where("username = ?", username)
+		Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash)
 
 	if u.debug {
 		query = query.Debug()
 	}
 
-	if err := query.Delete(&user).Error; err != nil {
+	res := query.Update("used_at", time.Now())
+	if res.Error != nil {
+		return model.ErrDBUnexpected.WithErr(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return model.ErrTwoFactorInvalid
+	}
+
+	return nil
+}
+
+func (u *userRepository) FindWithTotpSecret(ctx context.Context) ([]model.User, error) {
+	ctx, span := u.tracer.Start(ctx, "userRepository.FindWithTotpSecret")
+	defer span.End()
+
+	var users []model.User
+	if err := u.db.
+		WithContext(ctx).
+		Where("totp_secret <> ''").
+		Find(&users).Error; err != nil {
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return users, nil
+}
+
+func (u *userRepository) ReplaceTotpSecret(ctx context.Context, userID uuid.UUID, oldSecret, newSecret string) error {
+	ctx, span := u.tracer.Start(ctx, "userRepository.ReplaceTotpSecret")
+	defer span.End()
+
+	query := u.db.
+		WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ? AND totp_secret = ?", userID, oldSecret)
+
+	if u.debug {
+		query = query.Debug()
+	}
+
+	// a secret changed in the meantime was already written by the current code, nothing to replace
+	if err := query.Update("totp_secret", newSecret).Error; err != nil {
 		return model.ErrDBUnexpected.WithErr(err)
 	}
 
 	return nil
 }
+
+func (u *userRepository) FindIdentity(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	ctx, span := u.tracer.Start(ctx, "userRepository.FindIdentity")
+	defer span.End()
+
+	query := u.db.
+		WithContext(ctx).
+		Where("provider = ? AND subject = ?", provider, subject)
+
+	if u.debug {
+		query = query.Debug()
+	}
+
+	var identity model.UserIdentity
+	if err := query.First(&identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.ErrRecordNotFound
+		}
+
+		return nil, model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return &identity, nil
+}
+
+func (u *userRepository) CreateIdentity(ctx context.Context, identity *model.UserIdentity) error {
+	ctx, span := u.tracer.Start(ctx, "userRepository.CreateIdentity")
+	defer span.End()
+
+	query := u.db.WithContext(ctx)
+	if u.debug {
+		query = query.Debug()
+	}
+
+	if err := query.Create(identity).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return nil
+}
+
+func (u *userRepository) CreateUserWithIdentity(ctx context.Context, user *model.User, identity *model.UserIdentity) error {
+	ctx, span := u.tracer.Start(ctx, "userRepository.CreateUserWithIdentity")
+	defer span.End()
+
+	db := u.db.WithContext(ctx)
+	if u.debug {
+		db = db.Debug()
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.User{}).Where("username = ?", user.Username).Count(&count).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		if count > 0 {
+			return model.ErrUserAlreadyExists
+		}
+
+		if err := tx.Create(user).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		identity.UserID = user.ID
+
+		if err := tx.Create(identity).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// dropHousehold removes everything the user got through an apartment: ownership, memberships,
+// pending binding requests and the invitations they handed out.
+func dropHousehold(tx *gorm.DB, userID uuid.UUID) error {
+	var owned []uuid.UUID
+	if err := tx.
+		Model(&model.Apartment{}).
+		Where("owner_id = ?", userID).
+		Pluck("id", &owned).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	for _, apartmentID := range owned {
+		if _, err := apartment.SetOwner(tx, apartmentID, nil, apartment.OwnedBy(userID)); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Where("user_id = ?", userID).Delete(&model.ApartmentMember{}).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	now := time.Now()
+
+	if err := tx.
+		Model(&model.BindingRequest{}).
+		Where("user_id = ? AND status = ?", userID, model.BindingStatusPending).
+		Updates(map[string]any{
+			"status":      model.BindingStatusRejected,
+			"reason":      "the account was taken over by the owner of its username",
+			"reviewed_at": now,
+			"updated_at":  now,
+		}).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	if err := tx.
+		Model(&model.Invitation{}).
+		Where("created_by = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error; err != nil {
+		return model.ErrDBUnexpected.WithErr(err)
+	}
+
+	return nil
+}
+
+func (u *userRepository) TakeOverWithIdentity(
+	ctx context.Context,
+	userID uuid.UUID,
+	password string,
+	identity *model.UserIdentity,
+) error {
+	ctx, span := u.tracer.Start(ctx, "userRepository.TakeOverWithIdentity")
+	defer span.End()
+
+	db := u.db.WithContext(ctx)
+	if u.debug {
+		db = db.Debug()
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		res := tx.
+			Model(&model.User{}).
+			Where("id = ? AND is_approved = false AND disabled_at IS NULL", userID).
+			Updates(map[string]any{
+				"password":     password,
+				"is_approved":  true,
+				"totp_secret":  "",
+				"totp_enabled": false,
+				"role_id":      protopb.Role_GUEST,
+				"apartment_id": uuid.Nil,
+				"invited_by":   nil,
+			})
+		if res.Error != nil {
+			return model.ErrDBUnexpected.WithErr(res.Error)
+		}
+
+		if res.RowsAffected == 0 {
+			return model.ErrUserApproved
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		if err := dropHousehold(tx, userID); err != nil {
+			return err
+		}
+
+		identity.UserID = userID
+
+		if err := tx.Create(identity).Error; err != nil {
+			return model.ErrDBUnexpected.WithErr(err)
+		}
+
+		return nil
+	})
+}
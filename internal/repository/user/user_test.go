@@ -0,0 +1,24 @@
+package user
+
+import "testing"
+
+func TestLikeEscaper(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "ivan", want: "ivan"},
+		{in: "100%", want: `100\%`},
+		{in: "first_name", want: `first\_name`},
+		{in: `a\b`, want: `a\\b`},
+		{in: `\%_`, want: `\\\%\_`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := likeEscaper.Replace(tt.in); got != tt.want {
+				t.Errorf("likeEscaper.Replace(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	"github.com/google/uuid"
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
 	"github.com/podpivasniki1488/assyl-backend/internal/repository"
 	"go.opentelemetry.io/otel"
@@ -18,25 +21,329 @@ func NewApartmentService(repo *repository.Repository) Apartment {
 	return &apartment{repo, otel.Tracer("apartmentService")}
 }
 
-func (a *apartment) CreateApartment(ctx context.Context, req model.Apartment) error {
+func (a *apartment) CreateApartment(ctx context.Context, req model.ApartmentAddress) error {
 	ctx, span := a.trace.Start(ctx, "apartmentService.CreateApartment")
 	defer span.End()
 
-	if _, err := a.repo.ApartmentRepo.CreateApartment(ctx, req); err != nil {
+	entrance, err := resolveEntrance(ctx, a.repo, req)
+	if err != nil {
+		return err
+	}
+
+	if _, err = a.repo.ApartmentRepo.CreateApartment(ctx, model.Apartment{
+		EntranceID: &entrance.ID,
+		Floor:      req.Floor,
+		DoorNumber: req.DoorNumber,
+	}); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (a *apartment) GetApartment(ctx context.Context, req model.Apartment) (*model.Apartment, error) {
-	ctx, span := a.trace.Start(ctx, "apartmentService.GetApartment")
+func (a *apartment) ListApartments(ctx context.Context, req model.GetApartmentsRequest) (model.Page[model.ApartmentDetails], error) {
+	ctx, span := a.trace.Start(ctx, "apartmentService.ListApartments")
+	defer span.End()
+
+	req.Pagination = req.Pagination.Normalize()
+
+	apartments, total, err := a.repo.ApartmentRepo.FindByFilters(ctx, &req)
+	if err != nil {
+		return model.Page[model.ApartmentDetails]{}, err
+	}
+
+	items := make([]model.ApartmentDetails, len(apartments))
+	for i := range apartments {
+		items[i].Apartment = apartments[i]
+	}
+
+	if err = fillAddress(ctx, a.repo, items); err != nil {
+		return model.Page[model.ApartmentDetails]{}, err
+	}
+
+	if req.WithResidents {
+		if err = a.fillResidents(ctx, items); err != nil {
+			return model.Page[model.ApartmentDetails]{}, err
+		}
+	}
+
+	return model.Page[model.ApartmentDetails]{
+		Items:    items,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
+
+func (a *apartment) GetApartmentDetails(ctx context.Context, id uuid.UUID) (*model.ApartmentDetails, error) {
+	ctx, span := a.trace.Start(ctx, "apartmentService.GetApartmentDetails")
+	defer span.End()
+
+	ap, err := a.repo.ApartmentRepo.GetApartmentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []model.ApartmentDetails{{Apartment: *ap}}
+	if err = fillAddress(ctx, a.repo, res); err != nil {
+		return nil, err
+	}
+
+	if err = a.fillResidents(ctx, res); err != nil {
+		return nil, err
+	}
+
+	return &res[0], nil
+}
+
+func (a *apartment) UpdateApartment(
+	ctx context.Context,
+	actorID, id uuid.UUID,
+	req model.UpdateApartmentRequest,
+) (*model.Apartment, error) {
+	ctx, span := a.trace.Start(ctx, "apartmentService.UpdateApartment")
 	defer span.End()
 
-	res, err := a.repo.ApartmentRepo.GetApartmentByFloorAndNum(ctx, req.Floor, req.DoorNumber)
+	ap, err := a.repo.ApartmentRepo.GetApartmentByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return res, nil
+	fields := make(map[string]any)
+	var changes []string
+
+	if req.BuildingID != uuid.Nil || req.Entrance != 0 {
+		entrance, err := a.moveEntrance(ctx, ap, req)
+		if err != nil {
+			return nil, err
+		}
+
+		if ap.EntranceID == nil || *ap.EntranceID != entrance.ID {
+			fields["entrance_id"] = entrance.ID
+			changes = append(changes, fmt.Sprintf("entrance_id: %s -> %s", entranceIDString(ap.EntranceID), entrance.ID))
+			ap.EntranceID = &entrance.ID
+		}
+	}
+
+	if req.Floor != nil && *req.Floor != ap.Floor {
+		fields["floor"] = *req.Floor
+		changes = append(changes, fmt.Sprintf("floor: %d -> %d", ap.Floor, *req.Floor))
+		ap.Floor = *req.Floor
+	}
+
+	if req.DoorNumber != nil && *req.DoorNumber != ap.DoorNumber {
+		fields["door_number"] = *req.DoorNumber
+		changes = append(changes, fmt.Sprintf("door_number: %d -> %d", ap.DoorNumber, *req.DoorNumber))
+		ap.DoorNumber = *req.DoorNumber
+	}
+
+	if req.Area != nil && *req.Area != ap.Area {
+		fields["area"] = *req.Area
+		changes = append(changes, fmt.Sprintf("area: %.2f -> %.2f", ap.Area, *req.Area))
+		ap.Area = *req.Area
+	}
+
+	if req.Rooms != nil && *req.Rooms != ap.Rooms {
+		fields["rooms"] = *req.Rooms
+		changes = append(changes, fmt.Sprintf("rooms: %d -> %d", ap.Rooms, *req.Rooms))
+		ap.Rooms = *req.Rooms
+	}
+
+	if req.CadastralNumber != nil && *req.CadastralNumber != ap.CadastralNumber {
+		// an empty value clears the number
+		if *req.CadastralNumber != "" && !model.ValidCadastralNumber(*req.CadastralNumber) {
+			return nil, model.ErrInvalidApartment.WithErr(fmt.Errorf("cadastral number %q", *req.CadastralNumber))
+		}
+
+		fields["cadastral_number"] = *req.CadastralNumber
+		changes = append(changes, fmt.Sprintf("cadastral_number: %q -> %q", ap.CadastralNumber, *req.CadastralNumber))
+		ap.CadastralNumber = *req.CadastralNumber
+	}
+
+	if req.Type != nil && *req.Type != ap.Type {
+		if !req.Type.IsValid() {
+			return nil, model.ErrInvalidApartment.WithErr(fmt.Errorf("type %q", *req.Type))
+		}
+
+		fields["type"] = *req.Type
+		changes = append(changes, fmt.Sprintf("type: %s -> %s", ap.Type, *req.Type))
+		ap.Type = *req.Type
+	}
+
+	if req.StorageUnit != nil && *req.StorageUnit != ap.StorageUnit {
+		fields["storage_unit"] = *req.StorageUnit
+		changes = append(changes, fmt.Sprintf("storage_unit: %q -> %q", ap.StorageUnit, *req.StorageUnit))
+		ap.StorageUnit = *req.StorageUnit
+	}
+
+	if len(fields) == 0 {
+		return ap, nil
+	}
+
+	if err = a.repo.ApartmentRepo.ChangeApartment(ctx, id, fields, &model.ApartmentAudit{
+		ApartmentID: id,
+		ActorID:     actorID,
+		Action:      model.ApartmentAuditUpdate,
+		Details:     strings.Join(changes, ", "),
+	}); err != nil {
+		return nil, err
+	}
+
+	return ap, nil
+}
+
+func (a *apartment) DeleteApartment(ctx context.Context, actorID, id uuid.UUID) error {
+	ctx, span := a.trace.Start(ctx, "apartmentService.DeleteApartment")
+	defer span.End()
+
+	ap, err := a.repo.ApartmentRepo.GetApartmentByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if ap.OwnerId != nil {
+		return model.ErrApartmentBound
+	}
+
+	return a.repo.ApartmentRepo.DeleteApartment(ctx, id, &model.ApartmentAudit{
+		ApartmentID: id,
+		ActorID:     actorID,
+		Action:      model.ApartmentAuditDelete,
+		Details:     fmt.Sprintf("entrance_id %s, floor %d, door_number %d", entranceIDString(ap.EntranceID), ap.Floor, ap.DoorNumber),
+	})
+}
+
+func (a *apartment) UnbindApartment(ctx context.Context, actorID, id uuid.UUID) error {
+	ctx, span := a.trace.Start(ctx, "apartmentService.UnbindApartment")
+	defer span.End()
+
+	ap, err := a.repo.ApartmentRepo.GetApartmentByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	residents, err := a.repo.ApartmentRepo.FindResidents(ctx, []uuid.UUID{id})
+	if err != nil {
+		return err
+	}
+
+	details := "owner: none"
+	if ap.OwnerId != nil {
+		details = "owner: " + ap.OwnerId.String()
+	}
+
+	names := make([]string, 0, len(residents))
+	for _, r := range residents {
+		names = append(names, r.Username)
+	}
+
+	if len(names) > 0 {
+		details += ", residents: " + strings.Join(names, ", ")
+	}
+
+	userIDs, err := a.repo.ApartmentRepo.Unbind(ctx, id, &model.ApartmentAudit{
+		ApartmentID: id,
+		ActorID:     actorID,
+		Action:      model.ApartmentAuditUnbind,
+		Details:     details,
+	})
+	if err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf("You are no longer bound to apartment %d (floor %d).", ap.DoorNumber, ap.Floor)
+	for _, userID := range userIDs {
+		user, err := a.repo.UserRepo.FindById(ctx, userID)
+		if err != nil || user.ID == uuid.Nil {
+			continue
+		}
+
+		_ = notifyUser(ctx, a.repo, *user, "Apartment unbound", text)
+	}
+
+	return nil
+}
+
+func (a *apartment) GetOwnershipHistory(ctx context.Context, id uuid.UUID) ([]model.ApartmentOwnership, error) {
+	ctx, span := a.trace.Start(ctx, "apartmentService.GetOwnershipHistory")
+	defer span.End()
+
+	// the history outlives deleted apartments, like the audit log
+	return a.repo.ApartmentRepo.FindOwnershipHistory(ctx, id)
+}
+
+func (a *apartment) GetApartmentAudit(
+	ctx context.Context,
+	id uuid.UUID,
+	page model.Pagination,
+) (model.Page[model.ApartmentAudit], error) {
+	ctx, span := a.trace.Start(ctx, "apartmentService.GetApartmentAudit")
+	defer span.End()
+
+	// the log outlives deleted apartments, so the apartment is not required to exist
+	page = page.Normalize()
+
+	entries, total, err := a.repo.ApartmentRepo.FindAudit(ctx, id, page)
+	if err != nil {
+		return model.Page[model.ApartmentAudit]{}, err
+	}
+
+	return model.Page[model.ApartmentAudit]{
+		Items:    entries,
+		Total:    total,
+		Page:     page.Page,
+		PageSize: page.PageSize,
+	}, nil
+}
+
+// moveEntrance resolves the new entrance of the apartment, a missing building means the current one.
+func (a *apartment) moveEntrance(ctx context.Context, ap *model.Apartment, req model.UpdateApartmentRequest) (*model.Entrance, error) {
+	addr := model.ApartmentAddress{
+		BuildingID: req.BuildingID,
+		Entrance:   req.Entrance,
+	}
+
+	if addr.BuildingID == uuid.Nil && ap.EntranceID != nil {
+		current, err := a.repo.BuildingRepo.FindEntrancesByIDs(ctx, []uuid.UUID{*ap.EntranceID})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(current) > 0 {
+			addr.BuildingID = current[0].BuildingID
+		}
+	}
+
+	return resolveEntrance(ctx, a.repo, addr)
+}
+
+func entranceIDString(id *uuid.UUID) string {
+	if id == nil {
+		return "none"
+	}
+
+	return id.String()
+}
+
+func (a *apartment) fillResidents(ctx context.Context, items []model.ApartmentDetails) error {
+	ids := make([]uuid.UUID, len(items))
+	byID := make(map[uuid.UUID]*model.ApartmentDetails, len(items))
+
+	for i := range items {
+		ids[i] = items[i].Id
+		byID[items[i].Id] = &items[i]
+	}
+
+	residents, err := a.repo.ApartmentRepo.FindResidents(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range residents {
+		if item, ok := byID[r.ApartmentID]; ok {
+			item.Residents = append(item.Residents, r)
+		}
+	}
+
+	return nil
 }
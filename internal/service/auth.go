@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/rand"
 	"net/mail"
 	"regexp"
 	"time"
@@ -13,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
 	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"github.com/podpivasniki1488/assyl-backend/pkg"
 	"github.com/podpivasniki1488/assyl-backend/protopb"
 	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/otel"
@@ -30,17 +30,33 @@ var phoneRegexp = regexp.MustCompile(`^\+[1-9]\d{7,14}$`)
 
 type authService struct {
 	repo        *repository.Repository
-	secretKey   string
+	jwtKeys     *pkg.JWTKeySet
 	tracer      trace.Tracer
 	redisClient *redis.Client
+	sessions    *sessionStore
+	otp         *otpManager
+	loginGuard  *loginGuard
+	totp        *totpManager
 }
 
-func NewAuthService(repo *repository.Repository, secretKey string, redisCli *redis.Client) Auth {
+func NewAuthService(
+	repo *repository.Repository,
+	jwtKeys *pkg.JWTKeySet,
+	redisCli *redis.Client,
+	sessions *sessionStore,
+	otp *otpManager,
+	loginGuard *loginGuard,
+	totp *totpManager,
+) Auth {
 	return &authService{
 		repo:        repo,
-		secretKey:   secretKey,
+		jwtKeys:     jwtKeys,
 		tracer:      otel.Tracer("authService"),
 		redisClient: redisCli,
+		sessions:    sessions,
+		otp:         otp,
+		loginGuard:  loginGuard,
+		totp:        totp,
 	}
 }
 
@@ -48,17 +64,82 @@ func (a *authService) Confirm(ctx context.Context, username, otpCode string) err
 	ctx, span := a.tracer.Start(ctx, "authService.Confirm")
 	defer span.End()
 
-	otp, err := a.redisClient.Get(ctx, username).Result()
+	if err := a.otp.Verify(ctx, model.OtpPurposeRegistration, username, otpCode); err != nil {
+		return err
+	}
+
+	u, err := a.repo.UserRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	u.IsApproved = true
+
+	if _, err = a.repo.UserRepo.UpdateUser(ctx, u); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *authService) ResendOtp(ctx context.Context, username string, purpose model.OtpPurpose) error {
+	ctx, span := a.tracer.Start(ctx, "authService.ResendOtp")
+	defer span.End()
+
+	// the response does not reveal whether the username is registered or approved:
+	// when there is nothing to resend only the cooldown is started
+	u, err := a.repo.UserRepo.FindByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			return a.otp.Throttle(ctx, purpose, username)
+		}
+
+		return err
+	}
+
+	switch purpose {
+	case model.OtpPurposeRegistration:
+		if u.IsApproved {
+			return a.otp.Throttle(ctx, purpose, username)
+		}
+	default:
+		// codes for other purposes are started by their own flows, resend only refreshes a pending one
+		pending, err := a.otp.Pending(ctx, purpose, username)
+		if err != nil {
+			return err
+		}
+
+		if !pending {
+			return a.otp.Throttle(ctx, purpose, username)
+		}
+	}
+
+	return a.otp.Send(ctx, purpose, *u)
+}
+
+func (a *authService) ForgotPassword(ctx context.Context, username string) error {
+	ctx, span := a.tracer.Start(ctx, "authService.ForgotPassword")
+	defer span.End()
+
+	u, err := a.repo.UserRepo.FindByUsername(ctx, username)
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			return errors.New("no otp code found, or u need register again")
+		// do not reveal whether the username is registered, unknown ones get the same cooldown
+		if errors.Is(err, model.ErrUserNotFound) {
+			return a.otp.Throttle(ctx, model.OtpPurposePasswordReset, username)
 		}
 
 		return err
 	}
 
-	if otp != otpCode {
-		return fmt.Errorf("otp code is not correct, sended otp %s, redis otp %s", otpCode, otp)
+	return a.otp.Send(ctx, model.OtpPurposePasswordReset, *u)
+}
+
+func (a *authService) ResetPassword(ctx context.Context, username, otpCode, newPassword string) error {
+	ctx, span := a.tracer.Start(ctx, "authService.ResetPassword")
+	defer span.End()
+
+	if err := a.otp.Verify(ctx, model.OtpPurposePasswordReset, username, otpCode); err != nil {
+		return err
 	}
 
 	u, err := a.repo.UserRepo.FindByUsername(ctx, username)
@@ -66,102 +147,350 @@ func (a *authService) Confirm(ctx context.Context, username, otpCode string) err
 		return err
 	}
 
+	hashedPsw, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	u.Password = string(hashedPsw)
+
+	// the code reached the user's email/phone, so the username is confirmed as well
 	u.IsApproved = true
 
 	if _, err = a.repo.UserRepo.UpdateUser(ctx, u); err != nil {
 		return err
 	}
 
+	if err = a.sessions.RevokeAll(ctx, u.ID); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func (a *authService) Login(ctx context.Context, user model.User) (token string, u *model.User, err error) {
+// Login checks the password. If the user has 2FA enabled, or must enroll because of the role,
+// no tokens are issued: the result carries a challenge token for CompleteTwoFactorLogin instead.
+func (a *authService) Login(ctx context.Context, user model.User, client model.ClientInfo) (*model.LoginResult, error) {
 	ctx, span := a.tracer.Start(ctx, "authService.Login")
 	defer span.End()
 
-	u, err = a.repo.UserRepo.FindByUsername(ctx, user.Username)
+	if err := a.loginGuard.Check(ctx, user.Username, client.IP); err != nil {
+		return nil, err
+	}
+
+	u, err := a.repo.UserRepo.FindByUsername(ctx, user.Username)
 	if err != nil {
-		return "", nil, err
+		if errors.Is(err, model.ErrUserNotFound) {
+			return nil, a.loginFailed(ctx, nil, user.Username, client.IP, err)
+		}
+
+		return nil, err
 	}
 
 	if !u.IsApproved {
-		return "", nil, model.ErrUserNotApproved
+		return nil, model.ErrUserNotApproved
 	}
 
 	if ok := a.comparePasswords(u.Password, user.Password); !ok {
-		return "", nil, model.ErrPasswordMatch
+		return nil, a.loginFailed(ctx, u, user.Username, client.IP, model.ErrPasswordMatch)
+	}
+
+	// only after the password, so the flag is not revealed to whoever guesses usernames
+	if u.IsDisabled() {
+		return nil, model.ErrUserDisabled
+	}
+
+	res, err := a.completeLogin(ctx, u, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// with a second factor pending the failures are kept, CompleteTwoFactorLogin clears them
+	// once the code is right, so logging in again does not reset the guesses of the code
+	if res.Tokens != nil {
+		if err = a.loginGuard.Succeed(ctx, u.Username); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// completeLogin runs after the first factor (password or external identity) has been verified.
+func (a *authService) completeLogin(ctx context.Context, u *model.User, client model.ClientInfo) (*model.LoginResult, error) {
+	var step model.TwoFactorStep
+	switch {
+	case u.TotpEnabled:
+		step = model.TwoFactorStepVerify
+	case a.totp.Required(u.RoleID):
+		step = model.TwoFactorStepSetup
+	default:
+		return a.openSession(ctx, u, client)
+	}
+
+	challenge, err := a.totp.CreateChallenge(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LoginResult{
+		TwoFactor:      step,
+		ChallengeToken: challenge,
+	}, nil
+}
+
+// CompleteTwoFactorLogin finishes a login started by Login. For TwoFactorStepSetup the code must match
+// the secret from StartTwoFactorSetup, then 2FA gets enabled and recovery codes are returned.
+func (a *authService) CompleteTwoFactorLogin(
+	ctx context.Context,
+	challenge, code string,
+	client model.ClientInfo,
+) (*model.LoginResult, error) {
+	ctx, span := a.tracer.Start(ctx, "authService.CompleteTwoFactorLogin")
+	defer span.End()
+
+	u, err := a.challengeUser(ctx, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = a.loginGuard.Check(ctx, u.Username, client.IP); err != nil {
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	if u.TotpEnabled {
+		err = a.totp.Verify(ctx, *u, code)
+	} else {
+		recoveryCodes, err = a.totp.CompleteEnrollment(ctx, u, code)
+	}
+
+	if err != nil {
+		if !errors.Is(err, model.ErrTwoFactorInvalid) {
+			return nil, err
+		}
+
+		if failErr := a.totp.FailChallenge(ctx, challenge); failErr != nil {
+			return nil, failErr
+		}
+
+		// wrong codes count against the username like wrong passwords, the per-challenge limit alone
+		// would reset with every new password login
+		err = a.loginFailed(ctx, u, u.Username, client.IP, err)
+		if errors.Is(err, model.ErrLoginLocked) {
+			if dropErr := a.totp.DropChallenge(ctx, challenge); dropErr != nil {
+				return nil, dropErr
+			}
+		}
+
+		return nil, err
+	}
+
+	if err = a.totp.DropChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	if err = a.loginGuard.Succeed(ctx, u.Username); err != nil {
+		return nil, err
+	}
+
+	res, err := a.openSession(ctx, u, client)
+	if err != nil {
+		return nil, err
 	}
 
-	token, err = a.generateJwtToken(u.Username, u.RoleID, u.ID)
+	res.RecoveryCodes = recoveryCodes
+
+	return res, nil
+}
+
+// StartTwoFactorSetup lets a user whose role requires 2FA enroll in the middle of a login.
+func (a *authService) StartTwoFactorSetup(ctx context.Context, challenge string) (model.TotpEnrollment, error) {
+	ctx, span := a.tracer.Start(ctx, "authService.StartTwoFactorSetup")
+	defer span.End()
+
+	u, err := a.challengeUser(ctx, challenge)
 	if err != nil {
-		return "", nil, err
+		return model.TotpEnrollment{}, err
+	}
+
+	return a.totp.StartEnrollment(ctx, *u)
+}
+
+func (a *authService) challengeUser(ctx context.Context, challenge string) (*model.User, error) {
+	userID, err := a.totp.ChallengeUser(ctx, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := a.repo.UserRepo.FindById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.ID == uuid.Nil || !u.IsApproved || u.IsDisabled() {
+		return nil, model.ErrTwoFactorChallenge
+	}
+
+	return u, nil
+}
+
+func (a *authService) openSession(ctx context.Context, u *model.User, client model.ClientInfo) (*model.LoginResult, error) {
+	sid, refreshToken, err := a.sessions.Create(ctx, u.ID, client)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := a.issueTokens(u, sid, refreshToken)
+	if err != nil {
+		return nil, err
 	}
 
 	u.Password = ""
 
-	return
+	return &model.LoginResult{
+		Tokens: tokens,
+		User:   u,
+	}, nil
+}
+
+// loginFailed counts the failed attempt and returns cause, or ErrLoginLocked if this attempt locked
+// the account. The owner of an existing account is told about the lock.
+func (a *authService) loginFailed(ctx context.Context, u *model.User, username, clientIP string, cause error) error {
+	locked, err := a.loginGuard.Fail(ctx, username, clientIP)
+	if err != nil {
+		return err
+	}
+
+	if !locked {
+		return cause
+	}
+
+	if u != nil {
+		text := fmt.Sprintf(
+			"your account was locked for %d minutes after too many failed login attempts. "+
+				"if it was not you, reset your password",
+			int(loginLockout.Minutes()),
+		)
+
+		// best effort, the lock itself is already in place
+		_ = notifyUser(ctx, a.repo, *u, "Account temporarily locked", text)
+	}
+
+	return model.ErrLoginLocked
+}
+
+func (a *authService) Refresh(ctx context.Context, refreshToken string) (*model.TokenPair, error) {
+	ctx, span := a.tracer.Start(ctx, "authService.Refresh")
+	defer span.End()
+
+	sess, newRefreshToken, err := a.sessions.Rotate(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// role and approval may have changed since the session was opened, so always reload the user
+	u, err := a.repo.UserRepo.FindById(ctx, sess.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.ID == uuid.Nil || !u.IsApproved || u.IsDisabled() {
+		if revokeErr := a.sessions.Revoke(ctx, sess.ID); revokeErr != nil {
+			return nil, revokeErr
+		}
+
+		return nil, model.ErrSessionRevoked
+	}
+
+	return a.issueTokens(u, sess.ID, newRefreshToken)
+}
+
+func (a *authService) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	ctx, span := a.tracer.Start(ctx, "authService.Logout")
+	defer span.End()
+
+	return a.sessions.Revoke(ctx, sessionID)
+}
+
+func (a *authService) ValidateSession(ctx context.Context, sessionID, userID uuid.UUID, clientIP string) error {
+	ctx, span := a.tracer.Start(ctx, "authService.ValidateSession")
+	defer span.End()
+
+	if err := a.sessions.Validate(ctx, sessionID, userID, clientIP); err != nil {
+		return err
+	}
+
+	// disabling revokes the sessions, this catches a session opened while the admin was at it
+	u, err := a.repo.UserRepo.FindById(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if u.ID == uuid.Nil || u.IsDisabled() {
+		if revokeErr := a.sessions.Revoke(ctx, sessionID); revokeErr != nil {
+			return revokeErr
+		}
+
+		return model.ErrSessionRevoked
+	}
+
+	return nil
+}
+
+func (a *authService) issueTokens(u *model.User, sid uuid.UUID, refreshToken string) (*model.TokenPair, error) {
+	now := time.Now()
+
+	accessToken, err := a.generateJwtToken(u.Username, u.RoleID, u.ID, sid, now.Add(accessTokenTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		AccessExpiresAt:  now.Add(accessTokenTTL),
+		RefreshExpiresAt: now.Add(refreshTokenTTL),
+	}, nil
 }
 
-func (a *authService) Register(ctx context.Context, user model.User) error {
+// Register creates a GUEST. With an invitation code the new user is bound to the invitation's
+// apartment and promoted to INHABITANT right away.
+func (a *authService) Register(ctx context.Context, user model.User, invitationCode string) error {
 	ctx, span := a.tracer.Start(ctx, "authService.Register")
 	defer span.End()
 
-	if _, err := a.repo.UserRepo.FindByUsername(ctx, user.Username); err != nil {
-		if !errors.Is(err, model.ErrUserNotFound) {
+	if invitationCode != "" {
+		// fail early on a typo, before hashing the password
+		inv, err := a.repo.InvitationRepo.FindByCodeHash(ctx, hashInvitationCode(invitationCode))
+		if err != nil {
+			if errors.Is(err, model.ErrInvitationNotFound) {
+				return model.ErrInvitationInvalid
+			}
+
 			return err
 		}
+
+		if !inv.Usable(time.Now()) {
+			return model.ErrInvitationInvalid
+		}
 	}
 
-	var usernameType int
-	switch {
-	case a.isEmail(user.Username):
-		usernameType = UsernameTypeEmail
-	case a.isPhone(user.Username):
-		usernameType = UsernameTypePhone
-	default:
-		usernameType = UsernameTypeNone
-	}
-
-	var (
-		otp        = rand.Intn(9000) + 1000
-		isApproved = false
-		u          = model.User{
-			Username: user.Username,
-			//Password:     string(hashedPsw),
-			FirstName:    user.FirstName,
-			LastName:     user.LastName,
-			UsernameType: usernameType,
-			//IsApproved:   false,
-			RoleID: protopb.Role_GUEST,
-		}
-	)
-
-	// send message to
-	switch usernameType {
-	case UsernameTypeEmail:
-		if err := a.repo.EmailRepo.SendEmail(
-			ctx,
-			[]string{user.Username},
-			"OTP Code",
-			fmt.Sprintf("enter this otp code in order to confirm registration : %d", otp),
-		); err != nil {
+	if _, err := a.repo.UserRepo.FindByUsername(ctx, user.Username); err != nil {
+		if !errors.Is(err, model.ErrUserNotFound) {
 			return err
 		}
-	case UsernameTypeNone:
-		isApproved = true
-	case UsernameTypePhone:
-		// TODO: send sms via whatsapp
-	default:
-		return errors.New("unknown username type")
 	}
 
-	if err := a.redisClient.Set(
-		ctx,
-		user.Username,
-		otp,
-		5*time.Minute,
-	).Err(); err != nil {
-		return err
+	usernameType := detectUsernameType(user.Username)
+
+	u := model.User{
+		Username:     user.Username,
+		FirstName:    user.FirstName,
+		LastName:     user.LastName,
+		UsernameType: usernameType,
+		IsApproved:   usernameType == UsernameTypeNone,
+		RoleID:       protopb.Role_GUEST,
 	}
 
 	hashedPsw, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
@@ -169,37 +498,55 @@ func (a *authService) Register(ctx context.Context, user model.User) error {
 		return err
 	}
 
-	u.IsApproved = isApproved
 	u.Password = string(hashedPsw)
 
-	if err = a.repo.UserRepo.CreateUser(ctx, &u); err != nil {
+	if invitationCode != "" {
+		// the user is created only together with the redemption, so a code used up in the meantime
+		// does not leave an unbound account behind
+		if _, err = a.repo.InvitationRepo.CreateUserAndRedeem(ctx, &u, hashInvitationCode(invitationCode)); err != nil {
+			return err
+		}
+	} else if err = a.repo.UserRepo.CreateUser(ctx, &u); err != nil {
+		return err
+	}
+
+	if u.IsApproved {
+		return nil
+	}
+
+	// if delivery fails the user stays unapproved and can ask for another code via resend
+	if err = a.otp.Send(ctx, model.OtpPurposeRegistration, u); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (a *authService) isEmail(str string) bool {
-	_, err := mail.ParseAddress(str)
-	return err == nil
-}
+// detectUsernameType tells whether the username is an email, a phone number or neither.
+func detectUsernameType(username string) int {
+	if _, err := mail.ParseAddress(username); err == nil {
+		return UsernameTypeEmail
+	}
 
-func (a *authService) isPhone(str string) bool {
-	return phoneRegexp.MatchString(str)
+	if phoneRegexp.MatchString(username) {
+		return UsernameTypePhone
+	}
+
+	return UsernameTypeNone
 }
 
-func (a *authService) generateJwtToken(username string, role protopb.Role, userId uuid.UUID) (string, error) {
+func (a *authService) generateJwtToken(username string, role protopb.Role, userId, sid uuid.UUID, exp time.Time) (string, error) {
 	claims := jwt.MapClaims{
 		"username": username,
 		"user_id":  userId.String(),
 		"role":     role.String(),
+		"sid":      sid.String(),
 		"issuer":   "jeffry's backend",
-		"exp":      time.Now().Add(time.Hour * 24 * 7).Unix(),
+		"iat":      time.Now().Unix(),
+		"exp":      exp.Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	tokenString, err := token.SignedString([]byte(a.secretKey))
+	tokenString, err := a.jwtKeys.Sign(claims)
 	if err != nil {
 		return "", err
 	}
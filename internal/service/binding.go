@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"github.com/podpivasniki1488/assyl-backend/protopb"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// bindingProofTypes are the content types accepted as proof of ownership
+var bindingProofTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+}
+
+type bindingService struct {
+	repo   *repository.Repository
+	tracer trace.Tracer
+}
+
+func NewBindingService(repo *repository.Repository) Binding {
+	return &bindingService{repo, otel.Tracer("bindingService")}
+}
+
+func (b *bindingService) RequestBinding(
+	ctx context.Context,
+	userID uuid.UUID,
+	role protopb.Role,
+	req model.CreateBindingRequest,
+) (*model.BindingRequest, error) {
+	ctx, span := b.tracer.Start(ctx, "bindingService.RequestBinding")
+	defer span.End()
+
+	ap, err := b.repo.ApartmentRepo.GetApartmentByID(ctx, req.ApartmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	bindAny := model.HasPermission(role, model.PermApartmentBindAny)
+
+	// staff do not take over an owned apartment either: the owner leaves the household first
+	if ap.OwnerId != nil {
+		return nil, model.ErrApartmentAlreadyBound
+	}
+
+	if req.Proof != nil {
+		req.Proof.ContentType = http.DetectContentType(req.Proof.Data)
+		if !bindingProofTypes[req.Proof.ContentType] {
+			return nil, model.ErrBindingProofInvalid
+		}
+	}
+
+	now := time.Now()
+	request := &model.BindingRequest{
+		ApartmentID: ap.Id,
+		UserID:      userID,
+		Status:      model.BindingStatusPending,
+		Comment:     req.Comment,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if !bindAny {
+		if err = b.repo.BindingRepo.Create(ctx, request, req.Proof); err != nil {
+			return nil, err
+		}
+
+		return request, nil
+	}
+
+	// staff do not wait for a review, the request is kept as a record of the binding
+	stamp(request, userID, model.BindingStatusApproved, "")
+
+	rejected, err := b.repo.BindingRepo.CreateApproved(ctx, request, req.Proof, boundToAnotherResident)
+	if err != nil {
+		return nil, err
+	}
+
+	b.notifyRejected(ctx, *ap, rejected)
+
+	return request, nil
+}
+
+func (b *bindingService) ListBindingRequests(
+	ctx context.Context,
+	req model.GetBindingRequestsRequest,
+) (model.Page[model.BindingRequest], error) {
+	ctx, span := b.tracer.Start(ctx, "bindingService.ListBindingRequests")
+	defer span.End()
+
+	req.Pagination = req.Pagination.Normalize()
+
+	requests, total, err := b.repo.BindingRepo.FindByFilters(ctx, &req)
+	if err != nil {
+		return model.Page[model.BindingRequest]{}, err
+	}
+
+	return model.Page[model.BindingRequest]{
+		Items:    requests,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
+
+func (b *bindingService) GetBindingProof(
+	ctx context.Context,
+	actorID uuid.UUID,
+	actorRole protopb.Role,
+	id uuid.UUID,
+) (*model.BindingDocument, error) {
+	ctx, span := b.tracer.Start(ctx, "bindingService.GetBindingProof")
+	defer span.End()
+
+	request, err := b.repo.BindingRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// other users must not learn that the request exists
+	if request.UserID != actorID && !model.HasPermission(actorRole, model.PermBindingReview) {
+		return nil, model.ErrBindingNotFound
+	}
+
+	return b.repo.BindingRepo.FindDocument(ctx, id)
+}
+
+func (b *bindingService) ApproveBindingRequest(ctx context.Context, reviewerID, id uuid.UUID) (*model.BindingRequest, error) {
+	ctx, span := b.tracer.Start(ctx, "bindingService.ApproveBindingRequest")
+	defer span.End()
+
+	request, err := b.repo.BindingRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Status != model.BindingStatusPending {
+		return nil, model.ErrBindingNotPending
+	}
+
+	ap, err := b.repo.ApartmentRepo.GetApartmentByID(ctx, request.ApartmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// checked again under the apartment lock, this only saves a transaction in the common case
+	if ap.OwnerId != nil && *ap.OwnerId != request.UserID {
+		return nil, model.ErrApartmentAlreadyBound
+	}
+
+	stamp(request, reviewerID, model.BindingStatusApproved, "")
+
+	rejected, err := b.repo.BindingRepo.Approve(ctx, request, boundToAnotherResident)
+	if err != nil {
+		return nil, err
+	}
+
+	b.notify(ctx, request.UserID, "Apartment binding approved", fmt.Sprintf(
+		"Your request to bind apartment %d (floor %d) was approved, you are now its owner.", ap.DoorNumber, ap.Floor,
+	))
+
+	b.notifyRejected(ctx, *ap, rejected)
+
+	return request, nil
+}
+
+// boundToAnotherResident is the reason other pending requests get when an apartment is bound.
+const boundToAnotherResident = "the apartment has been bound to another resident"
+
+// notifyRejected tells the users whose requests were rejected because the apartment got its owner.
+func (b *bindingService) notifyRejected(ctx context.Context, ap model.Apartment, rejected []model.BindingRequest) {
+	for _, r := range rejected {
+		b.notify(ctx, r.UserID, "Apartment binding rejected", fmt.Sprintf(
+			"Your request to bind apartment %d (floor %d) was rejected: %s.", ap.DoorNumber, ap.Floor, r.Reason,
+		))
+	}
+}
+
+func (b *bindingService) RejectBindingRequest(
+	ctx context.Context,
+	reviewerID, id uuid.UUID,
+	reason string,
+) (*model.BindingRequest, error) {
+	ctx, span := b.tracer.Start(ctx, "bindingService.RejectBindingRequest")
+	defer span.End()
+
+	request, err := b.repo.BindingRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Status != model.BindingStatusPending {
+		return nil, model.ErrBindingNotPending
+	}
+
+	if err = b.review(ctx, request, reviewerID, model.BindingStatusRejected, reason); err != nil {
+		return nil, err
+	}
+
+	b.notify(ctx, request.UserID, "Apartment binding rejected", "Your request to bind the apartment was rejected: "+reason)
+
+	return request, nil
+}
+
+func (b *bindingService) review(
+	ctx context.Context,
+	request *model.BindingRequest,
+	reviewerID uuid.UUID,
+	status model.BindingStatus,
+	reason string,
+) error {
+	stamp(request, reviewerID, status, reason)
+
+	return b.repo.BindingRepo.Review(ctx, request)
+}
+
+// stamp records the review outcome on the request before it is stored.
+func stamp(request *model.BindingRequest, reviewerID uuid.UUID, status model.BindingStatus, reason string) {
+	now := time.Now()
+
+	request.Status = status
+	request.Reason = reason
+	request.ReviewedBy = &reviewerID
+	request.ReviewedAt = &now
+	request.UpdatedAt = now
+}
+
+// notify is best effort, a failed delivery does not undo the review.
+func (b *bindingService) notify(ctx context.Context, userID uuid.UUID, subject, text string) {
+	user, err := b.repo.UserRepo.FindById(ctx, userID)
+	if err != nil || user.ID == uuid.Nil {
+		return
+	}
+
+	_ = notifyUser(ctx, b.repo, *user, subject, text)
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type buildingService struct {
+	repo   *repository.Repository
+	tracer trace.Tracer
+}
+
+func NewBuildingService(repo *repository.Repository) Building {
+	return &buildingService{repo, otel.Tracer("buildingService")}
+}
+
+func (b *buildingService) CreateBuilding(ctx context.Context, req model.Building) (*model.Building, error) {
+	ctx, span := b.tracer.Start(ctx, "buildingService.CreateBuilding")
+	defer span.End()
+
+	building := &model.Building{
+		Name:    req.Name,
+		Address: req.Address,
+	}
+
+	if err := b.repo.BuildingRepo.CreateBuilding(ctx, building); err != nil {
+		return nil, err
+	}
+
+	return building, nil
+}
+
+func (b *buildingService) ListBuildings(ctx context.Context) ([]model.BuildingDetails, error) {
+	ctx, span := b.tracer.Start(ctx, "buildingService.ListBuildings")
+	defer span.End()
+
+	buildings, err := b.repo.BuildingRepo.FindBuildings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entrances, err := b.repo.BuildingRepo.FindEntrances(ctx, model.EntranceFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.BuildingDetails, len(buildings))
+	byID := make(map[uuid.UUID]*model.BuildingDetails, len(buildings))
+
+	for i := range buildings {
+		res[i] = model.BuildingDetails{Building: buildings[i], Entrances: []model.Entrance{}}
+		byID[buildings[i].ID] = &res[i]
+	}
+
+	for _, e := range entrances {
+		if building, ok := byID[e.BuildingID]; ok {
+			building.Entrances = append(building.Entrances, e)
+		}
+	}
+
+	return res, nil
+}
+
+func (b *buildingService) UpdateBuilding(ctx context.Context, req model.Building) (*model.Building, error) {
+	ctx, span := b.tracer.Start(ctx, "buildingService.UpdateBuilding")
+	defer span.End()
+
+	building, err := b.repo.BuildingRepo.FindBuildingByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	building.Name = req.Name
+	building.Address = req.Address
+
+	if err = b.repo.BuildingRepo.UpdateBuilding(ctx, building); err != nil {
+		return nil, err
+	}
+
+	return building, nil
+}
+
+func (b *buildingService) DeleteBuilding(ctx context.Context, id uuid.UUID) error {
+	ctx, span := b.tracer.Start(ctx, "buildingService.DeleteBuilding")
+	defer span.End()
+
+	return b.repo.BuildingRepo.DeleteBuilding(ctx, id)
+}
+
+func (b *buildingService) CreateEntrance(ctx context.Context, buildingID uuid.UUID, number uint8) (*model.Entrance, error) {
+	ctx, span := b.tracer.Start(ctx, "buildingService.CreateEntrance")
+	defer span.End()
+
+	if _, err := b.repo.BuildingRepo.FindBuildingByID(ctx, buildingID); err != nil {
+		return nil, err
+	}
+
+	entrance := &model.Entrance{
+		BuildingID: buildingID,
+		Number:     number,
+	}
+
+	if err := b.repo.BuildingRepo.CreateEntrance(ctx, entrance); err != nil {
+		return nil, err
+	}
+
+	return entrance, nil
+}
+
+func (b *buildingService) DeleteEntrance(ctx context.Context, buildingID, id uuid.UUID) error {
+	ctx, span := b.tracer.Start(ctx, "buildingService.DeleteEntrance")
+	defer span.End()
+
+	entrances, err := b.repo.BuildingRepo.FindEntrancesByIDs(ctx, []uuid.UUID{id})
+	if err != nil {
+		return err
+	}
+
+	if len(entrances) == 0 || entrances[0].BuildingID != buildingID {
+		return model.ErrEntranceNotFound
+	}
+
+	return b.repo.BuildingRepo.DeleteEntrance(ctx, id)
+}
+
+// resolveEntrance finds the entrance of an address. Building and entrance number may be left
+// out while they are unambiguous, ErrAddressAmbiguous otherwise.
+func resolveEntrance(ctx context.Context, repo *repository.Repository, addr model.ApartmentAddress) (*model.Entrance, error) {
+	if addr.BuildingID != uuid.Nil {
+		if _, err := repo.BuildingRepo.FindBuildingByID(ctx, addr.BuildingID); err != nil {
+			return nil, err
+		}
+	}
+
+	entrances, err := repo.BuildingRepo.FindEntrances(ctx, model.EntranceFilter{
+		BuildingID:   addr.BuildingID,
+		BuildingName: addr.BuildingName,
+		Number:       addr.Entrance,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(entrances) {
+	case 0:
+		return nil, model.ErrEntranceNotFound
+	case 1:
+		return &entrances[0], nil
+	default:
+		return nil, model.ErrAddressAmbiguous
+	}
+}
+
+// fillAddress sets building and entrance number of every apartment.
+func fillAddress(ctx context.Context, repo *repository.Repository, items []model.ApartmentDetails) error {
+	ids := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		if item.EntranceID != nil {
+			ids = append(ids, *item.EntranceID)
+		}
+	}
+
+	entrances, err := repo.BuildingRepo.FindEntrancesByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	buildings, err := repo.BuildingRepo.FindBuildings(ctx)
+	if err != nil {
+		return err
+	}
+
+	names := make(map[uuid.UUID]string, len(buildings))
+	for _, b := range buildings {
+		names[b.ID] = b.Name
+	}
+
+	byID := make(map[uuid.UUID]model.Entrance, len(entrances))
+	for _, e := range entrances {
+		byID[e.ID] = e
+	}
+
+	for i := range items {
+		if items[i].EntranceID == nil {
+			continue
+		}
+
+		e, ok := byID[*items[i].EntranceID]
+		if !ok {
+			continue
+		}
+
+		items[i].BuildingID = e.BuildingID
+		items[i].Building = names[e.BuildingID]
+		items[i].Entrance = e.Number
+	}
+
+	return nil
+}
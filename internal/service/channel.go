@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
 	"github.com/podpivasniki1488/assyl-backend/internal/repository"
 	"go.opentelemetry.io/otel"
@@ -23,6 +24,12 @@ func (c *channelService) SendChannelMessage(ctx context.Context, msg model.Chann
 	ctx, span := c.tracer.Start(ctx, "channelService.SendChannelMessage")
 	defer span.End()
 
+	if msg.BuildingID != nil {
+		if _, err := c.repo.BuildingRepo.FindBuildingByID(ctx, *msg.BuildingID); err != nil {
+			return err
+		}
+	}
+
 	if err := c.repo.ChannelRepo.InsertNewMessage(ctx, msg); err != nil {
 		return err
 	}
@@ -30,7 +37,11 @@ func (c *channelService) SendChannelMessage(ctx context.Context, msg model.Chann
 	return nil
 }
 
-func (c *channelService) GetByTimePeriod(ctx context.Context, from, to time.Time) ([]model.ChannelMessage, error) {
+func (c *channelService) GetByTimePeriod(
+	ctx context.Context,
+	from, to time.Time,
+	buildingID uuid.UUID,
+) ([]model.ChannelMessage, error) {
 	ctx, span := c.tracer.Start(ctx, "channelService.GetByTimePeriod")
 	defer span.End()
 
@@ -38,7 +49,7 @@ func (c *channelService) GetByTimePeriod(ctx context.Context, from, to time.Time
 		return nil, nil
 	}
 
-	res, err := c.repo.ChannelRepo.GetMessageByTime(ctx, from, to)
+	res, err := c.repo.ChannelRepo.GetMessageByTime(ctx, from, to, buildingID)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +76,7 @@ func (c *chat) SendMessageToChat(ctx context.Context, message string, chatId, se
 	// change LastMessagePreview, LastParticipantId, and LastMessageAt in goroutine
 	go func() {
 		if updateErr := c.repo.ChatRepo.ChangeLastChatInfo(context.Background(), message, senderId, chatId); updateErr != nil {
-			c.logger.Error("could not update last chat info:", updateErr)
+			c.logger.Error("could not update last chat info", "error", updateErr)
 		}
 	}()
 
@@ -85,7 +85,7 @@ func (c *chat) SendMessageToChat(ctx context.Context, message string, chatId, se
 			ChatID: chatId,
 			Text:   message,
 		}); pubErr != nil {
-			c.logger.Error("could not publish message to hub:", err)
+			c.logger.Error("could not publish message to hub", "error", pubErr)
 		}
 	}()
 
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"github.com/podpivasniki1488/assyl-backend/protopb"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	exportTTL          = 24 * time.Hour
+	exportBuildTimeout = 10 * time.Minute
+	// exportSyncMessageLimit is how many chat messages an export may have to be built within the request,
+	// chat history is what makes exports large
+	exportSyncMessageLimit = 1000
+)
+
+// exportService builds personal data archives. Large ones are built in the background:
+//
+//	export_job:{id}         -> hash {user_id, requested_by, status, error, created_at, completed_at}
+//	export_file:{id}        -> zip archive
+//	export_pending:{userId} -> id of the job being built, so repeated requests do not start new ones
+type exportService struct {
+	repo        *repository.Repository
+	redisClient *redis.Client
+	logger      *slog.Logger
+	tracer      trace.Tracer
+}
+
+func NewExportService(repo *repository.Repository, redisCli *redis.Client, logger *slog.Logger) Export {
+	return &exportService{
+		repo:        repo,
+		redisClient: redisCli,
+		logger:      logger,
+		tracer:      otel.Tracer("exportService"),
+	}
+}
+
+func exportJobKey(id uuid.UUID) string {
+	return "export_job:" + id.String()
+}
+
+func exportFileKey(id uuid.UUID) string {
+	return "export_file:" + id.String()
+}
+
+func exportPendingKey(userID uuid.UUID) string {
+	return "export_pending:" + userID.String()
+}
+
+func (e *exportService) ExportUserData(ctx context.Context, requestedBy, userID uuid.UUID) (*model.ExportResult, error) {
+	ctx, span := e.tracer.Start(ctx, "exportService.ExportUserData")
+	defer span.End()
+
+	u, err := e.repo.UserRepo.FindById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.ID == uuid.Nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	messages, err := e.repo.ChatRepo.CountMessagesBySender(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if messages <= exportSyncMessageLimit {
+		archive, err := e.build(ctx, *u)
+		if err != nil {
+			return nil, err
+		}
+
+		return &model.ExportResult{Archive: archive}, nil
+	}
+
+	job, err := e.startJob(ctx, requestedBy, *u)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ExportResult{Job: job}, nil
+}
+
+func (e *exportService) GetExportJob(
+	ctx context.Context,
+	actorID uuid.UUID,
+	actorRole protopb.Role,
+	jobID uuid.UUID,
+) (*model.ExportJob, error) {
+	ctx, span := e.tracer.Start(ctx, "exportService.GetExportJob")
+	defer span.End()
+
+	job, err := e.loadJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canAccessExport(*job, actorID, actorRole) {
+		return nil, model.ErrExportNotFound
+	}
+
+	return job, nil
+}
+
+func (e *exportService) DownloadExport(
+	ctx context.Context,
+	actorID uuid.UUID,
+	actorRole protopb.Role,
+	jobID uuid.UUID,
+) (*model.ExportJob, []byte, error) {
+	ctx, span := e.tracer.Start(ctx, "exportService.DownloadExport")
+	defer span.End()
+
+	job, err := e.GetExportJob(ctx, actorID, actorRole, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if job.Status != model.ExportStatusReady {
+		return nil, nil, model.ErrExportNotReady
+	}
+
+	archive, err := e.redisClient.Get(ctx, exportFileKey(jobID)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil, model.ErrExportNotFound
+		}
+
+		return nil, nil, err
+	}
+
+	return job, archive, nil
+}
+
+// startJob returns the job that is already building the user's export, or starts a new one.
+func (e *exportService) startJob(ctx context.Context, requestedBy uuid.UUID, u model.User) (*model.ExportJob, error) {
+	now := time.Now()
+	job := &model.ExportJob{
+		ID:          uuid.New(),
+		UserID:      u.ID,
+		RequestedBy: requestedBy,
+		Status:      model.ExportStatusPending,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(exportTTL),
+	}
+
+	started, err := e.redisClient.SetNX(ctx, exportPendingKey(u.ID), job.ID.String(), exportBuildTimeout).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if !started {
+		rawID, err := e.redisClient.Get(ctx, exportPendingKey(u.ID)).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		pendingID, err := uuid.Parse(rawID)
+		if err != nil {
+			return nil, err
+		}
+
+		return e.loadJob(ctx, pendingID)
+	}
+
+	pipe := e.redisClient.TxPipeline()
+	pipe.HSet(ctx, exportJobKey(job.ID), map[string]any{
+		"user_id":      job.UserID.String(),
+		"requested_by": job.RequestedBy.String(),
+		"status":       string(job.Status),
+		"created_at":   now.Unix(),
+	})
+	pipe.Expire(ctx, exportJobKey(job.ID), exportTTL)
+
+	if _, err = pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	// the request context ends with the response, the job must outlive it
+	go e.runJob(context.WithoutCancel(ctx), job.ID, u)
+
+	return job, nil
+}
+
+func (e *exportService) runJob(ctx context.Context, jobID uuid.UUID, u model.User) {
+	ctx, cancel := context.WithTimeout(ctx, exportBuildTimeout)
+	defer cancel()
+
+	ctx, span := e.tracer.Start(ctx, "exportService.runJob")
+	defer span.End()
+
+	defer func() {
+		if err := e.redisClient.Del(ctx, exportPendingKey(u.ID)).Err(); err != nil {
+			e.logger.Error("could not clear pending export", "job_id", jobID, "error", err)
+		}
+	}()
+
+	archive, err := e.build(ctx, u)
+	if err != nil {
+		e.logger.Error("could not build export", "job_id", jobID, "user_id", u.ID, "error", err)
+
+		if err = e.redisClient.HSet(ctx, exportJobKey(jobID), map[string]any{
+			"status":       string(model.ExportStatusFailed),
+			"error":        "could not build export, try again later",
+			"completed_at": time.Now().Unix(),
+		}).Err(); err != nil {
+			e.logger.Error("could not mark export as failed", "job_id", jobID, "error", err)
+		}
+
+		return
+	}
+
+	pipe := e.redisClient.TxPipeline()
+	pipe.Set(ctx, exportFileKey(jobID), archive, exportTTL)
+	pipe.HSet(ctx, exportJobKey(jobID), map[string]any{
+		"status":       string(model.ExportStatusReady),
+		"completed_at": time.Now().Unix(),
+	})
+
+	if _, err = pipe.Exec(ctx); err != nil {
+		e.logger.Error("could not store export", "job_id", jobID, "error", err)
+	}
+}
+
+// build collects everything stored about the user into a zip with one JSON file per kind of data.
+func (e *exportService) build(ctx context.Context, u model.User) ([]byte, error) {
+	ctx, span := e.tracer.Start(ctx, "exportService.build")
+	defer span.End()
+
+	profile, err := buildProfile(ctx, e.repo, u)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := e.repo.MemberRepo.FindByUser(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// proof files are left out to keep the archive small, has_proof tells which requests had one
+	bindingRequests, _, err := e.repo.BindingRepo.FindByFilters(ctx, &model.GetBindingRequestsRequest{
+		Pagination: model.Pagination{PageSize: model.MaxPageSize},
+		UserID:     u.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reservations, err := e.repo.ReservationRepo.GetByUserID(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := e.repo.OrderRepo.GetByFilters(ctx, &model.GetOrderRequest{UserID: u.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	feedback, err := e.repo.FeedbackRepo.GetFeedbackByFilter(ctx, &model.GetFeedbackRequest{UserID: u.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	channelPosts, err := e.repo.ChannelRepo.GetMessagesByAuthor(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := e.repo.ChatRepo.GetMessagesBySender(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", profile},
+		{"apartment.json", profile.Apartment},
+		{"memberships.json", memberships},
+		{"binding_requests.json", bindingRequests},
+		{"reservations.json", reservations},
+		{"orders.json", orders},
+		{"feedback.json", feedback},
+		{"channel_posts.json", channelPosts},
+		{"chat_messages.json", messages},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		if err = enc.Encode(f.data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (e *exportService) loadJob(ctx context.Context, jobID uuid.UUID) (*model.ExportJob, error) {
+	fields, err := e.redisClient.HGetAll(ctx, exportJobKey(jobID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 0 {
+		return nil, model.ErrExportNotFound
+	}
+
+	userID, err := uuid.Parse(fields["user_id"])
+	if err != nil {
+		return nil, model.ErrExportNotFound
+	}
+
+	requestedBy, err := uuid.Parse(fields["requested_by"])
+	if err != nil {
+		return nil, model.ErrExportNotFound
+	}
+
+	job := &model.ExportJob{
+		ID:          jobID,
+		UserID:      userID,
+		RequestedBy: requestedBy,
+		Status:      model.ExportStatus(fields["status"]),
+		Error:       fields["error"],
+	}
+
+	if createdAt, err := parseUnix(fields["created_at"]); err == nil {
+		job.CreatedAt = createdAt
+		job.ExpiresAt = createdAt.Add(exportTTL)
+	}
+
+	if completedAt, err := parseUnix(fields["completed_at"]); err == nil {
+		job.CompletedAt = &completedAt
+	}
+
+	return job, nil
+}
+
+// canAccessExport lets the subject, the requester and export admins see the job.
+func canAccessExport(job model.ExportJob, actorID uuid.UUID, actorRole protopb.Role) bool {
+	return job.UserID == actorID || job.RequestedBy == actorID || model.HasPermission(actorRole, model.PermUserExport)
+}
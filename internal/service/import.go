@@ -0,0 +1,684 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"github.com/podpivasniki1488/assyl-backend/pkg"
+	"github.com/podpivasniki1488/assyl-backend/protopb"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const importMaxRows = 5000
+
+// utf8BOM is what Excel puts in front of a CSV saved as UTF-8
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// importColumns maps accepted header names to the canonical column.
+var importColumns = map[string]string{
+	"building":    "building",
+	"block":       "building",
+	"entrance":    "entrance",
+	"floor":       "floor",
+	"door":        "door",
+	"door_number": "door",
+	"door_num":    "door",
+	"first_name":  "first_name",
+	"last_name":   "last_name",
+	"username":    "username",
+	"phone":       "username",
+	"email":       "username",
+	"role":        "role",
+}
+
+type importService struct {
+	repo   *repository.Repository
+	tracer trace.Tracer
+}
+
+func NewImportService(repo *repository.Repository) Import {
+	return &importService{
+		repo:   repo,
+		tracer: otel.Tracer("importService"),
+	}
+}
+
+// importRun carries the state of one import: the report and apartments and residents already seen in the file.
+type importRun struct {
+	actorID    uuid.UUID
+	opts       model.ImportOptions
+	report     *model.ImportReport
+	apartments map[importApartmentKey]*model.Apartment
+	residents  map[string]*model.User
+}
+
+type importApartmentKey struct {
+	entranceID uuid.UUID
+	floor      uint8
+	door       uint16
+}
+
+func apartmentKeyOf(row model.ImportRow) importApartmentKey {
+	return importApartmentKey{row.EntranceID, row.Floor, row.DoorNumber}
+}
+
+func (s *importService) ImportResidents(
+	ctx context.Context,
+	actorID uuid.UUID,
+	format model.ImportFormat,
+	data []byte,
+	opts model.ImportOptions,
+) (*model.ImportReport, error) {
+	ctx, span := s.tracer.Start(ctx, "importService.ImportResidents")
+	defer span.End()
+
+	records, err := readImportRecords(format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, rowErrs, err := parseImportRows(records)
+	if err != nil {
+		return nil, err
+	}
+
+	addrErrs, err := s.resolveAddresses(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	rowErrs = append(rowErrs, addrErrs...)
+
+	// owners are checked only for resolved addresses, the other rows already have an error
+	if len(addrErrs) == 0 {
+		ownerErrs, err := s.checkOwners(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+
+		rowErrs = append(rowErrs, ownerErrs...)
+	}
+
+	run := &importRun{
+		actorID: actorID,
+		opts:    opts,
+		report: &model.ImportReport{
+			DryRun:  opts.DryRun,
+			Rows:    len(rows),
+			Changes: []model.ImportChange{},
+			Errors:  append(rowErrs, checkImportRows(rows)...),
+		},
+		apartments: make(map[importApartmentKey]*model.Apartment),
+		residents:  make(map[string]*model.User),
+	}
+
+	// a file with mistakes is not applied at all, so it can be fixed and imported again as a whole
+	if len(run.report.Errors) > 0 {
+		return run.report, nil
+	}
+
+	// every step is an upsert, so an import that failed halfway can simply be repeated
+	for _, row := range rows {
+		ap, err := s.importApartment(ctx, run, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if row.Username == "" {
+			continue
+		}
+
+		user, created, err := s.importResident(ctx, run, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = s.importMembership(ctx, run, row, ap, user); err != nil {
+			return nil, err
+		}
+
+		if created && opts.SendInvitations {
+			s.invite(ctx, run, row, user)
+		}
+	}
+
+	run.report.Applied = !opts.DryRun
+
+	return run.report, nil
+}
+
+// resolveAddresses finds the entrance of every row. Unknown or ambiguous addresses come back as row errors.
+func (s *importService) resolveAddresses(ctx context.Context, rows []model.ImportRow) ([]model.ImportRowError, error) {
+	type address struct {
+		building string
+		entrance uint8
+	}
+
+	var errs []model.ImportRowError
+
+	resolved := make(map[address]*model.Entrance)
+	buildings := make(map[uuid.UUID]string)
+
+	for i := range rows {
+		addr := address{rows[i].Building, rows[i].Entrance}
+
+		entrance, ok := resolved[addr]
+		if !ok {
+			var err error
+
+			entrance, err = resolveEntrance(ctx, s.repo, model.ApartmentAddress{
+				BuildingName: addr.building,
+				Entrance:     addr.entrance,
+			})
+			if err != nil && !isAddressErr(err) {
+				return nil, err
+			}
+
+			resolved[addr] = entrance
+		}
+
+		if entrance == nil {
+			errs = append(errs, model.ImportRowError{
+				Line:    rows[i].Line,
+				Message: fmt.Sprintf("building %q entrance %d: not found or ambiguous", addr.building, addr.entrance),
+			})
+
+			continue
+		}
+
+		name, ok := buildings[entrance.BuildingID]
+		if !ok {
+			building, err := s.repo.BuildingRepo.FindBuildingByID(ctx, entrance.BuildingID)
+			if err != nil {
+				return nil, err
+			}
+
+			name = building.Name
+			buildings[entrance.BuildingID] = name
+		}
+
+		rows[i].EntranceID = entrance.ID
+		rows[i].Building = name
+		rows[i].Entrance = entrance.Number
+	}
+
+	return errs, nil
+}
+
+// checkOwners compares the file with the current owners: the import does not reassign or release
+// the ownership of an apartment, that is done through binding requests.
+func (s *importService) checkOwners(ctx context.Context, rows []model.ImportRow) ([]model.ImportRowError, error) {
+	var errs []model.ImportRowError
+
+	owners := make(map[uuid.UUID]string)
+
+	for _, row := range rows {
+		if row.Username == "" {
+			continue
+		}
+
+		ap, err := s.repo.ApartmentRepo.GetApartmentByAddress(ctx, row.EntranceID, row.Floor, row.DoorNumber)
+		if err != nil {
+			if errors.Is(err, model.ErrApartmentNotFound) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if ap.OwnerId == nil {
+			continue
+		}
+
+		owner, ok := owners[*ap.OwnerId]
+		if !ok {
+			u, err := s.repo.UserRepo.FindById(ctx, *ap.OwnerId)
+			if err != nil {
+				return nil, err
+			}
+
+			owner = u.Username
+			owners[*ap.OwnerId] = owner
+		}
+
+		switch {
+		case row.Role == model.MemberRoleOwner && row.Username != owner:
+			errs = append(errs, model.ImportRowError{
+				Line:    row.Line,
+				Message: fmt.Sprintf("apartment is owned by %s, use co_owner or rebind it through a binding request", owner),
+			})
+		case row.Role != model.MemberRoleOwner && row.Username == owner:
+			errs = append(errs, model.ImportRowError{
+				Line:    row.Line,
+				Message: fmt.Sprintf("%s owns the apartment, the role must be owner", row.Username),
+			})
+		}
+	}
+
+	return errs, nil
+}
+
+func isAddressErr(err error) bool {
+	return errors.Is(err, model.ErrEntranceNotFound) ||
+		errors.Is(err, model.ErrBuildingNotFound) ||
+		errors.Is(err, model.ErrAddressAmbiguous)
+}
+
+func (s *importService) importApartment(ctx context.Context, run *importRun, row model.ImportRow) (*model.Apartment, error) {
+	key := apartmentKeyOf(row)
+	if ap, ok := run.apartments[key]; ok {
+		return ap, nil
+	}
+
+	ap, err := s.repo.ApartmentRepo.GetApartmentByAddress(ctx, row.EntranceID, row.Floor, row.DoorNumber)
+	switch {
+	case err == nil:
+		run.report.Apartments.Unchanged++
+	case errors.Is(err, model.ErrApartmentNotFound):
+		ap = &model.Apartment{EntranceID: &row.EntranceID, Floor: row.Floor, DoorNumber: row.DoorNumber}
+
+		if !run.opts.DryRun {
+			if ap, err = s.repo.ApartmentRepo.CreateApartment(ctx, *ap); err != nil {
+				return nil, err
+			}
+		}
+
+		run.report.Apartments.Created++
+		run.change(row, "apartment", model.ImportActionCreate, apartmentKey(row), "")
+	default:
+		return nil, err
+	}
+
+	run.apartments[key] = ap
+
+	return ap, nil
+}
+
+// importResident returns the existing or created user. In a dry run a new user is returned without an ID.
+func (s *importService) importResident(ctx context.Context, run *importRun, row model.ImportRow) (*model.User, bool, error) {
+	// a resident listed on several lines is created or updated only once
+	if user, ok := run.residents[row.Username]; ok {
+		return user, false, nil
+	}
+
+	user, err := s.repo.UserRepo.FindByUsername(ctx, row.Username)
+	if err != nil {
+		if !errors.Is(err, model.ErrUserNotFound) {
+			return nil, false, err
+		}
+
+		// the account has no password: the resident sets one through the forgot password flow,
+		// which also confirms the email or phone
+		user = &model.User{
+			Username:     row.Username,
+			FirstName:    row.FirstName,
+			LastName:     row.LastName,
+			UsernameType: detectUsernameType(row.Username),
+			RoleID:       protopb.Role_GUEST,
+		}
+
+		if !run.opts.DryRun {
+			if err = s.repo.UserRepo.CreateUser(ctx, user); err != nil {
+				return nil, false, err
+			}
+		}
+
+		run.residents[row.Username] = user
+		run.report.Residents.Created++
+		run.change(row, "resident", model.ImportActionCreate, row.Username, "")
+
+		return user, true, nil
+	}
+
+	run.residents[row.Username] = user
+
+	var changed []string
+	if row.FirstName != "" && row.FirstName != user.FirstName {
+		user.FirstName = row.FirstName
+		changed = append(changed, "first_name")
+	}
+
+	if row.LastName != "" && row.LastName != user.LastName {
+		user.LastName = row.LastName
+		changed = append(changed, "last_name")
+	}
+
+	if len(changed) == 0 {
+		run.report.Residents.Unchanged++
+		return user, false, nil
+	}
+
+	if !run.opts.DryRun {
+		if _, err = s.repo.UserRepo.UpdateUser(ctx, user); err != nil {
+			return nil, false, err
+		}
+	}
+
+	run.report.Residents.Updated++
+	run.change(row, "resident", model.ImportActionUpdate, row.Username, strings.Join(changed, ", "))
+
+	return user, false, nil
+}
+
+func (s *importService) importMembership(
+	ctx context.Context,
+	run *importRun,
+	row model.ImportRow,
+	ap *model.Apartment,
+	user *model.User,
+) error {
+	key := fmt.Sprintf("%s in %s", row.Username, apartmentKey(row))
+	action := model.ImportActionCreate
+
+	// nothing to look up for an apartment or user that only exists in the dry run
+	if ap.Id != uuid.Nil && user.ID != uuid.Nil {
+		member, err := s.repo.MemberRepo.Find(ctx, ap.Id, user.ID)
+		switch {
+		case err == nil && member.IsApproved() && member.Role == row.Role:
+			run.report.Memberships.Unchanged++
+			return nil
+		case err == nil:
+			action = model.ImportActionUpdate
+		case !errors.Is(err, model.ErrMemberNotFound):
+			return err
+		}
+	}
+
+	if !run.opts.DryRun {
+		member := &model.ApartmentMember{
+			ApartmentID: ap.Id,
+			UserID:      user.ID,
+			Role:        row.Role,
+		}
+
+		if run.actorID != uuid.Nil {
+			member.ApprovedBy = &run.actorID
+		}
+
+		if err := s.repo.MemberRepo.Approve(ctx, member); err != nil {
+			return err
+		}
+	}
+
+	if action == model.ImportActionCreate {
+		run.report.Memberships.Created++
+	} else {
+		run.report.Memberships.Updated++
+	}
+
+	run.change(row, "membership", action, key, string(row.Role))
+
+	return nil
+}
+
+// invite tells a resident created by the import how to sign in. Delivery failures do not fail the import.
+func (s *importService) invite(ctx context.Context, run *importRun, row model.ImportRow, user *model.User) {
+	if !run.opts.DryRun {
+		text := fmt.Sprintf(
+			"You were added as a resident of apartment %d (building %s, entrance %d, floor %d). "+
+				"To sign in, choose \"Forgot password\" and enter %s to set your password.",
+			row.DoorNumber, row.Building, row.Entrance, row.Floor, user.Username,
+		)
+
+		if err := notifyUser(ctx, s.repo, *user, "Welcome to Assyl", text); err != nil {
+			return
+		}
+	}
+
+	run.report.InvitationsSent++
+	run.change(row, "invitation", model.ImportActionInvite, row.Username, "")
+}
+
+func (r *importRun) change(row model.ImportRow, entity string, action model.ImportAction, key, detail string) {
+	r.report.Changes = append(r.report.Changes, model.ImportChange{
+		Line:   row.Line,
+		Entity: entity,
+		Action: action,
+		Key:    key,
+		Detail: detail,
+	})
+}
+
+func apartmentKey(row model.ImportRow) string {
+	return fmt.Sprintf("building %s, entrance %d, floor %d, door %d", row.Building, row.Entrance, row.Floor, row.DoorNumber)
+}
+
+func readImportRecords(format model.ImportFormat, data []byte) ([][]string, error) {
+	switch format {
+	case model.ImportFormatCSV:
+		r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+		r.FieldsPerRecord = -1
+		r.TrimLeadingSpace = true
+
+		var records [][]string
+		for {
+			rec, err := r.Read()
+			if errors.Is(err, io.EOF) {
+				return records, nil
+			}
+
+			if err != nil {
+				return nil, model.ErrImportFile.WithErr(err)
+			}
+
+			// the reader skips blank lines, keep them so row errors point at the right line
+			line, _ := r.FieldPos(0)
+			for len(records) < line-1 {
+				records = append(records, nil)
+			}
+
+			records = append(records, rec)
+		}
+	case model.ImportFormatXLSX:
+		records, err := pkg.ReadXLSX(data)
+		if err != nil {
+			return nil, model.ErrImportFile.WithErr(err)
+		}
+
+		return records, nil
+	default:
+		return nil, model.ErrImportFile.WithErr(fmt.Errorf("unsupported format %q, use csv or xlsx", format))
+	}
+}
+
+// parseImportRows reads the header and turns the records into rows. Rows that can not be parsed
+// come back as row errors, a missing header or required column fails the whole file.
+func parseImportRows(records [][]string) ([]model.ImportRow, []model.ImportRowError, error) {
+	if len(records) == 0 {
+		return nil, nil, model.ErrImportFile.WithErr(errors.New("file is empty"))
+	}
+
+	if len(records)-1 > importMaxRows {
+		return nil, nil, model.ErrImportFile.WithErr(fmt.Errorf("file has more than %d rows", importMaxRows))
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		name = strings.ToLower(strings.Join(strings.Fields(name), "_"))
+		if col, ok := importColumns[name]; ok {
+			if _, dup := columns[col]; !dup {
+				columns[col] = i
+			}
+		}
+	}
+
+	for _, col := range []string{"floor", "door"} {
+		if _, ok := columns[col]; !ok {
+			return nil, nil, model.ErrImportFile.WithErr(fmt.Errorf("column %q is missing", col))
+		}
+	}
+
+	var (
+		rows []model.ImportRow
+		errs []model.ImportRowError
+	)
+
+	for i, rec := range records[1:] {
+		// line numbers as the user sees them in the spreadsheet, the header is line 1
+		line := i + 2
+
+		value := func(col string) string {
+			idx, ok := columns[col]
+			if !ok || idx >= len(rec) {
+				return ""
+			}
+
+			return strings.TrimSpace(rec[idx])
+		}
+
+		if strings.TrimSpace(strings.Join(rec, "")) == "" {
+			continue
+		}
+
+		row, err := parseImportRow(line, value)
+		if err != nil {
+			errs = append(errs, model.ImportRowError{Line: line, Message: err.Error()})
+			continue
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, errs, nil
+}
+
+func parseImportRow(line int, value func(col string) string) (model.ImportRow, error) {
+	row := model.ImportRow{
+		Line:      line,
+		Building:  value("building"),
+		FirstName: value("first_name"),
+		LastName:  value("last_name"),
+		Username:  normalizeImportUsername(value("username")),
+	}
+
+	floor, err := strconv.ParseUint(value("floor"), 10, 8)
+	if err != nil || floor == 0 {
+		return row, fmt.Errorf("invalid floor %q", value("floor"))
+	}
+
+	door, err := strconv.ParseUint(value("door"), 10, 16)
+	if err != nil || door == 0 {
+		return row, fmt.Errorf("invalid door number %q", value("door"))
+	}
+
+	row.Floor = uint8(floor)
+	row.DoorNumber = uint16(door)
+
+	if v := value("entrance"); v != "" {
+		entrance, err := strconv.ParseUint(v, 10, 8)
+		if err != nil || entrance == 0 {
+			return row, fmt.Errorf("invalid entrance %q", v)
+		}
+
+		row.Entrance = uint8(entrance)
+	}
+
+	role := strings.ReplaceAll(strings.ToLower(value("role")), "-", "_")
+
+	if row.Username == "" {
+		if role != "" || row.FirstName != "" || row.LastName != "" {
+			return row, errors.New("resident without phone or email")
+		}
+
+		return row, nil
+	}
+
+	if t := detectUsernameType(row.Username); t != UsernameTypeEmail && t != UsernameTypePhone {
+		return row, fmt.Errorf("%q is neither an email nor a phone number", row.Username)
+	}
+
+	row.Role = model.MemberRoleFamily
+	if role != "" {
+		row.Role = model.MemberRole(role)
+	}
+
+	if !row.Role.IsValid() {
+		return row, fmt.Errorf("invalid role %q, use owner, co_owner, tenant or family", value("role"))
+	}
+
+	return row, nil
+}
+
+// checkImportRows finds conflicts between rows: the same resident twice in one apartment
+// and more than one owner of an apartment.
+func checkImportRows(rows []model.ImportRow) []model.ImportRowError {
+	var errs []model.ImportRowError
+
+	residents := make(map[string]int)
+	households := make(map[string]importApartmentKey)
+	householdLines := make(map[string]int)
+	owners := make(map[importApartmentKey]int)
+
+	for _, row := range rows {
+		if row.Username == "" {
+			continue
+		}
+
+		apKey := apartmentKeyOf(row)
+
+		resKey := fmt.Sprintf("%s/%d/%d/%s", row.EntranceID, row.Floor, row.DoorNumber, row.Username)
+		if first, ok := residents[resKey]; ok {
+			errs = append(errs, model.ImportRowError{
+				Line:    row.Line,
+				Message: fmt.Sprintf("%s is already listed for this apartment on line %d", row.Username, first),
+			})
+		} else {
+			residents[resKey] = row.Line
+		}
+
+		// approving a membership moves the user out of the previous household
+		if first, ok := households[row.Username]; !ok {
+			households[row.Username] = apKey
+			householdLines[row.Username] = row.Line
+		} else if first != apKey {
+			errs = append(errs, model.ImportRowError{
+				Line: row.Line,
+				Message: fmt.Sprintf(
+					"%s is already listed for another apartment on line %d, a resident belongs to one household",
+					row.Username, householdLines[row.Username],
+				),
+			})
+		}
+
+		if row.Role != model.MemberRoleOwner {
+			continue
+		}
+
+		if first, ok := owners[apKey]; ok {
+			errs = append(errs, model.ImportRowError{
+				Line:    row.Line,
+				Message: fmt.Sprintf("apartment already has an owner on line %d, use co_owner", first),
+			})
+		} else {
+			owners[apKey] = row.Line
+		}
+	}
+
+	return errs
+}
+
+// normalizeImportUsername drops phone formatting like "+7 (701) 123-45-67" and lowercases emails.
+func normalizeImportUsername(username string) string {
+	if strings.Contains(username, "@") {
+		return strings.ToLower(username)
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '(', ')':
+			return -1
+		default:
+			return r
+		}
+	}, username)
+}
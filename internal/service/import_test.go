@@ -0,0 +1,82 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+func TestCheckImportRows(t *testing.T) {
+	entrance := uuid.New()
+	row := func(line int, door uint16, username string, role model.MemberRole) model.ImportRow {
+		return model.ImportRow{
+			Line:       line,
+			EntranceID: entrance,
+			Floor:      1,
+			DoorNumber: door,
+			Username:   username,
+			Role:       role,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		rows      []model.ImportRow
+		wantLines []int
+	}{
+		{
+			name: "household",
+			rows: []model.ImportRow{
+				row(2, 1, "+77011234567", model.MemberRoleOwner),
+				row(3, 1, "+77017654321", model.MemberRoleFamily),
+				row(4, 2, "", ""),
+			},
+		},
+		{
+			name: "resident listed twice for the apartment",
+			rows: []model.ImportRow{
+				row(2, 1, "+77011234567", model.MemberRoleOwner),
+				row(3, 1, "+77011234567", model.MemberRoleFamily),
+			},
+			wantLines: []int{3},
+		},
+		{
+			name: "two owners",
+			rows: []model.ImportRow{
+				row(2, 1, "+77011234567", model.MemberRoleOwner),
+				row(3, 1, "+77017654321", model.MemberRoleOwner),
+			},
+			wantLines: []int{3},
+		},
+		{
+			name: "resident of two apartments",
+			rows: []model.ImportRow{
+				row(2, 1, "+77011234567", model.MemberRoleOwner),
+				row(3, 2, "+77011234567", model.MemberRoleOwner),
+			},
+			wantLines: []int{3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := checkImportRows(tt.rows)
+
+			var lines []int
+			for _, e := range errs {
+				lines = append(lines, e.Line)
+			}
+
+			if len(lines) != len(tt.wantLines) {
+				t.Fatalf("checkImportRows() errors = %v, want lines %v", errs, tt.wantLines)
+			}
+
+			for i := range lines {
+				if lines[i] != tt.wantLines[i] {
+					t.Errorf("checkImportRows() errors = %v, want lines %v", errs, tt.wantLines)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"github.com/podpivasniki1488/assyl-backend/protopb"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	invitationCodeBytes  = 10
+	invitationCodeGroup  = 4
+	invitationMaxUsesCap = 100
+)
+
+type invitationService struct {
+	repo   *repository.Repository
+	tracer trace.Tracer
+}
+
+func NewInvitationService(repo *repository.Repository) Invitation {
+	return &invitationService{
+		repo:   repo,
+		tracer: otel.Tracer("invitationService"),
+	}
+}
+
+func (i *invitationService) CreateInvitation(
+	ctx context.Context,
+	actorID uuid.UUID,
+	actorRole protopb.Role,
+	req model.CreateInvitationRequest,
+) (*model.CreatedInvitation, error) {
+	ctx, span := i.tracer.Start(ctx, "invitationService.CreateInvitation")
+	defer span.End()
+
+	if req.MaxUses < 1 || req.MaxUses > invitationMaxUsesCap || req.TTL < 0 {
+		return nil, model.ErrInvalidInput
+	}
+
+	if req.MemberRole == "" {
+		req.MemberRole = model.MemberRoleFamily
+	}
+
+	if !req.MemberRole.IsValid() || req.MemberRole == model.MemberRoleOwner {
+		return nil, model.ErrInvalidMemberRole
+	}
+
+	if err := i.checkAccess(ctx, actorID, actorRole, req.ApartmentID); err != nil {
+		return nil, err
+	}
+
+	code, err := generateInvitationCode()
+	if err != nil {
+		return nil, err
+	}
+
+	inv := model.Invitation{
+		CodeHash:    hashInvitationCode(code),
+		ApartmentID: req.ApartmentID,
+		CreatedBy:   actorID,
+		MemberRole:  req.MemberRole,
+		MaxUses:     req.MaxUses,
+	}
+
+	if req.TTL > 0 {
+		expiresAt := time.Now().Add(req.TTL)
+		inv.ExpiresAt = &expiresAt
+	}
+
+	if err = i.repo.InvitationRepo.Create(ctx, &inv); err != nil {
+		return nil, err
+	}
+
+	return &model.CreatedInvitation{
+		Invitation: inv,
+		Code:       code,
+	}, nil
+}
+
+func (i *invitationService) GetInvitations(
+	ctx context.Context,
+	actorID uuid.UUID,
+	actorRole protopb.Role,
+	apartmentID uuid.UUID,
+) ([]model.Invitation, error) {
+	ctx, span := i.tracer.Start(ctx, "invitationService.GetInvitations")
+	defer span.End()
+
+	if err := i.checkAccess(ctx, actorID, actorRole, apartmentID); err != nil {
+		return nil, err
+	}
+
+	return i.repo.InvitationRepo.FindByApartmentID(ctx, apartmentID)
+}
+
+func (i *invitationService) RevokeInvitation(ctx context.Context, actorID uuid.UUID, actorRole protopb.Role, id uuid.UUID) error {
+	ctx, span := i.tracer.Start(ctx, "invitationService.RevokeInvitation")
+	defer span.End()
+
+	inv, err := i.repo.InvitationRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = i.checkAccess(ctx, actorID, actorRole, inv.ApartmentID); err != nil {
+		return err
+	}
+
+	return i.repo.InvitationRepo.Revoke(ctx, id)
+}
+
+func (i *invitationService) RedeemInvitation(ctx context.Context, userID uuid.UUID, code string) error {
+	ctx, span := i.tracer.Start(ctx, "invitationService.RedeemInvitation")
+	defer span.End()
+
+	_, err := i.repo.InvitationRepo.Redeem(ctx, hashInvitationCode(code), userID)
+
+	return err
+}
+
+// checkAccess lets admins manage invitations of any apartment and owners and co-owners only of their own.
+func (i *invitationService) checkAccess(ctx context.Context, actorID uuid.UUID, actorRole protopb.Role, apartmentID uuid.UUID) error {
+	return checkHouseholdAccess(ctx, i.repo, actorID, actorRole, apartmentID, model.PermInvitationManage)
+}
+
+// generateInvitationCode returns a code like ABCD-EFGH-IJKL-MNOP
+func generateInvitationCode() (string, error) {
+	buf := make([]byte, invitationCodeBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	raw := recoveryCodeEncoding.EncodeToString(buf)
+
+	groups := make([]string, 0, len(raw)/invitationCodeGroup)
+	for len(raw) > 0 {
+		n := min(invitationCodeGroup, len(raw))
+		groups = append(groups, raw[:n])
+		raw = raw[n:]
+	}
+
+	return strings.Join(groups, "-"), nil
+}
+
+// hashInvitationCode ignores case and dashes, so codes typed by hand still match.
+func hashInvitationCode(code string) string {
+	return hashToken(strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", "")))
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type layoutService struct {
+	repo   *repository.Repository
+	tracer trace.Tracer
+}
+
+func NewLayoutService(repo *repository.Repository) Layout {
+	return &layoutService{repo, otel.Tracer("layoutService")}
+}
+
+type layoutKey struct {
+	floor uint8
+	door  uint16
+}
+
+func (l *layoutService) GenerateLayout(
+	ctx context.Context,
+	actorID uuid.UUID,
+	layout model.ApartmentLayout,
+	opts model.LayoutOptions,
+) (*model.LayoutReport, error) {
+	ctx, span := l.tracer.Start(ctx, "layoutService.GenerateLayout")
+	defer span.End()
+
+	wanted, err := expandLayout(layout)
+	if err != nil {
+		return nil, model.ErrInvalidLayout.WithErr(err)
+	}
+
+	entrance, err := resolveEntrance(ctx, l.repo, model.ApartmentAddress{
+		BuildingID: layout.BuildingID,
+		Entrance:   layout.Entrance,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := l.repo.ApartmentRepo.FindByEntrance(ctx, entrance.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.LayoutReport{
+		DryRun:     opts.DryRun,
+		EntranceID: entrance.ID,
+		Created:    []model.LayoutApartment{},
+		Existing:   []model.LayoutApartment{},
+		Extra:      []model.LayoutApartment{},
+		Deleted:    []model.LayoutApartment{},
+		Bound:      []model.LayoutApartment{},
+	}
+
+	existing := make(map[layoutKey]*model.Apartment, len(current))
+	for i := range current {
+		existing[layoutKey{current[i].Floor, current[i].DoorNumber}] = &current[i]
+	}
+
+	inLayout := make(map[layoutKey]bool, len(wanted))
+	var create []model.Apartment
+
+	for _, key := range wanted {
+		inLayout[key] = true
+
+		if ap, ok := existing[key]; ok {
+			report.Existing = append(report.Existing, layoutApartment(ap))
+			continue
+		}
+
+		create = append(create, model.Apartment{
+			EntranceID: &entrance.ID,
+			Floor:      key.floor,
+			DoorNumber: key.door,
+		})
+	}
+
+	var extra []model.Apartment
+	for _, ap := range current {
+		if !inLayout[layoutKey{ap.Floor, ap.DoorNumber}] {
+			extra = append(extra, ap)
+			report.Extra = append(report.Extra, layoutApartment(&ap))
+		}
+	}
+
+	var (
+		deleteIDs []uuid.UUID
+		audit     []model.ApartmentAudit
+	)
+
+	if opts.Prune && len(extra) > 0 {
+		bound, err := l.boundApartments(ctx, extra)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ap := range extra {
+			if bound[ap.Id] {
+				report.Bound = append(report.Bound, layoutApartment(&ap))
+				continue
+			}
+
+			deleteIDs = append(deleteIDs, ap.Id)
+			report.Deleted = append(report.Deleted, layoutApartment(&ap))
+			audit = append(audit, model.ApartmentAudit{
+				ApartmentID: ap.Id,
+				ActorID:     actorID,
+				Action:      model.ApartmentAuditDelete,
+				Details:     fmt.Sprintf("layout: entrance_id %s, floor %d, door_number %d", entrance.ID, ap.Floor, ap.DoorNumber),
+			})
+		}
+	}
+
+	// bound apartments are never deleted, the layout has to be fixed or the apartments unbound first
+	if len(report.Bound) == 0 && !opts.DryRun {
+		if err = l.repo.ApartmentRepo.ApplyLayout(ctx, create, deleteIDs, audit); err != nil {
+			return nil, err
+		}
+
+		report.Applied = true
+	}
+
+	for _, ap := range create {
+		report.Created = append(report.Created, layoutApartment(&ap))
+	}
+
+	return report, nil
+}
+
+// boundApartments returns the apartments that have an owner or approved residents.
+func (l *layoutService) boundApartments(ctx context.Context, apartments []model.Apartment) (map[uuid.UUID]bool, error) {
+	ids := make([]uuid.UUID, 0, len(apartments))
+	bound := make(map[uuid.UUID]bool)
+
+	for _, ap := range apartments {
+		ids = append(ids, ap.Id)
+		if ap.OwnerId != nil {
+			bound[ap.Id] = true
+		}
+	}
+
+	residents, err := l.repo.ApartmentRepo.FindResidents(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range residents {
+		bound[r.ApartmentID] = true
+	}
+
+	return bound, nil
+}
+
+// expandLayout lists the addresses of the layout ordered by floor and door.
+func expandLayout(layout model.ApartmentLayout) ([]layoutKey, error) {
+	if layout.FromFloor == 0 || layout.ToFloor < layout.FromFloor {
+		return nil, fmt.Errorf("floors %d-%d", layout.FromFloor, layout.ToFloor)
+	}
+
+	if layout.FromDoor == 0 || layout.ToDoor < layout.FromDoor {
+		return nil, fmt.Errorf("doors %d-%d", layout.FromDoor, layout.ToDoor)
+	}
+
+	exceptions := make(map[uint8]model.LayoutFloor, len(layout.Exceptions))
+	for _, e := range layout.Exceptions {
+		if e.Floor < layout.FromFloor || e.Floor > layout.ToFloor {
+			return nil, fmt.Errorf("exception for floor %d is outside floors %d-%d", e.Floor, layout.FromFloor, layout.ToFloor)
+		}
+
+		if _, ok := exceptions[e.Floor]; ok {
+			return nil, fmt.Errorf("floor %d has several exceptions", e.Floor)
+		}
+
+		if !e.Skip && (e.FromDoor == 0 || e.ToDoor < e.FromDoor) {
+			return nil, fmt.Errorf("floor %d: doors %d-%d", e.Floor, e.FromDoor, e.ToDoor)
+		}
+
+		exceptions[e.Floor] = e
+	}
+
+	var res []layoutKey
+
+	for floor := int(layout.FromFloor); floor <= int(layout.ToFloor); floor++ {
+		fromDoor, toDoor := layout.FromDoor, layout.ToDoor
+
+		if e, ok := exceptions[uint8(floor)]; ok {
+			if e.Skip {
+				continue
+			}
+
+			fromDoor, toDoor = e.FromDoor, e.ToDoor
+		}
+
+		if len(res)+int(toDoor-fromDoor)+1 > model.MaxLayoutApartments {
+			return nil, fmt.Errorf("more than %d apartments", model.MaxLayoutApartments)
+		}
+
+		for door := int(fromDoor); door <= int(toDoor); door++ {
+			res = append(res, layoutKey{uint8(floor), uint16(door)})
+		}
+	}
+
+	return res, nil
+}
+
+func layoutApartment(ap *model.Apartment) model.LayoutApartment {
+	res := model.LayoutApartment{
+		Floor:      ap.Floor,
+		DoorNumber: ap.DoorNumber,
+	}
+
+	if ap.Id != uuid.Nil {
+		id := ap.Id
+		res.ID = &id
+	}
+
+	return res
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	loginFailWindow    = 15 * time.Minute
+	loginDelayAfter    = 3
+	loginMaxDelay      = time.Minute
+	loginMaxFailures   = 10
+	loginLockout       = 30 * time.Minute
+	loginMaxIPFailures = 50
+)
+
+// loginGuard throttles password guessing. Failures are counted per username and per client IP:
+//
+//	login_fail:{username}    -> failed attempts in the current window
+//	login_fail_ip:{ip}       -> failed attempts from the ip in the current window
+//	login_delay:{username}   -> marker that blocks the next attempt, its ttl doubles with every failure
+//	login_lock:{username}    -> hash {failures, last_ip, locked_at} while the account is locked
+type loginGuard struct {
+	redisClient *redis.Client
+	tracer      trace.Tracer
+}
+
+func newLoginGuard(redisCli *redis.Client) *loginGuard {
+	return &loginGuard{
+		redisClient: redisCli,
+		tracer:      otel.Tracer("loginGuard"),
+	}
+}
+
+func loginFailKey(username string) string {
+	return "login_fail:" + username
+}
+
+func loginFailIPKey(ip string) string {
+	return "login_fail_ip:" + ip
+}
+
+func loginDelayKey(username string) string {
+	return "login_delay:" + username
+}
+
+func loginLockKey(username string) string {
+	return "login_lock:" + username
+}
+
+// Check rejects the attempt if the account is locked, the username is still in its delay
+// or the ip made too many failed attempts.
+func (g *loginGuard) Check(ctx context.Context, username, ip string) error {
+	ctx, span := g.tracer.Start(ctx, "loginGuard.Check")
+	defer span.End()
+
+	pipe := g.redisClient.Pipeline()
+	locked := pipe.Exists(ctx, loginLockKey(username))
+	delayed := pipe.Exists(ctx, loginDelayKey(username))
+
+	var ipFailures *redis.StringCmd
+	if ip != "" {
+		ipFailures = pipe.Get(ctx, loginFailIPKey(ip))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+
+	if locked.Val() > 0 {
+		return model.ErrLoginLocked
+	}
+
+	if delayed.Val() > 0 {
+		return model.ErrLoginThrottled
+	}
+
+	if ipFailures != nil {
+		if n, _ := ipFailures.Int(); n >= loginMaxIPFailures {
+			return model.ErrLoginThrottled
+		}
+	}
+
+	return nil
+}
+
+// Fail records a failed attempt. It returns true when the failure locked the account.
+func (g *loginGuard) Fail(ctx context.Context, username, ip string) (bool, error) {
+	ctx, span := g.tracer.Start(ctx, "loginGuard.Fail")
+	defer span.End()
+
+	pipe := g.redisClient.TxPipeline()
+	failures := pipe.Incr(ctx, loginFailKey(username))
+	pipe.Expire(ctx, loginFailKey(username), loginFailWindow)
+
+	if ip != "" {
+		pipe.Incr(ctx, loginFailIPKey(ip))
+		pipe.Expire(ctx, loginFailIPKey(ip), loginFailWindow)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	n := failures.Val()
+
+	if n >= loginMaxFailures {
+		pipe = g.redisClient.TxPipeline()
+		pipe.HSet(ctx, loginLockKey(username), map[string]any{
+			"failures":  n,
+			"last_ip":   ip,
+			"locked_at": time.Now().Unix(),
+		})
+		pipe.Expire(ctx, loginLockKey(username), loginLockout)
+		pipe.Del(ctx, loginFailKey(username), loginDelayKey(username))
+
+		if _, err := pipe.Exec(ctx); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	if n >= loginDelayAfter {
+		if err := g.redisClient.Set(ctx, loginDelayKey(username), 1, loginDelay(n)).Err(); err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// Succeed forgets the failures of the username after a successful login.
+func (g *loginGuard) Succeed(ctx context.Context, username string) error {
+	ctx, span := g.tracer.Start(ctx, "loginGuard.Succeed")
+	defer span.End()
+
+	return g.redisClient.Del(ctx, loginFailKey(username), loginDelayKey(username)).Err()
+}
+
+// Lockouts returns every currently locked account.
+func (g *loginGuard) Lockouts(ctx context.Context) ([]model.LoginLockout, error) {
+	ctx, span := g.tracer.Start(ctx, "loginGuard.Lockouts")
+	defer span.End()
+
+	res := make([]model.LoginLockout, 0)
+
+	iter := g.redisClient.Scan(ctx, 0, loginLockKey("*"), 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+
+		fields, err := g.redisClient.HGetAll(ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		ttl, err := g.redisClient.TTL(ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		// the lock expired between SCAN and HGETALL
+		if len(fields) == 0 || ttl <= 0 {
+			continue
+		}
+
+		lockout := model.LoginLockout{
+			Username:    strings.TrimPrefix(key, loginLockKey("")),
+			LastIP:      fields["last_ip"],
+			LockedUntil: time.Now().Add(ttl),
+		}
+
+		lockout.Failures, _ = strconv.Atoi(fields["failures"])
+
+		if lockedAt, err := parseUnix(fields["locked_at"]); err == nil {
+			lockout.LockedAt = lockedAt
+		}
+
+		res = append(res, lockout)
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Clear unlocks the username and resets its failure counter.
+func (g *loginGuard) Clear(ctx context.Context, username string) error {
+	ctx, span := g.tracer.Start(ctx, "loginGuard.Clear")
+	defer span.End()
+
+	return g.redisClient.Del(ctx, loginLockKey(username), loginFailKey(username), loginDelayKey(username)).Err()
+}
+
+// loginDelay is 1s after loginDelayAfter failures and doubles with every next one, up to loginMaxDelay.
+func loginDelay(failures int64) time.Duration {
+	delay := time.Second << (failures - loginDelayAfter)
+	if delay <= 0 || delay > loginMaxDelay {
+		return loginMaxDelay
+	}
+
+	return delay
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"github.com/podpivasniki1488/assyl-backend/protopb"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type membershipService struct {
+	repo   *repository.Repository
+	tracer trace.Tracer
+}
+
+func NewMembershipService(repo *repository.Repository) Membership {
+	return &membershipService{
+		repo:   repo,
+		tracer: otel.Tracer("membershipService"),
+	}
+}
+
+func (m *membershipService) RequestMembership(
+	ctx context.Context,
+	userID, apartmentID uuid.UUID,
+	role model.MemberRole,
+) (*model.ApartmentMember, error) {
+	ctx, span := m.tracer.Start(ctx, "membershipService.RequestMembership")
+	defer span.End()
+
+	// ownership goes through /apartment/bind, joining is only possible as a household member
+	if !role.IsValid() || role == model.MemberRoleOwner {
+		return nil, model.ErrInvalidMemberRole
+	}
+
+	ap, err := m.repo.ApartmentRepo.GetApartmentByID(ctx, apartmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := m.repo.MemberRepo.Find(ctx, apartmentID, userID)
+	switch {
+	case err == nil && member.Status != model.MemberStatusRejected:
+		return nil, model.ErrMemberAlreadyExists
+	case err == nil:
+		if err = m.repo.MemberRepo.Reopen(ctx, member.ID, role); err != nil {
+			return nil, err
+		}
+
+		member.Role = role
+		member.Status = model.MemberStatusPending
+		member.ApprovedBy = nil
+	case errors.Is(err, model.ErrMemberNotFound):
+		member = &model.ApartmentMember{
+			ApartmentID: apartmentID,
+			UserID:      userID,
+			Role:        role,
+			Status:      model.MemberStatusPending,
+		}
+
+		if err = m.repo.MemberRepo.Create(ctx, member); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	m.notifyManagers(ctx, *ap, userID)
+
+	return member, nil
+}
+
+func (m *membershipService) ListMembers(
+	ctx context.Context,
+	actorID uuid.UUID,
+	actorRole protopb.Role,
+	apartmentID uuid.UUID,
+) ([]model.ApartmentMember, error) {
+	ctx, span := m.tracer.Start(ctx, "membershipService.ListMembers")
+	defer span.End()
+
+	// managers see pending and rejected requests too, other members only the household
+	err := checkHouseholdAccess(ctx, m.repo, actorID, actorRole, apartmentID, model.PermApartmentMembersManage)
+	if err == nil {
+		return m.repo.MemberRepo.FindByApartment(ctx, apartmentID, "")
+	}
+
+	if !errors.Is(err, model.ErrPermissionDenied) {
+		return nil, err
+	}
+
+	if _, err = approvedMember(ctx, m.repo, apartmentID, actorID); err != nil {
+		return nil, err
+	}
+
+	return m.repo.MemberRepo.FindByApartment(ctx, apartmentID, model.MemberStatusApproved)
+}
+
+func (m *membershipService) ListUserMemberships(ctx context.Context, userID uuid.UUID) ([]model.ApartmentMember, error) {
+	ctx, span := m.tracer.Start(ctx, "membershipService.ListUserMemberships")
+	defer span.End()
+
+	return m.repo.MemberRepo.FindByUser(ctx, userID)
+}
+
+func (m *membershipService) ApproveMember(
+	ctx context.Context,
+	actorID uuid.UUID,
+	actorRole protopb.Role,
+	apartmentID, userID uuid.UUID,
+	role model.MemberRole,
+) (*model.ApartmentMember, error) {
+	ctx, span := m.tracer.Start(ctx, "membershipService.ApproveMember")
+	defer span.End()
+
+	if role != "" && (!role.IsValid() || role == model.MemberRoleOwner) {
+		return nil, model.ErrInvalidMemberRole
+	}
+
+	if err := checkHouseholdAccess(ctx, m.repo, actorID, actorRole, apartmentID, model.PermApartmentMembersManage); err != nil {
+		return nil, err
+	}
+
+	member, err := m.repo.MemberRepo.Find(ctx, apartmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if member.Status != model.MemberStatusPending {
+		return nil, model.ErrMemberNotPending
+	}
+
+	if role != "" {
+		member.Role = role
+	}
+
+	member.ApprovedBy = &actorID
+
+	if err = m.repo.MemberRepo.Approve(ctx, member); err != nil {
+		return nil, err
+	}
+
+	m.notifyMember(ctx, userID, "Membership approved", "Your request to join the apartment was approved.")
+
+	return member, nil
+}
+
+func (m *membershipService) RejectMember(
+	ctx context.Context,
+	actorID uuid.UUID,
+	actorRole protopb.Role,
+	apartmentID, userID uuid.UUID,
+) error {
+	ctx, span := m.tracer.Start(ctx, "membershipService.RejectMember")
+	defer span.End()
+
+	if err := checkHouseholdAccess(ctx, m.repo, actorID, actorRole, apartmentID, model.PermApartmentMembersManage); err != nil {
+		return err
+	}
+
+	member, err := m.repo.MemberRepo.Find(ctx, apartmentID, userID)
+	if err != nil {
+		return err
+	}
+
+	if member.Status != model.MemberStatusPending {
+		return model.ErrMemberNotPending
+	}
+
+	if err = m.repo.MemberRepo.Reject(ctx, member.ID); err != nil {
+		return err
+	}
+
+	m.notifyMember(ctx, userID, "Membership rejected", "Your request to join the apartment was rejected.")
+
+	return nil
+}
+
+func (m *membershipService) ChangeMemberRole(
+	ctx context.Context,
+	actorID uuid.UUID,
+	actorRole protopb.Role,
+	apartmentID, userID uuid.UUID,
+	role model.MemberRole,
+) (*model.ApartmentMember, error) {
+	ctx, span := m.tracer.Start(ctx, "membershipService.ChangeMemberRole")
+	defer span.End()
+
+	if !role.IsValid() || role == model.MemberRoleOwner {
+		return nil, model.ErrInvalidMemberRole
+	}
+
+	if err := checkHouseholdAccess(ctx, m.repo, actorID, actorRole, apartmentID, model.PermApartmentMembersManage); err != nil {
+		return nil, err
+	}
+
+	member, err := m.repo.MemberRepo.Find(ctx, apartmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the owner is changed by binding the apartment to someone else, not by a role change
+	if member.Role == model.MemberRoleOwner {
+		return nil, model.ErrMemberIsOwner
+	}
+
+	if err = m.repo.MemberRepo.ChangeRole(ctx, member.ID, role); err != nil {
+		return nil, err
+	}
+
+	member.Role = role
+
+	return member, nil
+}
+
+func (m *membershipService) RemoveMember(
+	ctx context.Context,
+	actorID uuid.UUID,
+	actorRole protopb.Role,
+	apartmentID, userID uuid.UUID,
+) error {
+	ctx, span := m.tracer.Start(ctx, "membershipService.RemoveMember")
+	defer span.End()
+
+	// everyone may leave a household on their own
+	if actorID != userID {
+		if err := checkHouseholdAccess(ctx, m.repo, actorID, actorRole, apartmentID, model.PermApartmentMembersManage); err != nil {
+			return err
+		}
+	}
+
+	member, err := m.repo.MemberRepo.Find(ctx, apartmentID, userID)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == model.MemberRoleOwner && member.IsApproved() {
+		return model.ErrMemberIsOwner
+	}
+
+	return m.repo.MemberRepo.Remove(ctx, apartmentID, userID)
+}
+
+// notifyManagers tells owners and co-owners about a new request. Failures do not fail the request.
+func (m *membershipService) notifyManagers(ctx context.Context, ap model.Apartment, requesterID uuid.UUID) {
+	members, err := m.repo.MemberRepo.FindByApartment(ctx, ap.Id, model.MemberStatusApproved)
+	if err != nil {
+		return
+	}
+
+	text := fmt.Sprintf("New request to join apartment %d (floor %d) is waiting for your approval.", ap.DoorNumber, ap.Floor)
+
+	for _, member := range members {
+		if !member.Role.CanManage() || member.UserID == requesterID {
+			continue
+		}
+
+		m.notifyMember(ctx, member.UserID, "New household member request", text)
+	}
+}
+
+func (m *membershipService) notifyMember(ctx context.Context, userID uuid.UUID, subject, text string) {
+	user, err := m.repo.UserRepo.FindById(ctx, userID)
+	if err != nil || user.ID == uuid.Nil {
+		return
+	}
+
+	_ = notifyUser(ctx, m.repo, *user, subject, text)
+}
+
+// checkHouseholdAccess lets roles with perm manage any apartment and approved owners and
+// co-owners only their own.
+func checkHouseholdAccess(
+	ctx context.Context,
+	repo *repository.Repository,
+	actorID uuid.UUID,
+	actorRole protopb.Role,
+	apartmentID uuid.UUID,
+	perm model.Permission,
+) error {
+	if _, err := repo.ApartmentRepo.GetApartmentByID(ctx, apartmentID); err != nil {
+		return err
+	}
+
+	if model.HasPermission(actorRole, perm) {
+		return nil
+	}
+
+	member, err := approvedMember(ctx, repo, apartmentID, actorID)
+	if err != nil {
+		return err
+	}
+
+	if !member.Role.CanManage() {
+		return model.ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// approvedMember returns ErrPermissionDenied unless the user is an approved member of the apartment.
+func approvedMember(ctx context.Context, repo *repository.Repository, apartmentID, userID uuid.UUID) (*model.ApartmentMember, error) {
+	member, err := repo.MemberRepo.Find(ctx, apartmentID, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrMemberNotFound) {
+			return nil, model.ErrPermissionDenied
+		}
+
+		return nil, err
+	}
+
+	if !member.IsApproved() {
+		return nil, model.ErrPermissionDenied
+	}
+
+	return member, nil
+}
+
+// householdOf returns the approved membership the user acts under: the one of User.ApartmentID.
+// ErrNotApartmentMember if the user does not belong to any household.
+func householdOf(ctx context.Context, repo *repository.Repository, user model.User) (*model.ApartmentMember, error) {
+	if user.ApartmentID == uuid.Nil {
+		return nil, model.ErrNotApartmentMember
+	}
+
+	member, err := approvedMember(ctx, repo, user.ApartmentID, user.ID)
+	if err != nil {
+		if errors.Is(err, model.ErrPermissionDenied) {
+			return nil, model.ErrNotApartmentMember
+		}
+
+		return nil, err
+	}
+
+	return member, nil
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+)
+
+// notifyUser delivers a message to the user's username: by email or by phone, depending on UsernameType.
+func notifyUser(ctx context.Context, repo *repository.Repository, user model.User, subject, text string) error {
+	switch user.UsernameType {
+	case UsernameTypeEmail:
+		return repo.EmailRepo.SendEmail(ctx, []string{user.Username}, subject, text)
+	case UsernameTypePhone:
+		return repo.MessagingRepo.SendMessage(ctx, user.Username, text)
+	default:
+		return model.ErrUnknownOtpTarget
+	}
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/protopb"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const oidcStateTTL = 10 * time.Minute
+
+// oidc_state:{state} -> hash {provider, verifier, nonce} of a login waiting for the provider callback
+func oidcStateKey(state string) string {
+	return "oidc_state:" + state
+}
+
+func (a *authService) OIDCProviders() []string {
+	return a.repo.OIDCRepo.Providers()
+}
+
+// StartOIDCLogin returns the provider URL to send the user to. The PKCE verifier and nonce
+// never leave the server, the client only carries the state.
+func (a *authService) StartOIDCLogin(ctx context.Context, provider string) (string, error) {
+	ctx, span := a.tracer.Start(ctx, "authService.StartOIDCLogin")
+	defer span.End()
+
+	var params [3]string
+	for i := range params {
+		token, err := randomToken()
+		if err != nil {
+			return "", err
+		}
+
+		params[i] = token
+	}
+
+	state, nonce, verifier := params[0], params[1], params[2]
+
+	challenge := sha256.Sum256([]byte(verifier))
+
+	authURL, err := a.repo.OIDCRepo.AuthCodeURL(
+		ctx,
+		provider,
+		state,
+		nonce,
+		base64.RawURLEncoding.EncodeToString(challenge[:]),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	pipe := a.redisClient.TxPipeline()
+	pipe.HSet(ctx, oidcStateKey(state), map[string]any{
+		"provider": provider,
+		"verifier": verifier,
+		"nonce":    nonce,
+	})
+	pipe.Expire(ctx, oidcStateKey(state), oidcStateTTL)
+
+	if _, err = pipe.Exec(ctx); err != nil {
+		return "", err
+	}
+
+	return authURL, nil
+}
+
+// CompleteOIDCLogin handles the provider callback: it exchanges the code, links the external identity
+// to a user (creating a GUEST if needed) and continues like a password login, including 2FA.
+func (a *authService) CompleteOIDCLogin(
+	ctx context.Context,
+	provider, code, state string,
+	client model.ClientInfo,
+) (*model.LoginResult, error) {
+	ctx, span := a.tracer.Start(ctx, "authService.CompleteOIDCLogin")
+	defer span.End()
+
+	pipe := a.redisClient.TxPipeline()
+	stateCmd := pipe.HGetAll(ctx, oidcStateKey(state))
+	pipe.Del(ctx, oidcStateKey(state))
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	fields := stateCmd.Val()
+	if len(fields) == 0 || fields["provider"] != provider {
+		return nil, model.ErrOIDCInvalidState
+	}
+
+	identity, err := a.repo.OIDCRepo.Exchange(ctx, provider, code, fields["verifier"], fields["nonce"])
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := a.linkIdentity(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	if !u.IsApproved {
+		return nil, model.ErrUserNotApproved
+	}
+
+	if u.IsDisabled() {
+		return nil, model.ErrUserDisabled
+	}
+
+	return a.completeLogin(ctx, u, client)
+}
+
+// linkIdentity finds the user behind an external identity. A verified email or phone that matches
+// an existing username links the identity to that user, otherwise a new GUEST is created.
+// An unconfirmed account with that username is taken over instead of linked, see takeOver.
+func (a *authService) linkIdentity(ctx context.Context, identity *model.ExternalIdentity) (*model.User, error) {
+	linked, err := a.repo.UserRepo.FindIdentity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		u, err := a.repo.UserRepo.FindById(ctx, linked.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		if u.ID == uuid.Nil {
+			return nil, model.ErrUserNotFound
+		}
+
+		return u, nil
+	}
+
+	if !errors.Is(err, model.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	link := model.UserIdentity{
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+
+	var username string
+	switch {
+	case identity.EmailVerified && identity.Email != "":
+		username = identity.Email
+	case identity.PhoneVerified && identity.Phone != "":
+		username = identity.Phone
+	}
+
+	if username != "" {
+		u, err := a.repo.UserRepo.FindByUsername(ctx, username)
+		if err == nil {
+			// a disabled account stays as the admin left it, unconfirmed or not
+			if u.IsDisabled() {
+				return nil, model.ErrUserDisabled
+			}
+
+			if !u.IsApproved {
+				return a.takeOver(ctx, u, &link)
+			}
+
+			link.UserID = u.ID
+
+			if err = a.repo.UserRepo.CreateIdentity(ctx, &link); err != nil {
+				return nil, err
+			}
+
+			return u, nil
+		}
+
+		if !errors.Is(err, model.ErrUserNotFound) {
+			return nil, err
+		}
+	}
+
+	usernameType := UsernameTypeNone
+	if username == "" {
+		username = fmt.Sprintf("%s:%s", identity.Provider, identity.Subject)
+	} else {
+		usernameType = detectUsernameType(username)
+	}
+
+	hashedPsw, err := unusablePassword()
+	if err != nil {
+		return nil, err
+	}
+
+	u := model.User{
+		Username:     username,
+		FirstName:    identity.FirstName,
+		LastName:     identity.LastName,
+		UsernameType: usernameType,
+		Password:     hashedPsw,
+		IsApproved:   true,
+		RoleID:       protopb.Role_GUEST,
+	}
+
+	if err = a.repo.UserRepo.CreateUserWithIdentity(ctx, &u, &link); err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// takeOver gives an unconfirmed account to the owner of the verified email or phone. Anyone can register
+// a username without confirming it, so linking as is would let whoever registered it first sign in
+// as the real owner with their own password: that password, 2FA and sessions are dropped, and so is
+// whatever the account collected before, such as a role or an apartment from an invitation.
+func (a *authService) takeOver(ctx context.Context, u *model.User, link *model.UserIdentity) (*model.User, error) {
+	hashedPsw, err := unusablePassword()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = a.repo.UserRepo.TakeOverWithIdentity(ctx, u.ID, hashedPsw, link); err != nil {
+		return nil, err
+	}
+
+	if err = a.sessions.RevokeAll(ctx, u.ID); err != nil {
+		return nil, err
+	}
+
+	return a.repo.UserRepo.FindById(ctx, u.ID)
+}
+
+// unusablePassword returns the hash of a random password: the account is used only through
+// the provider until the user sets a password via reset.
+func unusablePassword() (string, error) {
+	password, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hashed), nil
+}
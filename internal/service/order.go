@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 
 	"github.com/google/uuid"
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
@@ -14,12 +16,14 @@ import (
 type order struct {
 	repo   *repository.Repository
 	tracer trace.Tracer
+	logger *slog.Logger
 }
 
-func NewOrderService(repo *repository.Repository) Order {
+func NewOrderService(repo *repository.Repository, logger *slog.Logger) Order {
 	return &order{
 		repo:   repo,
 		tracer: otel.Tracer("orderService"),
+		logger: logger,
 	}
 }
 
@@ -27,20 +31,57 @@ func (o *order) OrderService(ctx context.Context, req *model.Order) error {
 	ctx, span := o.tracer.Start(ctx, "OrderService.Order")
 	defer span.End()
 
-	if err := o.repo.OrderRepo.Create(ctx, req); err != nil {
+	user, err := o.repo.UserRepo.FindById(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	// orders are placed on behalf of a household, so only its approved members may place them
+	member, err := householdOf(ctx, o.repo, *user)
+	if err != nil {
 		return err
 	}
 
-	// TODO: send notification via whatsapp to admin's phone num
+	req.ApartmentID = &member.ApartmentID
+
+	if err = o.repo.OrderRepo.Create(ctx, req); err != nil {
+		return err
+	}
+
+	// notification failures must not fail the order itself
+	go o.notifyAdmins(context.Background(), *req)
 
 	return nil
 }
 
-func (o *order) GetUserOrders(ctx context.Context, req *model.GetOrderRequest, role string) ([]model.Order, error) {
+func (o *order) notifyAdmins(ctx context.Context, req model.Order) {
+	ctx, span := o.tracer.Start(ctx, "OrderService.notifyAdmins")
+	defer span.End()
+
+	admins, err := o.repo.UserRepo.FindByRoles(ctx, protopb.Role_ADMIN, protopb.Role_GOD)
+	if err != nil {
+		o.logger.Error("could not load admins for order notification", "error", err)
+		return
+	}
+
+	text := fmt.Sprintf("new order (%s): %s", req.OrderType.String(), req.Text)
+
+	for _, admin := range admins {
+		if admin.UsernameType != UsernameTypePhone {
+			continue
+		}
+
+		if err = o.repo.MessagingRepo.SendMessage(ctx, admin.Username, text); err != nil {
+			o.logger.Error("could not notify admin about order", "admin", admin.Username, "error", err)
+		}
+	}
+}
+
+func (o *order) GetUserOrders(ctx context.Context, req *model.GetOrderRequest, role protopb.Role) ([]model.Order, error) {
 	ctx, span := o.tracer.Start(ctx, "GetUserOrders.Order")
 	defer span.End()
 
-	if role == protopb.Role_GOD.String() || role == protopb.Role_ADMIN.String() {
+	if model.HasPermission(role, model.PermOrderViewAll) {
 		req.UserID = uuid.Nil
 	}
 
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	otpDigits      = 6
+	otpTTL         = 5 * time.Minute
+	otpCooldown    = time.Minute
+	otpMaxAttempts = 5
+	otpLockout     = 15 * time.Minute
+)
+
+var otpSubjects = map[model.OtpPurpose]string{
+	model.OtpPurposeRegistration:   "Registration code",
+	model.OtpPurposePasswordReset:  "Password reset code",
+	model.OtpPurposeLogin:          "Login code",
+	model.OtpPurposeUsernameChange: "Confirm your new username",
+}
+
+// otpManager issues and verifies one-time codes. Codes are kept in redis only as HMAC,
+// keyed by purpose and username:
+//
+//	otp:{purpose}:{username}          -> hash {code_hash, attempts}
+//	otp_cooldown:{purpose}:{username} -> resend cooldown marker
+//	otp_lock:{purpose}:{username}     -> lockout marker after too many wrong attempts
+type otpManager struct {
+	repo        *repository.Repository
+	redisClient *redis.Client
+	pepper      []byte
+	tracer      trace.Tracer
+}
+
+func newOtpManager(repo *repository.Repository, redisCli *redis.Client, pepper string) *otpManager {
+	return &otpManager{
+		repo:        repo,
+		redisClient: redisCli,
+		pepper:      []byte(pepper),
+		tracer:      otel.Tracer("otpManager"),
+	}
+}
+
+func otpKey(purpose model.OtpPurpose, username string) string {
+	return fmt.Sprintf("otp:%s:%s", purpose, username)
+}
+
+func otpCooldownKey(purpose model.OtpPurpose, username string) string {
+	return fmt.Sprintf("otp_cooldown:%s:%s", purpose, username)
+}
+
+func otpLockKey(purpose model.OtpPurpose, username string) string {
+	return fmt.Sprintf("otp_lock:%s:%s", purpose, username)
+}
+
+// Send issues a new code for the user and delivers it to the user's username (email or phone).
+func (o *otpManager) Send(ctx context.Context, purpose model.OtpPurpose, user model.User) error {
+	ctx, span := o.tracer.Start(ctx, "otpManager.Send")
+	defer span.End()
+
+	code, err := o.issue(ctx, purpose, user.Username)
+	if err != nil {
+		return err
+	}
+
+	return o.deliver(ctx, purpose, user, code)
+}
+
+// SendTo issues a code stored under key and delivers it to recipient. It is used when the code has to
+// reach an address that is not the user's username yet.
+func (o *otpManager) SendTo(ctx context.Context, purpose model.OtpPurpose, key string, recipient model.User) error {
+	ctx, span := o.tracer.Start(ctx, "otpManager.SendTo")
+	defer span.End()
+
+	code, err := o.issue(ctx, purpose, key)
+	if err != nil {
+		return err
+	}
+
+	return o.deliver(ctx, purpose, recipient, code)
+}
+
+// Throttle starts the resend cooldown without issuing a code. It is used when there is nothing to send,
+// so that requests for unknown or approved usernames are rate limited the same way as real ones.
+func (o *otpManager) Throttle(ctx context.Context, purpose model.OtpPurpose, username string) error {
+	ctx, span := o.tracer.Start(ctx, "otpManager.Throttle")
+	defer span.End()
+
+	return o.startCooldown(ctx, purpose, username)
+}
+
+// Pending reports whether there is an unexpired code for the purpose.
+func (o *otpManager) Pending(ctx context.Context, purpose model.OtpPurpose, username string) (bool, error) {
+	ctx, span := o.tracer.Start(ctx, "otpManager.Pending")
+	defer span.End()
+
+	n, err := o.redisClient.Exists(ctx, otpKey(purpose, username)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// Verify checks the code and consumes it on success. Every wrong guess is counted,
+// and after otpMaxAttempts the code is dropped and the username is locked for otpLockout.
+func (o *otpManager) Verify(ctx context.Context, purpose model.OtpPurpose, username, code string) error {
+	ctx, span := o.tracer.Start(ctx, "otpManager.Verify")
+	defer span.End()
+
+	if err := o.checkLock(ctx, purpose, username); err != nil {
+		return err
+	}
+
+	storedHash, err := o.redisClient.HGet(ctx, otpKey(purpose, username), "code_hash").Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return model.ErrOtpNotFound
+		}
+
+		return err
+	}
+
+	if hmac.Equal([]byte(storedHash), []byte(o.hash(purpose, username, code))) {
+		return o.redisClient.Del(ctx, otpKey(purpose, username)).Err()
+	}
+
+	attempts, err := o.redisClient.HIncrBy(ctx, otpKey(purpose, username), "attempts", 1).Result()
+	if err != nil {
+		return err
+	}
+
+	if attempts >= otpMaxAttempts {
+		pipe := o.redisClient.TxPipeline()
+		pipe.Del(ctx, otpKey(purpose, username))
+		pipe.Set(ctx, otpLockKey(purpose, username), 1, otpLockout)
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return err
+		}
+
+		return model.ErrOtpLocked
+	}
+
+	return model.ErrOtpInvalid
+}
+
+func (o *otpManager) issue(ctx context.Context, purpose model.OtpPurpose, username string) (string, error) {
+	if err := o.checkLock(ctx, purpose, username); err != nil {
+		return "", err
+	}
+
+	if err := o.startCooldown(ctx, purpose, username); err != nil {
+		return "", err
+	}
+
+	code, err := generateOtpCode()
+	if err != nil {
+		return "", err
+	}
+
+	pipe := o.redisClient.TxPipeline()
+	pipe.Del(ctx, otpKey(purpose, username))
+	pipe.HSet(ctx, otpKey(purpose, username), map[string]any{
+		"code_hash": o.hash(purpose, username, code),
+		"attempts":  0,
+	})
+	pipe.Expire(ctx, otpKey(purpose, username), otpTTL)
+
+	if _, err = pipe.Exec(ctx); err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+func (o *otpManager) startCooldown(ctx context.Context, purpose model.OtpPurpose, username string) error {
+	ok, err := o.redisClient.SetNX(ctx, otpCooldownKey(purpose, username), 1, otpCooldown).Result()
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return model.ErrOtpCooldown
+	}
+
+	return nil
+}
+
+func (o *otpManager) deliver(ctx context.Context, purpose model.OtpPurpose, user model.User, code string) error {
+	text := fmt.Sprintf("your %s code: %s. it expires in %d minutes", purpose, code, int(otpTTL.Minutes()))
+
+	return notifyUser(ctx, o.repo, user, otpSubjects[purpose], text)
+}
+
+func (o *otpManager) checkLock(ctx context.Context, purpose model.OtpPurpose, username string) error {
+	n, err := o.redisClient.Exists(ctx, otpLockKey(purpose, username)).Result()
+	if err != nil {
+		return err
+	}
+
+	if n > 0 {
+		return model.ErrOtpLocked
+	}
+
+	return nil
+}
+
+func (o *otpManager) hash(purpose model.OtpPurpose, username, code string) string {
+	mac := hmac.New(sha256.New, o.pepper)
+	mac.Write([]byte(string(purpose) + ":" + username + ":" + code))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func generateOtpCode() (string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < otpDigits; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", otpDigits, n), nil
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// profileService keeps a pending username change next to its otp code:
+//
+//	username_change:{userId} -> hash {username, username_type}
+type profileService struct {
+	repo        *repository.Repository
+	tracer      trace.Tracer
+	redisClient *redis.Client
+	sessions    *sessionStore
+	otp         *otpManager
+	guard       *loginGuard
+}
+
+func NewProfileService(
+	repo *repository.Repository,
+	redisCli *redis.Client,
+	sessions *sessionStore,
+	otp *otpManager,
+	guard *loginGuard,
+) Profile {
+	return &profileService{
+		repo:        repo,
+		tracer:      otel.Tracer("profileService"),
+		redisClient: redisCli,
+		sessions:    sessions,
+		otp:         otp,
+		guard:       guard,
+	}
+}
+
+func usernameChangeKey(userID uuid.UUID) string {
+	return "username_change:" + userID.String()
+}
+
+func (p *profileService) GetProfile(ctx context.Context, userID uuid.UUID) (*model.Profile, error) {
+	ctx, span := p.tracer.Start(ctx, "profileService.GetProfile")
+	defer span.End()
+
+	u, err := p.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildProfile(ctx, p.repo, *u)
+}
+
+func (p *profileService) UpdateProfile(ctx context.Context, userID uuid.UUID, req model.UpdateProfileRequest) (*model.Profile, error) {
+	ctx, span := p.tracer.Start(ctx, "profileService.UpdateProfile")
+	defer span.End()
+
+	u, err := p.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.FirstName != nil {
+		u.FirstName = *req.FirstName
+	}
+
+	if req.LastName != nil {
+		u.LastName = *req.LastName
+	}
+
+	if req.AvatarURL != nil {
+		u.AvatarURL = *req.AvatarURL
+	}
+
+	if _, err = p.repo.UserRepo.UpdateUser(ctx, u); err != nil {
+		return nil, err
+	}
+
+	return buildProfile(ctx, p.repo, *u)
+}
+
+// ChangePassword requires the current password. All other sessions are revoked.
+func (p *profileService) ChangePassword(ctx context.Context, userID, sessionID uuid.UUID, currentPassword, newPassword string) error {
+	ctx, span := p.tracer.Start(ctx, "profileService.ChangePassword")
+	defer span.End()
+
+	u, err := p.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err = p.checkPassword(ctx, *u, currentPassword); err != nil {
+		return err
+	}
+
+	hashedPsw, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	u.Password = string(hashedPsw)
+
+	if _, err = p.repo.UserRepo.UpdateUser(ctx, u); err != nil {
+		return err
+	}
+
+	if err = p.sessions.RevokeOthers(ctx, u.ID, sessionID); err != nil {
+		return err
+	}
+
+	// best effort, the password is already changed
+	_ = notifyUser(ctx, p.repo, *u, "Password changed",
+		"the password of your account was changed. if it was not you, reset your password")
+
+	return nil
+}
+
+// StartUsernameChange sends a code to the new email or phone. The username stays the same
+// until the code is confirmed with ConfirmUsernameChange.
+func (p *profileService) StartUsernameChange(ctx context.Context, userID uuid.UUID, password, newUsername string) error {
+	ctx, span := p.tracer.Start(ctx, "profileService.StartUsernameChange")
+	defer span.End()
+
+	usernameType := detectUsernameType(newUsername)
+	if usernameType == UsernameTypeNone {
+		return model.ErrInvalidUsername
+	}
+
+	u, err := p.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err = p.checkPassword(ctx, *u, password); err != nil {
+		return err
+	}
+
+	if _, err = p.repo.UserRepo.FindByUsername(ctx, newUsername); err == nil {
+		return model.ErrUserAlreadyExists
+	} else if !errors.Is(err, model.ErrUserNotFound) {
+		return err
+	}
+
+	pipe := p.redisClient.TxPipeline()
+	pipe.Del(ctx, usernameChangeKey(u.ID))
+	pipe.HSet(ctx, usernameChangeKey(u.ID), map[string]any{
+		"username":      newUsername,
+		"username_type": usernameType,
+	})
+	pipe.Expire(ctx, usernameChangeKey(u.ID), otpTTL)
+
+	if _, err = pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	return p.otp.SendTo(ctx, model.OtpPurposeUsernameChange, u.ID.String(), model.User{
+		Username:     newUsername,
+		UsernameType: usernameType,
+	})
+}
+
+// ConfirmUsernameChange checks the code and swaps the username. Other sessions are revoked,
+// the current one keeps working, and its next refresh carries the new username.
+func (p *profileService) ConfirmUsernameChange(ctx context.Context, userID, sessionID uuid.UUID, code string) (*model.Profile, error) {
+	ctx, span := p.tracer.Start(ctx, "profileService.ConfirmUsernameChange")
+	defer span.End()
+
+	pending, err := p.redisClient.HGetAll(ctx, usernameChangeKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pending) == 0 {
+		return nil, model.ErrOtpNotFound
+	}
+
+	usernameType, err := strconv.Atoi(pending["username_type"])
+	if err != nil {
+		return nil, model.ErrOtpNotFound
+	}
+
+	if err = p.otp.Verify(ctx, model.OtpPurposeUsernameChange, userID.String(), code); err != nil {
+		return nil, err
+	}
+
+	u, err := p.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	old := *u
+
+	if err = p.repo.UserRepo.ChangeUsername(ctx, u.ID, old.Username, pending["username"], usernameType); err != nil {
+		return nil, err
+	}
+
+	u.Username = pending["username"]
+	u.UsernameType = usernameType
+
+	if err = p.redisClient.Del(ctx, usernameChangeKey(u.ID)).Err(); err != nil {
+		return nil, err
+	}
+
+	if err = p.sessions.RevokeOthers(ctx, u.ID, sessionID); err != nil {
+		return nil, err
+	}
+
+	// the old phone number may already belong to someone else, so the new address is not disclosed.
+	// best effort, the username is already changed
+	_ = notifyUser(ctx, p.repo, old, "Username changed",
+		"the username of your account was changed and this address can no longer be used to sign in. "+
+			"if it was not you, contact the administrator")
+
+	return buildProfile(ctx, p.repo, *u)
+}
+
+func (p *profileService) ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]model.Session, error) {
+	ctx, span := p.tracer.Start(ctx, "profileService.ListSessions")
+	defer span.End()
+
+	sessions, err := p.sessions.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession signs out one of the user's devices. The JWT middleware rejects its access token right away.
+func (p *profileService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	ctx, span := p.tracer.Start(ctx, "profileService.RevokeSession")
+	defer span.End()
+
+	sess, err := p.sessions.Get(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, model.ErrSessionRevoked) {
+			return model.ErrSessionNotFound
+		}
+
+		return err
+	}
+
+	// someone else's session id must look exactly like a missing one
+	if sess.UserID != userID {
+		return model.ErrSessionNotFound
+	}
+
+	return p.sessions.Revoke(ctx, sessionID)
+}
+
+func (p *profileService) RevokeOtherSessions(ctx context.Context, userID, currentSessionID uuid.UUID) error {
+	ctx, span := p.tracer.Start(ctx, "profileService.RevokeOtherSessions")
+	defer span.End()
+
+	return p.sessions.RevokeOthers(ctx, userID, currentSessionID)
+}
+
+// checkPassword counts wrong guesses in the login guard, so a stolen access token
+// can not be used to brute force the password.
+func (p *profileService) checkPassword(ctx context.Context, u model.User, password string) error {
+	if err := p.guard.Check(ctx, u.Username, ""); err != nil {
+		return err
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
+	if err == nil {
+		return p.guard.Succeed(ctx, u.Username)
+	}
+
+	if !errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return err
+	}
+
+	locked, err := p.guard.Fail(ctx, u.Username, "")
+	if err != nil {
+		return err
+	}
+
+	if locked {
+		return model.ErrLoginLocked
+	}
+
+	return model.ErrCurrentPassword
+}
+
+// buildProfile is the user's public view together with the bound apartment.
+func buildProfile(ctx context.Context, repo *repository.Repository, u model.User) (*model.Profile, error) {
+	profile := &model.Profile{
+		ID:           u.ID,
+		FirstName:    u.FirstName,
+		LastName:     u.LastName,
+		Username:     u.Username,
+		UsernameType: u.UsernameType,
+		AvatarURL:    u.AvatarURL,
+		IsApproved:   u.IsApproved,
+		Role:         u.RoleID.String(),
+		TotpEnabled:  u.TotpEnabled,
+	}
+
+	if u.ApartmentID == uuid.Nil {
+		return profile, nil
+	}
+
+	ap, err := repo.ApartmentRepo.GetApartmentByID(ctx, u.ApartmentID)
+	if err != nil {
+		// the apartment may have been removed while the user still points to it
+		if errors.Is(err, model.ErrApartmentNotFound) {
+			return profile, nil
+		}
+
+		return nil, err
+	}
+
+	address := []model.ApartmentDetails{{Apartment: *ap}}
+	if err = fillAddress(ctx, repo, address); err != nil {
+		return nil, err
+	}
+
+	profile.Apartment = &model.ProfileApartment{
+		ID:         ap.Id,
+		Building:   address[0].Building,
+		Entrance:   address[0].Entrance,
+		Floor:      ap.Floor,
+		DoorNumber: ap.DoorNumber,
+		IsOwner:    ap.OwnerId != nil && *ap.OwnerId == u.ID,
+	}
+
+	member, err := repo.MemberRepo.Find(ctx, ap.Id, u.ID)
+	switch {
+	case err == nil && member.IsApproved():
+		profile.Apartment.MemberRole = member.Role
+		profile.Apartment.CanVote = member.CanVote()
+	case err != nil && !errors.Is(err, model.ErrMemberNotFound):
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (p *profileService) findUser(ctx context.Context, userID uuid.UUID) (*model.User, error) {
+	u, err := p.repo.UserRepo.FindById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.ID == uuid.Nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	return u, nil
+}
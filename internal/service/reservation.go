@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"regexp"
+	"slices"
 	"sort"
 	"time"
 
@@ -19,16 +20,19 @@ import (
 type reservation struct {
 	tracer trace.Tracer
 	repo   *repository.Repository
+	// tz is the venue timezone: days, slot times and responses are in it
+	tz *time.Location
 }
 
 var phoneNumRegex = regexp.MustCompile(`^\+\d{11}$`)
 
 const totalFreeReservations = 5
 
-func NewReservation(repo *repository.Repository) Reservation {
+func NewReservation(repo *repository.Repository, tz *time.Location) Reservation {
 	return &reservation{
 		tracer: otel.Tracer("reservationService"),
 		repo:   repo,
+		tz:     tz,
 	}
 }
 
@@ -36,8 +40,7 @@ func (r *reservation) GetUserReservations(ctx context.Context, userId uuid.UUID,
 	ctx, span := r.tracer.Start(ctx, "reservation.GetReservation")
 	defer span.End()
 
-	startTime := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
-	endTime := time.Date(end.Year(), end.Month(), end.Day(), 23, 59, 59, 0, time.UTC)
+	startTime, endTime := venueDays(start, end, r.tz)
 
 	user, err := r.repo.UserRepo.FindById(ctx, userId)
 	if err != nil {
@@ -52,7 +55,17 @@ func (r *reservation) GetUserReservations(ctx context.Context, userId uuid.UUID,
 		return nil, err
 	}
 
-	return r.filterReservation(ctx, reservations, *user)
+	res, err := r.filterReservation(ctx, reservations, *user)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range res {
+		res[i].StartTime = res[i].StartTime.In(r.tz)
+		res[i].EndTime = res[i].EndTime.In(r.tz)
+	}
+
+	return res, nil
 }
 
 func (r *reservation) GetUnfilteredReservations(ctx context.Context, req model.CinemaReservation) ([]model.CinemaReservation, error) {
@@ -74,7 +87,7 @@ func (r *reservation) filterReservation(ctx context.Context, reservations []mode
 	ctx, span := r.tracer.Start(ctx, "reservation.filterReservation")
 	defer span.End()
 
-	if user.RoleID == protopb.Role_ADMIN || user.RoleID == protopb.Role_GOD {
+	if model.HasPermission(user.RoleID, model.PermReservationViewAll) {
 		return reservations, nil
 	}
 
@@ -94,7 +107,8 @@ func (r *reservation) MakeReservation(
 	date time.Time,
 	positions []int16,
 	peopleNum uint8,
-	role, username string,
+	role protopb.Role,
+	username string,
 ) (reservationLeft int, err error) {
 	ctx, span := r.tracer.Start(ctx, "reservation.MakeReservation")
 	defer span.End()
@@ -107,19 +121,13 @@ func (r *reservation) MakeReservation(
 		return 0, model.ErrInvalidInput
 	}
 
-	sort.Slice(positions, func(i, j int) bool {
-		return positions[i] < positions[j]
-	})
+	date = model.VenueDay(date, r.tz)
 
-	if len(positions) == 2 && positions[1] != positions[0]+1 {
-		return 0, model.ErrInvalidInput
-	}
-
-	if err = r.repo.SlotRepo.EnsureDailySlots(ctx, date, time.UTC); err != nil {
+	if err = r.repo.SlotRepo.EnsureDailySlots(ctx, date, r.tz); err != nil {
 		return 0, err
 	}
 
-	idMap, err := r.repo.SlotRepo.GetDailySlotIDsByPositions(ctx, date, positions, time.UTC)
+	idMap, err := r.repo.SlotRepo.GetDailySlotIDsByPositions(ctx, date, positions, r.tz)
 	if err != nil {
 		return 0, err
 	}
@@ -133,20 +141,16 @@ func (r *reservation) MakeReservation(
 		dailyIDs = append(dailyIDs, id)
 	}
 
-	dSlots, err := r.repo.SlotRepo.GetDailySlots(ctx, date, time.UTC)
+	dSlots, err := r.repo.SlotRepo.GetDailySlots(ctx, date, r.tz)
 	if err != nil {
 		return 0, err
 	}
 
-	var start, end time.Time
-	posToSlot := map[int16]model.DailySlot{}
-	for _, s := range dSlots {
-		posToSlot[s.Position] = s
+	start, end, err := bookedRange(dSlots, positions, r.tz)
+	if err != nil {
+		return 0, err
 	}
 
-	start = posToSlot[positions[0]].StartAt
-	end = posToSlot[positions[len(positions)-1]].EndAt
-
 	res := &model.CinemaReservation{
 		ID:         uuid.New(),
 		UserID:     userID,
@@ -157,7 +161,7 @@ func (r *reservation) MakeReservation(
 		PhoneNum:   "",
 	}
 
-	if role == protopb.Role_ADMIN.String() || role == protopb.Role_GOD.String() {
+	if model.HasPermission(role, model.PermReservationApprove) {
 		res.IsApproved = true
 	}
 
@@ -170,17 +174,17 @@ func (r *reservation) MakeReservation(
 		return 0, err
 	}
 
-	userFromSameApart, err := r.repo.UserRepo.FindByApartmentId(ctx, user.ApartmentID)
+	household, err := r.householdMembers(ctx, *user)
 	if err != nil {
 		return 0, err
 	}
 
 	reservationLeft = totalFreeReservations
-	for _, u := range userFromSameApart {
+	for _, memberID := range household {
 		reservations, err := r.repo.ReservationRepo.GetByFilters(ctx, &model.GetReservationRequest{
 			StartTimeFrom: start,
 			EndTimeTo:     end,
-			UserID:        u.ID,
+			UserID:        memberID,
 		})
 		if err != nil {
 			return 0, err
@@ -200,6 +204,31 @@ func (r *reservation) MakeReservation(
 	return reservationLeft, nil
 }
 
+// householdMembers returns the users sharing the reservation quota with the user: approved members
+// of the user's apartment, or only the user when they do not belong to a household.
+func (r *reservation) householdMembers(ctx context.Context, user model.User) ([]uuid.UUID, error) {
+	member, err := householdOf(ctx, r.repo, user)
+	if err != nil {
+		if errors.Is(err, model.ErrNotApartmentMember) {
+			return []uuid.UUID{user.ID}, nil
+		}
+
+		return nil, err
+	}
+
+	members, err := r.repo.MemberRepo.FindByApartment(ctx, member.ApartmentID, model.MemberStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]uuid.UUID, 0, len(members))
+	for _, m := range members {
+		res = append(res, m.UserID)
+	}
+
+	return res, nil
+}
+
 func (r *reservation) ApproveReservation(ctx context.Context, id uuid.UUID) error {
 	ctx, span := r.tracer.Start(ctx, "reservation.ApproveReservation")
 	defer span.End()
@@ -226,11 +255,21 @@ func (r *reservation) GetFreeSlots(ctx context.Context, date time.Time) ([]model
 	ctx, span := r.tracer.Start(ctx, "reservation.GetFreeSlots")
 	defer span.End()
 
-	if err := r.repo.SlotRepo.EnsureDailySlots(ctx, date, time.UTC); err != nil {
+	date = model.VenueDay(date, r.tz)
+
+	if err := r.repo.SlotRepo.EnsureDailySlots(ctx, date, r.tz); err != nil {
 		return nil, nil, err
 	}
 
-	free, err := r.repo.SlotRepo.GetFreeDailySlots(ctx, date, time.UTC)
+	free, err := r.repo.SlotRepo.GetFreeDailySlots(ctx, date, r.tz)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inVenueZone(free, r.tz)
+
+	// reserved slots count too: a pair must not skip over a booked slot between its halves
+	day, err := r.repo.SlotRepo.GetDailySlots(ctx, date, r.tz)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -241,11 +280,77 @@ func (r *reservation) GetFreeSlots(ctx context.Context, date time.Time) ([]model
 	}
 
 	pairs := make([][2]int16, 0, 4)
-	for _, s := range free {
-		if freePos[s.Position+1] {
-			pairs = append(pairs, [2]int16{s.Position, s.Position + 1})
+	for _, p := range consecutivePairs(day) {
+		if freePos[p[0]] && freePos[p[1]] {
+			pairs = append(pairs, p)
 		}
 	}
 
 	return free, pairs, nil
 }
+
+// venueDays returns the first and the last second of the calendar days start..end in the venue timezone tz.
+func venueDays(start, end time.Time, tz *time.Location) (time.Time, time.Time) {
+	return model.VenueDay(start, tz), model.VenueDay(end, tz).AddDate(0, 0, 1).Add(-time.Second)
+}
+
+// inVenueZone moves the slots read from Postgres, which returns UTC, to the venue's clock residents expect.
+func inVenueZone(slots []model.DailySlot, tz *time.Location) {
+	for i := range slots {
+		slots[i].SlotDate = model.VenueDay(slots[i].SlotDate, tz)
+		slots[i].StartAt = slots[i].StartAt.In(tz)
+		slots[i].EndAt = slots[i].EndAt.In(tz)
+	}
+}
+
+// bookedRange returns the time the slots at positions cover, in the venue timezone tz. Two slots are
+// booked together only when the second one follows the first, ErrInvalidInput otherwise.
+func bookedRange(day []model.DailySlot, positions []int16, tz *time.Location) (time.Time, time.Time, error) {
+	posToSlot := map[int16]model.DailySlot{}
+	for _, s := range day {
+		posToSlot[s.Position] = s
+	}
+
+	chosen := make([]model.DailySlot, 0, len(positions))
+	for _, p := range positions {
+		s, ok := posToSlot[p]
+		if !ok {
+			return time.Time{}, time.Time{}, model.ErrInvalidInput
+		}
+		chosen = append(chosen, s)
+	}
+
+	if len(chosen) == 0 {
+		return time.Time{}, time.Time{}, model.ErrInvalidInput
+	}
+
+	sort.Slice(chosen, func(i, j int) bool {
+		return chosen[i].StartAt.Before(chosen[j].StartAt)
+	})
+
+	if len(chosen) == 2 && !slices.Contains(consecutivePairs(day), [2]int16{chosen[0].Position, chosen[1].Position}) {
+		return time.Time{}, time.Time{}, model.ErrInvalidInput
+	}
+
+	return chosen[0].StartAt.In(tz), chosen[len(chosen)-1].EndAt.In(tz), nil
+}
+
+// consecutivePairs returns the positions of the slots of a day that can be booked together: the second
+// is the next slot in time and starts no earlier than the first ends. Positions order the templates, but
+// after a template change a reserved slot keeps its old time, so neighbouring positions need not be
+// neighbouring times.
+func consecutivePairs(day []model.DailySlot) [][2]int16 {
+	byTime := slices.Clone(day)
+	sort.Slice(byTime, func(i, j int) bool {
+		return byTime[i].StartAt.Before(byTime[j].StartAt)
+	})
+
+	var pairs [][2]int16
+	for i := 0; i+1 < len(byTime); i++ {
+		if !byTime[i+1].StartAt.Before(byTime[i].EndAt) {
+			pairs = append(pairs, [2]int16{byTime[i].Position, byTime[i+1].Position})
+		}
+	}
+
+	return pairs
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+)
+
+func almaty(t *testing.T) *time.Location {
+	t.Helper()
+
+	tz, err := time.LoadLocation("Asia/Almaty")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tz
+}
+
+// dbSlot is a daily slot as Postgres returns it: the date at UTC midnight, the times in UTC.
+func dbSlot(pos int16, date string, from, to time.Duration, tz *time.Location) model.DailySlot {
+	d, _ := time.Parse(time.DateOnly, date)
+	day := model.VenueDay(d, tz)
+
+	return model.DailySlot{
+		SlotDate:  d,
+		Position:  pos,
+		StartAt:   model.SlotAt(day, from, tz).UTC(),
+		EndAt:     model.SlotAt(day, to, tz).UTC(),
+		IsEnabled: true,
+	}
+}
+
+func TestVenueDays(t *testing.T) {
+	tz := almaty(t)
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		wantFrom   time.Time
+		wantTo     time.Time
+	}{
+		{
+			name:     "one day from the API",
+			start:    time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+			end:      time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+			wantFrom: time.Date(2026, 3, 9, 19, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2026, 3, 10, 18, 59, 59, 0, time.UTC),
+		},
+		{
+			name:     "across the year",
+			start:    time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+			end:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantFrom: time.Date(2025, 12, 30, 19, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2026, 1, 1, 18, 59, 59, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := venueDays(tt.start, tt.end, tz)
+			if !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+				t.Errorf("venueDays() = %v..%v, want %v..%v", from.UTC(), to.UTC(), tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestInVenueZone(t *testing.T) {
+	tz := almaty(t)
+
+	// 23:00-23:59 local is 18:00-18:59 UTC, still the same day on both clocks
+	late := dbSlot(4, "2026-03-10", 23*time.Hour, 23*time.Hour+59*time.Minute, tz)
+	// 00:00-02:00 local is 19:00-21:00 UTC of the previous day
+	early := dbSlot(1, "2026-03-11", 0, 2*time.Hour, tz)
+
+	slots := []model.DailySlot{late, early}
+	inVenueZone(slots, tz)
+
+	tests := []struct {
+		slot      model.DailySlot
+		wantDate  string
+		wantStart string
+		wantEnd   string
+	}{
+		{slots[0], "2026-03-10", "2026-03-10 23:00", "2026-03-10 23:59"},
+		{slots[1], "2026-03-11", "2026-03-11 00:00", "2026-03-11 02:00"},
+	}
+
+	for _, tt := range tests {
+		s := tt.slot
+		if s.SlotDate.Location() != tz || s.StartAt.Location() != tz || s.EndAt.Location() != tz {
+			t.Errorf("slot %d is not in the venue zone: %v, %v, %v", s.Position, s.SlotDate, s.StartAt, s.EndAt)
+		}
+
+		if got := s.SlotDate.Format(time.DateOnly); got != tt.wantDate {
+			t.Errorf("slot %d SlotDate = %s, want %s", s.Position, got, tt.wantDate)
+		}
+
+		if got := s.StartAt.Format("2006-01-02 15:04"); got != tt.wantStart {
+			t.Errorf("slot %d StartAt = %s, want %s", s.Position, got, tt.wantStart)
+		}
+
+		if got := s.EndAt.Format("2006-01-02 15:04"); got != tt.wantEnd {
+			t.Errorf("slot %d EndAt = %s, want %s", s.Position, got, tt.wantEnd)
+		}
+	}
+}
+
+func TestBookedRange(t *testing.T) {
+	tz := almaty(t)
+
+	day := []model.DailySlot{
+		dbSlot(1, "2026-03-10", 10*time.Hour, 14*time.Hour, tz),
+		dbSlot(2, "2026-03-10", 14*time.Hour, 18*time.Hour, tz),
+		dbSlot(3, "2026-03-10", 18*time.Hour, 22*time.Hour, tz),
+		dbSlot(4, "2026-03-10", 22*time.Hour, 23*time.Hour+59*time.Minute, tz),
+	}
+
+	tests := []struct {
+		name      string
+		positions []int16
+		wantStart string
+		wantEnd   string
+		wantErr   error
+	}{
+		{name: "one slot", positions: []int16{2}, wantStart: "2026-03-10 14:00", wantEnd: "2026-03-10 18:00"},
+		{name: "pair", positions: []int16{1, 2}, wantStart: "2026-03-10 10:00", wantEnd: "2026-03-10 18:00"},
+		{name: "pair in any order", positions: []int16{2, 1}, wantStart: "2026-03-10 10:00", wantEnd: "2026-03-10 18:00"},
+		{name: "up to midnight", positions: []int16{3, 4}, wantStart: "2026-03-10 18:00", wantEnd: "2026-03-10 23:59"},
+		{name: "not consecutive", positions: []int16{1, 3}, wantErr: model.ErrInvalidInput},
+		{name: "same slot twice", positions: []int16{2, 2}, wantErr: model.ErrInvalidInput},
+		{name: "unknown position", positions: []int16{5}, wantErr: model.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := bookedRange(day, tt.positions, tz)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("bookedRange() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if start.Location() != tz || end.Location() != tz {
+				t.Errorf("bookedRange() = %v..%v, want the venue zone", start, end)
+			}
+
+			if got := start.Format("2006-01-02 15:04"); got != tt.wantStart {
+				t.Errorf("bookedRange() start = %s, want %s", got, tt.wantStart)
+			}
+
+			if got := end.Format("2006-01-02 15:04"); got != tt.wantEnd {
+				t.Errorf("bookedRange() end = %s, want %s", got, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestConsecutivePairs(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	slot := func(pos int16, from, to int) model.DailySlot {
+		return model.DailySlot{
+			Position: pos,
+			StartAt:  day.Add(time.Duration(from) * time.Hour),
+			EndAt:    day.Add(time.Duration(to) * time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name string
+		day  []model.DailySlot
+		want [][2]int16
+	}{
+		{
+			name: "positions follow times",
+			day:  []model.DailySlot{slot(1, 10, 14), slot(2, 14, 18), slot(3, 18, 22)},
+			want: [][2]int16{{1, 2}, {2, 3}},
+		},
+		{
+			name: "break between slots",
+			day:  []model.DailySlot{slot(1, 10, 13), slot(2, 14, 18)},
+			want: [][2]int16{{1, 2}},
+		},
+		{
+			// template 1 moved to 14-18 while its reserved slot kept 10-14, template 2 took 10-14
+			name: "swapped templates",
+			day:  []model.DailySlot{slot(2, 10, 14), slot(1, 14, 18), slot(3, 18, 22)},
+			want: [][2]int16{{2, 1}, {1, 3}},
+		},
+		{
+			name: "overlapping slots are not a pair",
+			day:  []model.DailySlot{slot(1, 10, 14), slot(2, 12, 16)},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := consecutivePairs(tt.day); !slices.Equal(got, tt.want) {
+				t.Errorf("consecutivePairs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,15 +9,25 @@ import (
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
 	"github.com/podpivasniki1488/assyl-backend/internal/repository"
 	"github.com/podpivasniki1488/assyl-backend/pkg"
+	"github.com/podpivasniki1488/assyl-backend/protopb"
 	"github.com/redis/go-redis/v9"
 )
 
 type Service struct {
-	UserValidator  UserValidator
 	UserManagement UserManagement
+	Profile        Profile
+	Export         Export
 	Auth           Auth
+	TwoFactor      TwoFactor
 	Apartment      Apartment
+	Building       Building
+	Binding        Binding
+	Invitation     Invitation
+	Membership     Membership
+	Import         Import
+	Layout         Layout
 	Reservation    Reservation
+	SlotTemplates  SlotTemplates
 	Channel        Channel
 	Feedback       Feedback
 	Order          Order
@@ -25,23 +35,161 @@ type Service struct {
 }
 
 type Auth interface {
-	Login(ctx context.Context, user model.User) (token string, u *model.User, err error)
-	Register(ctx context.Context, user model.User) error
+	Login(ctx context.Context, user model.User, client model.ClientInfo) (*model.LoginResult, error)
+	CompleteTwoFactorLogin(ctx context.Context, challenge, code string, client model.ClientInfo) (*model.LoginResult, error)
+	StartTwoFactorSetup(ctx context.Context, challenge string) (model.TotpEnrollment, error)
+	OIDCProviders() []string
+	StartOIDCLogin(ctx context.Context, provider string) (authURL string, err error)
+	CompleteOIDCLogin(ctx context.Context, provider, code, state string, client model.ClientInfo) (*model.LoginResult, error)
+	Register(ctx context.Context, user model.User, invitationCode string) error
 	Confirm(ctx context.Context, username, otpCode string) error
+	Refresh(ctx context.Context, refreshToken string) (*model.TokenPair, error)
+	Logout(ctx context.Context, sessionID uuid.UUID) error
+	// ValidateSession also refreshes the session's last seen time and IP
+	ValidateSession(ctx context.Context, sessionID, userID uuid.UUID, clientIP string) error
+	ResendOtp(ctx context.Context, username string, purpose model.OtpPurpose) error
+	ForgotPassword(ctx context.Context, username string) error
+	ResetPassword(ctx context.Context, username, otpCode, newPassword string) error
 }
 
-type UserValidator interface {
-	Login(ctx context.Context, login, psw string) (*model.User, error)
+type TwoFactor interface {
+	StartEnrollment(ctx context.Context, userID uuid.UUID) (model.TotpEnrollment, error)
+	Enable(ctx context.Context, userID uuid.UUID, code string) (recoveryCodes []string, err error)
+	Disable(ctx context.Context, userID uuid.UUID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	// SealLegacySecrets encrypts TOTP secrets stored in plain text and returns how many were changed
+	SealLegacySecrets(ctx context.Context) (int, error)
 }
 
 type UserManagement interface {
-	DeleteUserByUsername(ctx context.Context, username string) error
-	BindApartmentToUser(ctx context.Context, username string, apartmentId uuid.UUID) error
+	DeleteUserByUsername(ctx context.Context, username string, mode model.ErasureMode) (*model.ErasureReport, error)
+	ListUsers(ctx context.Context, req model.GetUsersRequest) (model.Page[model.User], error)
+	ChangeRole(ctx context.Context, actorID uuid.UUID, actorRole protopb.Role, username string, role protopb.Role) error
+	SetApproval(ctx context.Context, actorID uuid.UUID, actorRole protopb.Role, username string, approved bool) error
+	ListLockouts(ctx context.Context) ([]model.LoginLockout, error)
+	ClearLockout(ctx context.Context, username string) error
+}
+
+type Profile interface {
+	GetProfile(ctx context.Context, userID uuid.UUID) (*model.Profile, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, req model.UpdateProfileRequest) (*model.Profile, error)
+	// ChangePassword keeps the session the request came from and revokes all others
+	ChangePassword(ctx context.Context, userID, sessionID uuid.UUID, currentPassword, newPassword string) error
+	// StartUsernameChange sends an otp to the new email or phone, the password is required
+	StartUsernameChange(ctx context.Context, userID uuid.UUID, password, newUsername string) error
+	ConfirmUsernameChange(ctx context.Context, userID, sessionID uuid.UUID, code string) (*model.Profile, error)
+	// ListSessions returns the user's active sessions, the one with currentSessionID is marked as current
+	ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]model.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	RevokeOtherSessions(ctx context.Context, userID, currentSessionID uuid.UUID) error
+}
+
+type Export interface {
+	// ExportUserData returns the archive right away for small exports and a background job for large ones
+	ExportUserData(ctx context.Context, requestedBy, userID uuid.UUID) (*model.ExportResult, error)
+	GetExportJob(ctx context.Context, actorID uuid.UUID, actorRole protopb.Role, jobID uuid.UUID) (*model.ExportJob, error)
+	DownloadExport(ctx context.Context, actorID uuid.UUID, actorRole protopb.Role, jobID uuid.UUID) (*model.ExportJob, []byte, error)
 }
 
 type Apartment interface {
-	CreateApartment(ctx context.Context, req model.Apartment) error
-	GetApartment(ctx context.Context, req model.Apartment) (*model.Apartment, error)
+	CreateApartment(ctx context.Context, req model.ApartmentAddress) error
+	ListApartments(ctx context.Context, req model.GetApartmentsRequest) (model.Page[model.ApartmentDetails], error)
+	GetApartmentDetails(ctx context.Context, id uuid.UUID) (*model.ApartmentDetails, error)
+	// UpdateApartment changes the address and attributes, every change is written to the audit log
+	UpdateApartment(ctx context.Context, actorID, id uuid.UUID, req model.UpdateApartmentRequest) (*model.Apartment, error)
+	// DeleteApartment removes an apartment nobody is bound to
+	DeleteApartment(ctx context.Context, actorID, id uuid.UUID) error
+	// UnbindApartment detaches the owner and all residents from the apartment and notifies them
+	UnbindApartment(ctx context.Context, actorID, id uuid.UUID) error
+	GetApartmentAudit(ctx context.Context, id uuid.UUID, page model.Pagination) (model.Page[model.ApartmentAudit], error)
+	// GetOwnershipHistory lists who owned the apartment and when, the latest first
+	GetOwnershipHistory(ctx context.Context, id uuid.UUID) ([]model.ApartmentOwnership, error)
+}
+
+// Binding handles ownership claims: a user asks to become the owner of an apartment and staff approve or
+// reject the request. Users with apartment.bind_any are bound at once.
+type Binding interface {
+	RequestBinding(ctx context.Context, userID uuid.UUID, role protopb.Role, req model.CreateBindingRequest) (*model.BindingRequest, error)
+	ListBindingRequests(ctx context.Context, req model.GetBindingRequestsRequest) (model.Page[model.BindingRequest], error)
+	// GetBindingProof is available to the requester and to reviewers
+	GetBindingProof(ctx context.Context, actorID uuid.UUID, actorRole protopb.Role, id uuid.UUID) (*model.BindingDocument, error)
+	// ApproveBindingRequest makes the requester the owner and rejects other pending requests for the apartment
+	ApproveBindingRequest(ctx context.Context, reviewerID, id uuid.UUID) (*model.BindingRequest, error)
+	// RejectBindingRequest notifies the requester with the reason
+	RejectBindingRequest(ctx context.Context, reviewerID, id uuid.UUID, reason string) (*model.BindingRequest, error)
+}
+
+// Building manages the blocks of the complex and their entrances.
+type Building interface {
+	CreateBuilding(ctx context.Context, req model.Building) (*model.Building, error)
+	ListBuildings(ctx context.Context) ([]model.BuildingDetails, error)
+	UpdateBuilding(ctx context.Context, req model.Building) (*model.Building, error)
+	// DeleteBuilding removes an empty building with its entrances
+	DeleteBuilding(ctx context.Context, id uuid.UUID) error
+	CreateEntrance(ctx context.Context, buildingID uuid.UUID, number uint8) (*model.Entrance, error)
+	DeleteEntrance(ctx context.Context, buildingID, id uuid.UUID) error
+}
+
+type Invitation interface {
+	CreateInvitation(
+		ctx context.Context,
+		actorID uuid.UUID,
+		actorRole protopb.Role,
+		req model.CreateInvitationRequest,
+	) (*model.CreatedInvitation, error)
+	GetInvitations(ctx context.Context, actorID uuid.UUID, actorRole protopb.Role, apartmentID uuid.UUID) ([]model.Invitation, error)
+	RevokeInvitation(ctx context.Context, actorID uuid.UUID, actorRole protopb.Role, id uuid.UUID) error
+	RedeemInvitation(ctx context.Context, userID uuid.UUID, code string) error
+}
+
+// Membership manages households: users ask to join an apartment and its owners or co-owners approve them.
+type Membership interface {
+	RequestMembership(ctx context.Context, userID, apartmentID uuid.UUID, role model.MemberRole) (*model.ApartmentMember, error)
+	ListMembers(ctx context.Context, actorID uuid.UUID, actorRole protopb.Role, apartmentID uuid.UUID) ([]model.ApartmentMember, error)
+	ListUserMemberships(ctx context.Context, userID uuid.UUID) ([]model.ApartmentMember, error)
+	// ApproveMember approves a pending request, an empty role keeps the requested one
+	ApproveMember(
+		ctx context.Context,
+		actorID uuid.UUID,
+		actorRole protopb.Role,
+		apartmentID, userID uuid.UUID,
+		role model.MemberRole,
+	) (*model.ApartmentMember, error)
+	RejectMember(ctx context.Context, actorID uuid.UUID, actorRole protopb.Role, apartmentID, userID uuid.UUID) error
+	ChangeMemberRole(
+		ctx context.Context,
+		actorID uuid.UUID,
+		actorRole protopb.Role,
+		apartmentID, userID uuid.UUID,
+		role model.MemberRole,
+	) (*model.ApartmentMember, error)
+	// RemoveMember removes a member, members may also remove themselves. The owner can not be removed.
+	RemoveMember(ctx context.Context, actorID uuid.UUID, actorRole protopb.Role, apartmentID, userID uuid.UUID) error
+}
+
+// SlotTemplates manages the cinema's slot templates. Changes reach the daily slots that have not started yet,
+// reserved slots keep the times they were booked for.
+type SlotTemplates interface {
+	ListSlotTemplates(ctx context.Context) ([]model.SlotTemplate, error)
+	CreateSlotTemplate(ctx context.Context, req model.SlotTemplate) (*model.SlotTemplate, error)
+	UpdateSlotTemplate(ctx context.Context, id int16, req model.UpdateSlotTemplateRequest) (*model.SlotTemplateChange, error)
+	DeleteSlotTemplate(ctx context.Context, id int16) (*model.SlotTemplateChange, error)
+}
+
+// Layout creates the apartments of an entrance from a declarative layout. Running the same layout again changes nothing.
+type Layout interface {
+	GenerateLayout(ctx context.Context, actorID uuid.UUID, layout model.ApartmentLayout, opts model.LayoutOptions) (*model.LayoutReport, error)
+}
+
+// Import creates apartments and residents from a spreadsheet. Re-importing the same file changes nothing.
+type Import interface {
+	ImportResidents(
+		ctx context.Context,
+		actorID uuid.UUID,
+		format model.ImportFormat,
+		data []byte,
+		opts model.ImportOptions,
+	) (*model.ImportReport, error)
 }
 
 type Reservation interface {
@@ -51,7 +199,8 @@ type Reservation interface {
 		date time.Time,
 		positions []int16,
 		peopleNum uint8,
-		role, username string,
+		role protopb.Role,
+		username string,
 	) (reservationLeft int, err error)
 	GetUserReservations(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]model.CinemaReservation, error)
 	GetUnfilteredReservations(ctx context.Context, req model.CinemaReservation) ([]model.CinemaReservation, error)
@@ -61,7 +210,8 @@ type Reservation interface {
 
 type Channel interface {
 	SendChannelMessage(ctx context.Context, msg model.ChannelMessage) error
-	GetByTimePeriod(ctx context.Context, from, to time.Time) ([]model.ChannelMessage, error)
+	// GetByTimePeriod returns announcements of the period, with buildingID set only those that concern the building
+	GetByTimePeriod(ctx context.Context, from, to time.Time, buildingID uuid.UUID) ([]model.ChannelMessage, error)
 }
 
 type Feedback interface {
@@ -71,7 +221,7 @@ type Feedback interface {
 
 type Order interface {
 	OrderService(ctx context.Context, req *model.Order) error
-	GetUserOrders(ctx context.Context, req *model.GetOrderRequest, role string) ([]model.Order, error)
+	GetUserOrders(ctx context.Context, req *model.GetOrderRequest, role protopb.Role) ([]model.Order, error)
 }
 
 type Chat interface {
@@ -81,22 +231,55 @@ type Chat interface {
 	StartNewChat(ctx context.Context, creatorID uuid.UUID, participantsIDs []uuid.UUID) (uuid.UUID, error)
 }
 
+type TwoFactorConfig struct {
+	// Issuer is the account label shown in authenticator apps
+	Issuer string
+	// RequiredForAdmins makes 2FA mandatory for ADMIN and GOD
+	RequiredForAdmins bool
+	// SecretBox encrypts TOTP secrets at rest
+	SecretBox *pkg.SecretBox
+}
+
 func NewService(
 	repo *repository.Repository,
 	redisCli *redis.Client,
 	logger *slog.Logger,
 	hub *pkg.Hub,
-	secretKey string,
+	otpPepper string,
+	jwtKeys *pkg.JWTKeySet,
+	twoFactorCfg TwoFactorConfig,
+	venueTZ *time.Location,
 ) *Service {
+	sessions := newSessionStore(redisCli)
+	otp := newOtpManager(repo, redisCli, otpPepper)
+	guard := newLoginGuard(redisCli)
+	totp := newTotpManager(
+		repo,
+		redisCli,
+		otpPepper,
+		twoFactorCfg.SecretBox,
+		twoFactorCfg.Issuer,
+		twoFactorCfg.RequiredForAdmins,
+	)
+
 	return &Service{
-		UserValidator:  NewUserValidator(repo),
-		UserManagement: NewUserManagement(repo),
-		Auth:           NewAuthService(repo, secretKey, redisCli),
+		UserManagement: NewUserManagement(repo, sessions, guard),
+		Profile:        NewProfileService(repo, redisCli, sessions, otp, guard),
+		Export:         NewExportService(repo, redisCli, logger),
+		Auth:           NewAuthService(repo, jwtKeys, redisCli, sessions, otp, guard, totp),
+		TwoFactor:      NewTwoFactorService(repo, totp, guard),
 		Apartment:      NewApartmentService(repo),
-		Reservation:    NewReservation(repo),
+		Building:       NewBuildingService(repo),
+		Binding:        NewBindingService(repo),
+		Invitation:     NewInvitationService(repo),
+		Membership:     NewMembershipService(repo),
+		Import:         NewImportService(repo),
+		Layout:         NewLayoutService(repo),
+		Reservation:    NewReservation(repo, venueTZ),
+		SlotTemplates:  NewSlotTemplateService(repo, venueTZ),
 		Channel:        NewChannelService(repo),
 		Feedback:       NewFeedback(repo),
-		Order:          NewOrderService(repo),
+		Order:          NewOrderService(repo, logger),
 		Chat:           NewChat(repo, logger, hub),
 	}
 }
@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+	// sessionTouchInterval limits how often a request updates last_seen, so not every call writes to redis
+	sessionTouchInterval = time.Minute
+	maxUserAgentLength   = 256
+)
+
+// sessionStore keeps server-side sessions in redis:
+//
+//	session:{sid}          -> hash {user_id, refresh_hash, created_at, last_seen, device, ip, user_agent}
+//	refresh:{sha256(rt)}   -> sid
+//	user_sessions:{userId} -> set of sid
+//
+// Access tokens carry sid, so removing session:{sid} revokes them immediately.
+type sessionStore struct {
+	redisClient *redis.Client
+	tracer      trace.Tracer
+}
+
+func newSessionStore(redisCli *redis.Client) *sessionStore {
+	return &sessionStore{
+		redisClient: redisCli,
+		tracer:      otel.Tracer("sessionStore"),
+	}
+}
+
+func sessionKey(sid uuid.UUID) string {
+	return "session:" + sid.String()
+}
+
+func refreshKey(refreshHash string) string {
+	return "refresh:" + refreshHash
+}
+
+func userSessionsKey(userID uuid.UUID) string {
+	return "user_sessions:" + userID.String()
+}
+
+// Create opens a new session for the user and returns its id with a fresh refresh token.
+func (s *sessionStore) Create(ctx context.Context, userID uuid.UUID, client model.ClientInfo) (uuid.UUID, string, error) {
+	ctx, span := s.tracer.Start(ctx, "sessionStore.Create")
+	defer span.End()
+
+	sid := uuid.New()
+
+	refreshToken, refreshHash, err := newRefreshToken()
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	now := time.Now().Unix()
+
+	pipe := s.redisClient.TxPipeline()
+	pipe.HSet(ctx, sessionKey(sid), map[string]any{
+		"user_id":      userID.String(),
+		"refresh_hash": refreshHash,
+		"created_at":   now,
+		"last_seen":    now,
+		"device":       client.Device,
+		"ip":           client.IP,
+		"user_agent":   userAgent,
+	})
+	pipe.Expire(ctx, sessionKey(sid), refreshTokenTTL)
+	pipe.Set(ctx, refreshKey(refreshHash), sid.String(), refreshTokenTTL)
+	pipe.SAdd(ctx, userSessionsKey(userID), sid.String())
+	pipe.Expire(ctx, userSessionsKey(userID), refreshTokenTTL)
+
+	if _, err = pipe.Exec(ctx); err != nil {
+		return uuid.Nil, "", err
+	}
+
+	return sid, refreshToken, nil
+}
+
+// Rotate exchanges a refresh token for a new one. The old token stops working right away,
+// so a replayed refresh token is rejected.
+func (s *sessionStore) Rotate(ctx context.Context, refreshToken string) (model.Session, string, error) {
+	ctx, span := s.tracer.Start(ctx, "sessionStore.Rotate")
+	defer span.End()
+
+	oldHash := hashToken(refreshToken)
+
+	rawSid, err := s.redisClient.GetDel(ctx, refreshKey(oldHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return model.Session{}, "", model.ErrInvalidRefreshToken
+		}
+
+		return model.Session{}, "", err
+	}
+
+	sid, err := uuid.Parse(rawSid)
+	if err != nil {
+		return model.Session{}, "", model.ErrInvalidRefreshToken
+	}
+
+	sess, storedHash, err := s.get(ctx, sid)
+	if err != nil {
+		return model.Session{}, "", err
+	}
+
+	if storedHash != oldHash {
+		return model.Session{}, "", model.ErrInvalidRefreshToken
+	}
+
+	newToken, newHash, err := newRefreshToken()
+	if err != nil {
+		return model.Session{}, "", err
+	}
+
+	pipe := s.redisClient.TxPipeline()
+	pipe.HSet(ctx, sessionKey(sid), "refresh_hash", newHash, "last_seen", time.Now().Unix())
+	pipe.Expire(ctx, sessionKey(sid), refreshTokenTTL)
+	pipe.Set(ctx, refreshKey(newHash), sid.String(), refreshTokenTTL)
+	pipe.Expire(ctx, userSessionsKey(sess.UserID), refreshTokenTTL)
+
+	if _, err = pipe.Exec(ctx); err != nil {
+		return model.Session{}, "", err
+	}
+
+	return sess, newToken, nil
+}
+
+// Validate checks that the session is still alive and belongs to the user,
+// and records the request as the session's last activity.
+func (s *sessionStore) Validate(ctx context.Context, sid, userID uuid.UUID, clientIP string) error {
+	ctx, span := s.tracer.Start(ctx, "sessionStore.Validate")
+	defer span.End()
+
+	fields, err := s.redisClient.HMGet(ctx, sessionKey(sid), "user_id", "last_seen").Result()
+	if err != nil {
+		return err
+	}
+
+	owner, _ := fields[0].(string)
+	if owner == "" || owner != userID.String() {
+		return model.ErrSessionRevoked
+	}
+
+	rawLastSeen, _ := fields[1].(string)
+	if lastSeen, err := parseUnix(rawLastSeen); err == nil && time.Since(lastSeen) < sessionTouchInterval {
+		return nil
+	}
+
+	values := []any{"last_seen", time.Now().Unix()}
+	if clientIP != "" {
+		values = append(values, "ip", clientIP)
+	}
+
+	// HSet on a session that was revoked in the meantime would resurrect a bare hash, so check again
+	if n, err := s.redisClient.Exists(ctx, sessionKey(sid)).Result(); err != nil || n == 0 {
+		return err
+	}
+
+	return s.redisClient.HSet(ctx, sessionKey(sid), values...).Err()
+}
+
+// Get returns the session, ErrSessionRevoked if it does not exist anymore.
+func (s *sessionStore) Get(ctx context.Context, sid uuid.UUID) (model.Session, error) {
+	ctx, span := s.tracer.Start(ctx, "sessionStore.Get")
+	defer span.End()
+
+	sess, _, err := s.get(ctx, sid)
+
+	return sess, err
+}
+
+// List returns the user's sessions, most recently active first. Expired sessions are dropped from the index.
+func (s *sessionStore) List(ctx context.Context, userID uuid.UUID) ([]model.Session, error) {
+	ctx, span := s.tracer.Start(ctx, "sessionStore.List")
+	defer span.End()
+
+	sids, err := s.redisClient.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]model.Session, 0, len(sids))
+	for _, raw := range sids {
+		sid, err := uuid.Parse(raw)
+		if err != nil {
+			continue
+		}
+
+		sess, _, err := s.get(ctx, sid)
+		if err != nil {
+			if errors.Is(err, model.ErrSessionRevoked) {
+				if err = s.redisClient.SRem(ctx, userSessionsKey(userID), raw).Err(); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			return nil, err
+		}
+
+		if sess.UserID != userID {
+			continue
+		}
+
+		sessions = append(sessions, sess)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+// Revoke removes a single session together with its refresh token.
+func (s *sessionStore) Revoke(ctx context.Context, sid uuid.UUID) error {
+	ctx, span := s.tracer.Start(ctx, "sessionStore.Revoke")
+	defer span.End()
+
+	sess, refreshHash, err := s.get(ctx, sid)
+	if err != nil {
+		if errors.Is(err, model.ErrSessionRevoked) {
+			return nil
+		}
+
+		return err
+	}
+
+	pipe := s.redisClient.TxPipeline()
+	pipe.Del(ctx, sessionKey(sid), refreshKey(refreshHash))
+	pipe.SRem(ctx, userSessionsKey(sess.UserID), sid.String())
+
+	if _, err = pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RevokeAll removes every session of the user.
+func (s *sessionStore) RevokeAll(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := s.tracer.Start(ctx, "sessionStore.RevokeAll")
+	defer span.End()
+
+	sids, err := s.redisClient.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, raw := range sids {
+		sid, err := uuid.Parse(raw)
+		if err != nil {
+			continue
+		}
+
+		if err = s.Revoke(ctx, sid); err != nil {
+			return err
+		}
+	}
+
+	return s.redisClient.Del(ctx, userSessionsKey(userID)).Err()
+}
+
+// RevokeOthers removes every session of the user except keep.
+func (s *sessionStore) RevokeOthers(ctx context.Context, userID, keep uuid.UUID) error {
+	ctx, span := s.tracer.Start(ctx, "sessionStore.RevokeOthers")
+	defer span.End()
+
+	sids, err := s.redisClient.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, raw := range sids {
+		sid, err := uuid.Parse(raw)
+		if err != nil || sid == keep {
+			continue
+		}
+
+		if err = s.Revoke(ctx, sid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *sessionStore) get(ctx context.Context, sid uuid.UUID) (model.Session, string, error) {
+	fields, err := s.redisClient.HGetAll(ctx, sessionKey(sid)).Result()
+	if err != nil {
+		return model.Session{}, "", err
+	}
+
+	if len(fields) == 0 {
+		return model.Session{}, "", model.ErrSessionRevoked
+	}
+
+	userID, err := uuid.Parse(fields["user_id"])
+	if err != nil {
+		return model.Session{}, "", model.ErrSessionRevoked
+	}
+
+	sess := model.Session{
+		ID:        sid,
+		UserID:    userID,
+		Device:    fields["device"],
+		IP:        fields["ip"],
+		UserAgent: fields["user_agent"],
+	}
+
+	if createdAt, err := parseUnix(fields["created_at"]); err == nil {
+		sess.CreatedAt = createdAt
+	}
+
+	// sessions opened before last_seen was tracked fall back to created_at
+	sess.LastSeenAt = sess.CreatedAt
+	if lastSeen, err := parseUnix(fields["last_seen"]); err == nil {
+		sess.LastSeenAt = lastSeen
+	}
+
+	return sess, fields["refresh_hash"], nil
+}
+
+func newRefreshToken() (token, hash string, err error) {
+	token, err = randomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	return token, hashToken(token), nil
+}
+
+// randomToken returns 256 random bits, url-safe encoded
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func parseUnix(raw string) (time.Time, error) {
+	sec, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(sec, 0), nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type slotTemplateService struct {
+	repo   *repository.Repository
+	tracer trace.Tracer
+	// tz is the venue timezone the template times are in
+	tz *time.Location
+}
+
+func NewSlotTemplateService(repo *repository.Repository, tz *time.Location) SlotTemplates {
+	return &slotTemplateService{repo, otel.Tracer("slotTemplateService"), tz}
+}
+
+func (s *slotTemplateService) ListSlotTemplates(ctx context.Context) ([]model.SlotTemplate, error) {
+	ctx, span := s.tracer.Start(ctx, "slotTemplateService.ListSlotTemplates")
+	defer span.End()
+
+	return s.repo.SlotRepo.FindTemplates(ctx)
+}
+
+func (s *slotTemplateService) CreateSlotTemplate(ctx context.Context, req model.SlotTemplate) (*model.SlotTemplate, error) {
+	ctx, span := s.tracer.Start(ctx, "slotTemplateService.CreateSlotTemplate")
+	defer span.End()
+
+	t := &model.SlotTemplate{
+		Code:      req.Code,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Position:  req.Position,
+		IsActive:  req.IsActive,
+	}
+
+	if err := s.check(ctx, t); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SlotRepo.CreateTemplate(ctx, t); err != nil {
+		return nil, err
+	}
+
+	// daily slots of the new template are created the next time a day is requested
+	return t, nil
+}
+
+func (s *slotTemplateService) UpdateSlotTemplate(
+	ctx context.Context,
+	id int16,
+	req model.UpdateSlotTemplateRequest,
+) (*model.SlotTemplateChange, error) {
+	ctx, span := s.tracer.Start(ctx, "slotTemplateService.UpdateSlotTemplate")
+	defer span.End()
+
+	t, err := s.repo.SlotRepo.FindTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Code != nil {
+		t.Code = *req.Code
+	}
+
+	if req.StartTime != nil {
+		t.StartTime = *req.StartTime
+	}
+
+	if req.EndTime != nil {
+		t.EndTime = *req.EndTime
+	}
+
+	if req.Position != nil {
+		t.Position = *req.Position
+	}
+
+	if req.IsActive != nil {
+		t.IsActive = *req.IsActive
+	}
+
+	if err = s.check(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return s.repo.SlotRepo.UpdateTemplate(ctx, t, s.tz)
+}
+
+func (s *slotTemplateService) DeleteSlotTemplate(ctx context.Context, id int16) (*model.SlotTemplateChange, error) {
+	ctx, span := s.tracer.Start(ctx, "slotTemplateService.DeleteSlotTemplate")
+	defer span.End()
+
+	t, err := s.repo.SlotRepo.FindTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.SlotRepo.DeleteTemplate(ctx, t)
+}
+
+// check normalizes the times to HH:MM:SS and makes sure an active template does not overlap
+// another active one: the cinema has a single hall.
+func (s *slotTemplateService) check(ctx context.Context, t *model.SlotTemplate) error {
+	start, err := model.ParseSlotTime(t.StartTime)
+	if err != nil {
+		return err
+	}
+
+	end, err := model.ParseSlotTime(t.EndTime)
+	if err != nil {
+		return err
+	}
+
+	if end <= start {
+		return model.ErrInvalidSlotTime
+	}
+
+	t.StartTime, t.EndTime = formatSlotTime(start), formatSlotTime(end)
+
+	if !t.IsActive {
+		return nil
+	}
+
+	templates, err := s.repo.SlotRepo.FindTemplates(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range templates {
+		if other.ID == t.ID || !other.IsActive {
+			continue
+		}
+
+		otherStart, err := model.ParseSlotTime(other.StartTime)
+		if err != nil {
+			return err
+		}
+
+		otherEnd, err := model.ParseSlotTime(other.EndTime)
+		if err != nil {
+			return err
+		}
+
+		if start < otherEnd && otherStart < end {
+			return model.ErrSlotTemplateOverlap.WithErr(fmt.Errorf("template %s, %s-%s", other.Code, other.StartTime, other.EndTime))
+		}
+	}
+
+	return nil
+}
+
+func formatSlotTime(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute), int(d%time.Minute/time.Second))
+}
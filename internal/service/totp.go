@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"github.com/podpivasniki1488/assyl-backend/pkg"
+	"github.com/podpivasniki1488/assyl-backend/protopb"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	totpEnrollTTL           = 10 * time.Minute
+	totpReplayTTL           = 2 * time.Minute
+	twoFactorChallengeTTL   = 5 * time.Minute
+	twoFactorMaxAttempts    = 5
+	recoveryCodeCount       = 10
+	recoveryCodeBytes       = 5
+	recoveryCodeGroupLength = 4
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// totpManager handles TOTP secrets, recovery codes and pending 2FA logins. Secrets are stored
+// encrypted with secretBox, recovery codes only as HMAC with pepper:
+//
+//	totp_enroll:{userId}         -> secret waiting for the first valid code
+//	totp_used:{userId}:{step}    -> marker that rejects a replayed code
+//	2fa_challenge:{sha256(token)} -> hash {user_id, attempts} of a login waiting for the second factor
+type totpManager struct {
+	repo              *repository.Repository
+	redisClient       *redis.Client
+	pepper            []byte
+	secretBox         *pkg.SecretBox
+	issuer            string
+	requiredForAdmins bool
+	tracer            trace.Tracer
+}
+
+func newTotpManager(
+	repo *repository.Repository,
+	redisCli *redis.Client,
+	pepper string,
+	secretBox *pkg.SecretBox,
+	issuer string,
+	requiredForAdmins bool,
+) *totpManager {
+	return &totpManager{
+		repo:              repo,
+		redisClient:       redisCli,
+		pepper:            []byte(pepper),
+		secretBox:         secretBox,
+		issuer:            issuer,
+		requiredForAdmins: requiredForAdmins,
+		tracer:            otel.Tracer("totpManager"),
+	}
+}
+
+func totpEnrollKey(userID uuid.UUID) string {
+	return "totp_enroll:" + userID.String()
+}
+
+func totpUsedKey(userID uuid.UUID, step int64) string {
+	return fmt.Sprintf("totp_used:%s:%d", userID, step)
+}
+
+func twoFactorChallengeKey(token string) string {
+	return "2fa_challenge:" + hashToken(token)
+}
+
+// Required reports whether the role must use 2FA.
+func (t *totpManager) Required(role protopb.Role) bool {
+	return t.requiredForAdmins && (role == protopb.Role_ADMIN || role == protopb.Role_GOD)
+}
+
+// StartEnrollment generates a new secret. It becomes active only after CompleteEnrollment.
+func (t *totpManager) StartEnrollment(ctx context.Context, user model.User) (model.TotpEnrollment, error) {
+	ctx, span := t.tracer.Start(ctx, "totpManager.StartEnrollment")
+	defer span.End()
+
+	if user.TotpEnabled {
+		return model.TotpEnrollment{}, model.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := pkg.GenerateTotpSecret()
+	if err != nil {
+		return model.TotpEnrollment{}, err
+	}
+
+	if err = t.redisClient.Set(ctx, totpEnrollKey(user.ID), secret, totpEnrollTTL).Err(); err != nil {
+		return model.TotpEnrollment{}, err
+	}
+
+	return model.TotpEnrollment{
+		Secret: secret,
+		URI:    pkg.TotpURI(t.issuer, user.Username, secret),
+	}, nil
+}
+
+// CompleteEnrollment activates the pending secret if code matches it and returns fresh recovery codes.
+func (t *totpManager) CompleteEnrollment(ctx context.Context, user *model.User, code string) ([]string, error) {
+	ctx, span := t.tracer.Start(ctx, "totpManager.CompleteEnrollment")
+	defer span.End()
+
+	if user.TotpEnabled {
+		return nil, model.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := t.redisClient.Get(ctx, totpEnrollKey(user.ID)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, model.ErrTwoFactorEnrollmentExpired
+		}
+
+		return nil, err
+	}
+
+	if err = t.checkTotp(ctx, user.ID, secret, code); err != nil {
+		return nil, err
+	}
+
+	sealed, err := t.secretBox.Seal(secret, user.ID[:])
+	if err != nil {
+		return nil, err
+	}
+
+	user.TotpSecret = sealed
+	user.TotpEnabled = true
+
+	if _, err = t.repo.UserRepo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err = t.redisClient.Del(ctx, totpEnrollKey(user.ID)).Err(); err != nil {
+		return nil, err
+	}
+
+	return t.NewRecoveryCodes(ctx, user.ID)
+}
+
+// Verify accepts either a current TOTP code or an unused recovery code.
+func (t *totpManager) Verify(ctx context.Context, user model.User, code string) error {
+	ctx, span := t.tracer.Start(ctx, "totpManager.Verify")
+	defer span.End()
+
+	if !user.TotpEnabled {
+		return model.ErrTwoFactorNotEnabled
+	}
+
+	secret, err := t.secret(user)
+	if err != nil {
+		return err
+	}
+
+	err = t.checkTotp(ctx, user.ID, secret, code)
+	if err == nil || !errors.Is(err, model.ErrTwoFactorInvalid) {
+		return err
+	}
+
+	return t.repo.UserRepo.UseRecoveryCode(ctx, user.ID, t.hashRecoveryCode(code))
+}
+
+// Disable removes the secret and all recovery codes.
+func (t *totpManager) Disable(ctx context.Context, user *model.User) error {
+	ctx, span := t.tracer.Start(ctx, "totpManager.Disable")
+	defer span.End()
+
+	user.TotpSecret = ""
+	user.TotpEnabled = false
+
+	if _, err := t.repo.UserRepo.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+
+	return t.repo.UserRepo.ReplaceRecoveryCodes(ctx, user.ID, nil)
+}
+
+// NewRecoveryCodes replaces the user's recovery codes. Only hashes are stored, the codes are shown once.
+func (t *totpManager) NewRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	ctx, span := t.tracer.Start(ctx, "totpManager.NewRecoveryCodes")
+	defer span.End()
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, code)
+		hashes = append(hashes, t.hashRecoveryCode(code))
+	}
+
+	if err := t.repo.UserRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// CreateChallenge remembers a login that passed the password check and waits for the second factor.
+func (t *totpManager) CreateChallenge(ctx context.Context, userID uuid.UUID) (string, error) {
+	ctx, span := t.tracer.Start(ctx, "totpManager.CreateChallenge")
+	defer span.End()
+
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	pipe := t.redisClient.TxPipeline()
+	pipe.HSet(ctx, twoFactorChallengeKey(token), map[string]any{
+		"user_id":  userID.String(),
+		"attempts": 0,
+	})
+	pipe.Expire(ctx, twoFactorChallengeKey(token), twoFactorChallengeTTL)
+
+	if _, err = pipe.Exec(ctx); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// ChallengeUser returns the user the challenge was issued for.
+func (t *totpManager) ChallengeUser(ctx context.Context, token string) (uuid.UUID, error) {
+	raw, err := t.redisClient.HGet(ctx, twoFactorChallengeKey(token), "user_id").Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return uuid.Nil, model.ErrTwoFactorChallenge
+		}
+
+		return uuid.Nil, err
+	}
+
+	userID, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, model.ErrTwoFactorChallenge
+	}
+
+	return userID, nil
+}
+
+// FailChallenge counts a wrong code. After twoFactorMaxAttempts the challenge is dropped
+// and the user has to start over with the password.
+func (t *totpManager) FailChallenge(ctx context.Context, token string) error {
+	attempts, err := t.redisClient.HIncrBy(ctx, twoFactorChallengeKey(token), "attempts", 1).Result()
+	if err != nil {
+		return err
+	}
+
+	if attempts >= twoFactorMaxAttempts {
+		return t.DropChallenge(ctx, token)
+	}
+
+	return nil
+}
+
+func (t *totpManager) DropChallenge(ctx context.Context, token string) error {
+	return t.redisClient.Del(ctx, twoFactorChallengeKey(token)).Err()
+}
+
+// SealLegacySecrets encrypts the secrets that were stored in plain text before encryption existed.
+func (t *totpManager) SealLegacySecrets(ctx context.Context) (int, error) {
+	ctx, span := t.tracer.Start(ctx, "totpManager.SealLegacySecrets")
+	defer span.End()
+
+	users, err := t.repo.UserRepo.FindWithTotpSecret(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sealed := 0
+	for _, u := range users {
+		if pkg.IsSealed(u.TotpSecret) {
+			continue
+		}
+
+		value, err := t.secretBox.Seal(u.TotpSecret, u.ID[:])
+		if err != nil {
+			return sealed, err
+		}
+
+		if err = t.repo.UserRepo.ReplaceTotpSecret(ctx, u.ID, u.TotpSecret, value); err != nil {
+			return sealed, err
+		}
+
+		sealed++
+	}
+
+	return sealed, nil
+}
+
+// secret decrypts the user's secret. A plain one is left from before encryption
+// and is accepted until SealLegacySecrets runs.
+func (t *totpManager) secret(user model.User) (string, error) {
+	if !pkg.IsSealed(user.TotpSecret) {
+		return user.TotpSecret, nil
+	}
+
+	return t.secretBox.Open(user.TotpSecret, user.ID[:])
+}
+
+// checkTotp validates the code and makes sure every time step is used only once.
+func (t *totpManager) checkTotp(ctx context.Context, userID uuid.UUID, secret, code string) error {
+	step, ok := pkg.ValidateTotp(secret, code, time.Now())
+	if !ok {
+		return model.ErrTwoFactorInvalid
+	}
+
+	fresh, err := t.redisClient.SetNX(ctx, totpUsedKey(userID, step), 1, totpReplayTTL).Result()
+	if err != nil {
+		return err
+	}
+
+	if !fresh {
+		return model.ErrTwoFactorInvalid
+	}
+
+	return nil
+}
+
+func (t *totpManager) hashRecoveryCode(code string) string {
+	normalized := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+
+	mac := hmac.New(sha256.New, t.pepper)
+	mac.Write([]byte("recovery:" + normalized))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateRecoveryCode returns a code like ABCD-EFGH
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	raw := recoveryCodeEncoding.EncodeToString(buf)
+
+	return raw[:recoveryCodeGroupLength] + "-" + raw[recoveryCodeGroupLength:], nil
+}
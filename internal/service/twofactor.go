@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/podpivasniki1488/assyl-backend/internal/model"
+	"github.com/podpivasniki1488/assyl-backend/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type twoFactorService struct {
+	repo   *repository.Repository
+	tracer trace.Tracer
+	totp   *totpManager
+	guard  *loginGuard
+}
+
+func NewTwoFactorService(repo *repository.Repository, totp *totpManager, guard *loginGuard) TwoFactor {
+	return &twoFactorService{
+		repo:   repo,
+		tracer: otel.Tracer("twoFactorService"),
+		totp:   totp,
+		guard:  guard,
+	}
+}
+
+func (t *twoFactorService) StartEnrollment(ctx context.Context, userID uuid.UUID) (model.TotpEnrollment, error) {
+	ctx, span := t.tracer.Start(ctx, "twoFactorService.StartEnrollment")
+	defer span.End()
+
+	u, err := t.findUser(ctx, userID)
+	if err != nil {
+		return model.TotpEnrollment{}, err
+	}
+
+	return t.totp.StartEnrollment(ctx, *u)
+}
+
+func (t *twoFactorService) Enable(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	ctx, span := t.tracer.Start(ctx, "twoFactorService.Enable")
+	defer span.End()
+
+	u, err := t.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.totp.CompleteEnrollment(ctx, u, code)
+}
+
+func (t *twoFactorService) Disable(ctx context.Context, userID uuid.UUID, code string) error {
+	ctx, span := t.tracer.Start(ctx, "twoFactorService.Disable")
+	defer span.End()
+
+	u, err := t.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if t.totp.Required(u.RoleID) {
+		return model.ErrTwoFactorEnforced
+	}
+
+	if err = t.verify(ctx, *u, code); err != nil {
+		return err
+	}
+
+	return t.totp.Disable(ctx, u)
+}
+
+func (t *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	ctx, span := t.tracer.Start(ctx, "twoFactorService.RegenerateRecoveryCodes")
+	defer span.End()
+
+	u, err := t.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = t.verify(ctx, *u, code); err != nil {
+		return nil, err
+	}
+
+	return t.totp.NewRecoveryCodes(ctx, u.ID)
+}
+
+func (t *twoFactorService) SealLegacySecrets(ctx context.Context) (int, error) {
+	ctx, span := t.tracer.Start(ctx, "twoFactorService.SealLegacySecrets")
+	defer span.End()
+
+	return t.totp.SealLegacySecrets(ctx)
+}
+
+// verify checks the code of a signed-in user. Wrong codes count against the username like wrong
+// passwords, otherwise a stolen access token would allow guessing until 2FA can be turned off.
+func (t *twoFactorService) verify(ctx context.Context, u model.User, code string) error {
+	if err := t.guard.Check(ctx, u.Username, ""); err != nil {
+		return err
+	}
+
+	err := t.totp.Verify(ctx, u, code)
+	if err == nil {
+		return t.guard.Succeed(ctx, u.Username)
+	}
+
+	if !errors.Is(err, model.ErrTwoFactorInvalid) {
+		return err
+	}
+
+	locked, err := t.guard.Fail(ctx, u.Username, "")
+	if err != nil {
+		return err
+	}
+
+	if locked {
+		return model.ErrLoginLocked
+	}
+
+	return model.ErrTwoFactorInvalid
+}
+
+func (t *twoFactorService) findUser(ctx context.Context, userID uuid.UUID) (*model.User, error) {
+	u, err := t.repo.UserRepo.FindById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.ID == uuid.Nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	return u, nil
+}
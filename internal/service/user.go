@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/podpivasniki1488/assyl-backend/internal/model"
@@ -12,69 +13,200 @@ import (
 )
 
 type userManagement struct {
-	repo   *repository.Repository
-	tracer trace.Tracer
+	repo     *repository.Repository
+	tracer   trace.Tracer
+	sessions *sessionStore
+	guard    *loginGuard
 }
 
-func NewUserManagement(repo *repository.Repository) UserManagement {
-	return &userManagement{repo, otel.Tracer("userManagement")}
+func NewUserManagement(repo *repository.Repository, sessions *sessionStore, guard *loginGuard) UserManagement {
+	return &userManagement{repo, otel.Tracer("userManagement"), sessions, guard}
 }
 
-func (u *userManagement) DeleteUserByUsername(ctx context.Context, username string) error {
+// DeleteUserByUsername erases the account in Postgres and Mongo, see model.ErasureMode,
+// and signs the user out everywhere.
+func (u *userManagement) DeleteUserByUsername(
+	ctx context.Context,
+	username string,
+	mode model.ErasureMode,
+) (*model.ErasureReport, error) {
+	ctx, span := u.tracer.Start(ctx, "userManagement.DeleteUserByUsername")
+	defer span.End()
+
+	if !mode.IsValid() {
+		return nil, model.ErrInvalidInput
+	}
+
 	userToDelete, err := u.repo.UserRepo.FindByUsername(ctx, username)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if userToDelete.RoleID == protopb.Role_GOD || userToDelete.RoleID == protopb.Role_ADMIN {
-		return model.ErrAdminsCannotBeDeleted
+		return nil, model.ErrAdminsCannotBeDeleted
 	}
 
-	if err = u.repo.UserRepo.DeleteByUsername(ctx, username); err != nil {
-		return err
+	report, err := u.repo.ErasureRepo.EraseUser(ctx, userToDelete.ID, mode)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	if err = u.sessions.RevokeAll(ctx, userToDelete.ID); err != nil {
+		return nil, err
+	}
+
+	report.SessionsRevoked = true
+
+	if err = u.guard.Clear(ctx, username); err != nil {
+		return nil, err
+	}
+
+	return report, nil
 }
 
-func (u *userManagement) BindApartmentToUser(ctx context.Context, username string, apartmentId uuid.UUID) error {
-	ctx, span := u.tracer.Start(ctx, "userManagement_BindApartmentToUser")
+func (u *userManagement) ListUsers(ctx context.Context, req model.GetUsersRequest) (model.Page[model.User], error) {
+	ctx, span := u.tracer.Start(ctx, "userManagement.ListUsers")
 	defer span.End()
 
-	ap, err := u.repo.ApartmentRepo.GetApartmentByID(ctx, apartmentId)
+	req.Pagination = req.Pagination.Normalize()
+
+	users, total, err := u.repo.UserRepo.FindByFilters(ctx, &req)
 	if err != nil {
-		return err
+		return model.Page[model.User]{}, err
+	}
+
+	return model.Page[model.User]{
+		Items:    users,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
+
+func (u *userManagement) ChangeRole(
+	ctx context.Context,
+	actorID uuid.UUID,
+	actorRole protopb.Role,
+	username string,
+	role protopb.Role,
+) error {
+	ctx, span := u.tracer.Start(ctx, "userManagement.ChangeRole")
+	defer span.End()
+
+	if _, ok := protopb.Role_name[int32(role)]; !ok || role == protopb.Role_STATUS_UNSPECIFIED {
+		return model.ErrInvalidRole
 	}
 
-	currUser, err := u.repo.UserRepo.FindByUsername(ctx, username)
+	target, err := u.getManageableUser(ctx, actorID, actorRole, username)
 	if err != nil {
 		return err
 	}
 
-	if err = u.checkUserAccess(ctx, *currUser, *ap); err != nil {
-		return err
+	if isAdminRole(role) && !model.HasPermission(actorRole, model.PermUserManageAdmins) {
+		return model.ErrPermissionDenied
+	}
+
+	if target.RoleID == role {
+		return nil
 	}
 
-	ap.OwnerId = &currUser.ID
+	demoted := role < target.RoleID
+	target.RoleID = role
 
-	if err = u.repo.ApartmentRepo.UpdateApartment(ctx, ap); err != nil {
+	if _, err = u.repo.UserRepo.UpdateUser(ctx, target); err != nil {
 		return err
 	}
 
+	// promotions are picked up on the next token refresh, demotions must take effect right away
+	if demoted {
+		return u.sessions.RevokeAll(ctx, target.ID)
+	}
+
 	return nil
 }
 
-func (u *userManagement) checkUserAccess(ctx context.Context, currUser model.User, apartment model.Apartment) error {
-	ctx, span := u.tracer.Start(ctx, "userManagement_checkUserAccess")
+// SetApproval approves the user, which also confirms the username and enables a disabled account,
+// or disables it. A disabled user is signed out everywhere and cannot sign in again until an admin
+// approves them: unlike IsApproved, the flag is not touched by the user's own confirmation flows.
+func (u *userManagement) SetApproval(
+	ctx context.Context,
+	actorID uuid.UUID,
+	actorRole protopb.Role,
+	username string,
+	approved bool,
+) error {
+	ctx, span := u.tracer.Start(ctx, "userManagement.SetApproval")
 	defer span.End()
 
-	if currUser.RoleID == protopb.Role_ADMIN || currUser.RoleID == protopb.Role_GOD {
-		return nil
+	target, err := u.getManageableUser(ctx, actorID, actorRole, username)
+	if err != nil {
+		return err
+	}
+
+	if !approved {
+		if !target.IsDisabled() {
+			now := time.Now()
+			if err = u.repo.UserRepo.SetDisabled(ctx, target.ID, &now); err != nil {
+				return err
+			}
+		}
+
+		return u.sessions.RevokeAll(ctx, target.ID)
+	}
+
+	if !target.IsApproved {
+		target.IsApproved = true
+
+		if _, err = u.repo.UserRepo.UpdateUser(ctx, target); err != nil {
+			return err
+		}
 	}
 
-	if apartment.OwnerId != nil && currUser.ID != *apartment.OwnerId {
-		return model.ErrApartmentAlreadyBound
+	if target.IsDisabled() {
+		return u.repo.UserRepo.SetDisabled(ctx, target.ID, nil)
 	}
 
 	return nil
 }
+
+// getManageableUser loads the target user and makes sure the actor is allowed to change it:
+// nobody edits themselves, and only GOD touches ADMIN/GOD accounts.
+func (u *userManagement) getManageableUser(
+	ctx context.Context,
+	actorID uuid.UUID,
+	actorRole protopb.Role,
+	username string,
+) (*model.User, error) {
+	target, err := u.repo.UserRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.ID == actorID {
+		return nil, model.ErrSelfModification
+	}
+
+	if isAdminRole(target.RoleID) && !model.HasPermission(actorRole, model.PermUserManageAdmins) {
+		return nil, model.ErrPermissionDenied
+	}
+
+	return target, nil
+}
+
+func isAdminRole(role protopb.Role) bool {
+	return role == protopb.Role_ADMIN || role == protopb.Role_GOD
+}
+
+func (u *userManagement) ListLockouts(ctx context.Context) ([]model.LoginLockout, error) {
+	ctx, span := u.tracer.Start(ctx, "userManagement.ListLockouts")
+	defer span.End()
+
+	return u.guard.Lockouts(ctx)
+}
+
+func (u *userManagement) ClearLockout(ctx context.Context, username string) error {
+	ctx, span := u.tracer.Start(ctx, "userManagement.ClearLockout")
+	defer span.End()
+
+	return u.guard.Clear(ctx, username)
+}
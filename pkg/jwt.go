@@ -0,0 +1,347 @@
+package pkg
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	JWTAlgHS256 = "HS256"
+	JWTAlgRS256 = "RS256"
+	JWTAlgEdDSA = "EdDSA"
+)
+
+var ErrUnknownJWTKey = errors.New("unknown jwt signing key")
+
+type JWTConfig struct {
+	// Algorithm is HS256 (default), RS256 or EdDSA
+	Algorithm string
+	// Secret is used only in HS256 mode
+	Secret string
+	// SigningKeyPEM is the current private key (PKCS#8, or PKCS#1 for RSA)
+	SigningKeyPEM string
+	// VerificationKeysPEM holds public keys (PKIX) of previous signing keys that are still accepted
+	// while tokens signed by them may be alive
+	VerificationKeysPEM string
+}
+
+type jwtKey struct {
+	kid    string
+	method jwt.SigningMethod
+	public crypto.PublicKey
+}
+
+// JWTKeySet signs and verifies access tokens. In asymmetric mode every token carries a kid header,
+// so several verification keys can be active at once and a new signing key can be rolled out
+// without invalidating tokens signed by the previous one.
+type JWTKeySet struct {
+	method  jwt.SigningMethod
+	secret  []byte
+	signKid string
+	signKey crypto.Signer
+	keys    map[string]jwtKey
+}
+
+func NewJWTKeySet(cfg JWTConfig) (*JWTKeySet, error) {
+	switch cfg.Algorithm {
+	case "", JWTAlgHS256:
+		if cfg.Secret == "" {
+			return nil, errors.New("jwt secret is required for HS256")
+		}
+
+		return &JWTKeySet{
+			method: jwt.SigningMethodHS256,
+			secret: []byte(cfg.Secret),
+		}, nil
+	case JWTAlgRS256, JWTAlgEdDSA:
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", cfg.Algorithm)
+	}
+
+	signKey, err := parsePrivateKey(cfg.SigningKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	ks := &JWTKeySet{
+		signKey: signKey,
+		keys:    make(map[string]jwtKey),
+	}
+
+	signing, err := newJWTKey(signKey.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	if signing.method.Alg() != cfg.Algorithm {
+		return nil, fmt.Errorf("signing key does not match jwt algorithm %s", cfg.Algorithm)
+	}
+
+	ks.method = signing.method
+	ks.signKid = signing.kid
+	ks.keys[signing.kid] = signing
+
+	publicKeys, err := parsePublicKeys(cfg.VerificationKeysPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pub := range publicKeys {
+		key, err := newJWTKey(pub)
+		if err != nil {
+			return nil, err
+		}
+
+		ks.keys[key.kid] = key
+	}
+
+	return ks, nil
+}
+
+// Sign signs claims with the current key.
+func (k *JWTKeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+
+	if k.secret != nil {
+		return token.SignedString(k.secret)
+	}
+
+	token.Header["kid"] = k.signKid
+
+	return token.SignedString(k.signKey)
+}
+
+// Parse verifies the token signature against the known keys and validates registered claims.
+func (k *JWTKeySet) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, k.keyFunc, jwt.WithValidMethods(k.validMethods()))
+}
+
+func (k *JWTKeySet) keyFunc(token *jwt.Token) (any, error) {
+	if k.secret != nil {
+		return k.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := k.keys[kid]
+	if !ok || key.method.Alg() != token.Method.Alg() {
+		return nil, ErrUnknownJWTKey
+	}
+
+	return key.public, nil
+}
+
+func (k *JWTKeySet) validMethods() []string {
+	if k.secret != nil {
+		return []string{k.method.Alg()}
+	}
+
+	seen := make(map[string]bool)
+	res := make([]string, 0, 2)
+
+	for _, key := range k.keys {
+		if !seen[key.method.Alg()] {
+			seen[key.method.Alg()] = true
+			res = append(res, key.method.Alg())
+		}
+	}
+
+	return res
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// PublicKey decodes an RSA, EC or Ed25519 JWK, e.g. one published by an OpenID provider.
+func (j JWK) PublicKey() (crypto.PublicKey, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil {
+			return nil, fmt.Errorf("decode jwk n: %w", err)
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil {
+			return nil, fmt.Errorf("decode jwk e: %w", err)
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported jwk curve %q", j.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil {
+			return nil, fmt.Errorf("decode jwk x: %w", err)
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(j.Y)
+		if err != nil {
+			return nil, fmt.Errorf("decode jwk y: %w", err)
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported jwk curve %q", j.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil {
+			return nil, fmt.Errorf("decode jwk x: %w", err)
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 jwk")
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported jwk type %q", j.Kty)
+	}
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns public verification keys. In HS256 mode the set is empty, the secret is never published.
+func (k *JWTKeySet) JWKS() JWKS {
+	res := JWKS{Keys: make([]JWK, 0, len(k.keys))}
+
+	for _, key := range k.keys {
+		jwk := JWK{
+			Kid: key.kid,
+			Use: "sig",
+			Alg: key.method.Alg(),
+		}
+
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+
+		res.Keys = append(res.Keys, jwk)
+	}
+
+	sort.Slice(res.Keys, func(i, j int) bool {
+		return res.Keys[i].Kid < res.Keys[j].Kid
+	})
+
+	return res
+}
+
+func newJWTKey(pub crypto.PublicKey) (jwtKey, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return jwtKey{}, err
+	}
+
+	// kid is derived from the key itself, so it stays the same after restarts and rotations
+	sum := sha256.Sum256(der)
+	key := jwtKey{
+		kid:    base64.RawURLEncoding.EncodeToString(sum[:12]),
+		public: pub,
+	}
+
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return jwtKey{}, fmt.Errorf("unsupported public key type %T", pub)
+	}
+
+	return key, nil
+}
+
+func parsePrivateKey(rawPEM string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(rawPEM))
+	if block == nil {
+		return nil, errors.New("jwt signing key is not a valid PEM")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse jwt signing key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	return signer, nil
+}
+
+func parsePublicKeys(rawPEM string) ([]crypto.PublicKey, error) {
+	var (
+		rest = []byte(rawPEM)
+		res  []crypto.PublicKey
+	)
+
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return res, nil
+		}
+
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse jwt verification key: %w", err)
+		}
+
+		res = append(res, pub)
+	}
+}
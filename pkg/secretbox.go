@@ -0,0 +1,84 @@
+package pkg
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// sealedPrefix marks a value produced by SecretBox.Seal, so the key or format can be changed later
+// and values stored before encryption existed can be told apart.
+const sealedPrefix = "v1:"
+
+var ErrSecretBoxOpen = errors.New("secret box: cannot decrypt value")
+
+// SecretBox encrypts small secrets, such as TOTP seeds, with AES-256-GCM before they are stored.
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewSecretBox takes a base64 encoded 32-byte key.
+func NewSecretBox(keyBase64 string) (*SecretBox, error) {
+	key, err := base64.StdEncoding.DecodeString(keyBase64)
+	if err != nil {
+		return nil, fmt.Errorf("secret box key: %w", err)
+	}
+
+	if len(key) != 32 {
+		return nil, fmt.Errorf("secret box key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecretBox{aead: aead}, nil
+}
+
+// Seal encrypts plain. The same additional data has to be passed to Open, it binds the value
+// to its owner so it cannot be copied to another row.
+func (b *SecretBox) Seal(plain string, additionalData []byte) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := b.aead.Seal(nonce, nonce, []byte(plain), additionalData)
+
+	return sealedPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (b *SecretBox) Open(sealed string, additionalData []byte) (string, error) {
+	if !IsSealed(sealed) {
+		return "", ErrSecretBoxOpen
+	}
+
+	raw, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(sealed, sealedPrefix))
+	if err != nil || len(raw) < b.aead.NonceSize() {
+		return "", ErrSecretBoxOpen
+	}
+
+	nonce, ciphertext := raw[:b.aead.NonceSize()], raw[b.aead.NonceSize():]
+
+	plain, err := b.aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return "", ErrSecretBoxOpen
+	}
+
+	return string(plain), nil
+}
+
+// IsSealed reports whether the value was produced by Seal.
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
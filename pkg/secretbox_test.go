@@ -0,0 +1,62 @@
+package pkg
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func testSecretBox(t *testing.T) *SecretBox {
+	t.Helper()
+
+	box, err := NewSecretBox(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return box
+}
+
+func TestSecretBox(t *testing.T) {
+	box := testSecretBox(t)
+	owner := []byte("user-1")
+
+	sealed, err := box.Seal("JBSWY3DPEHPK3PXP", owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsSealed(sealed) || strings.Contains(sealed, "JBSWY3DPEHPK3PXP") {
+		t.Fatalf("value is not sealed: %q", sealed)
+	}
+
+	plain, err := box.Open(sealed, owner)
+	if err != nil || plain != "JBSWY3DPEHPK3PXP" {
+		t.Fatalf("Open = %q, %v", plain, err)
+	}
+
+	if _, err = box.Open(sealed, []byte("user-2")); err == nil {
+		t.Error("a value sealed for another owner must not open")
+	}
+
+	tampered := sealed[:len(sealed)-2] + "AA"
+	if tampered == sealed {
+		tampered = sealed[:len(sealed)-2] + "BB"
+	}
+
+	if _, err = box.Open(tampered, owner); err == nil {
+		t.Error("a tampered value must not open")
+	}
+
+	if _, err = box.Open("JBSWY3DPEHPK3PXP", owner); err == nil {
+		t.Error("a plain value must not open")
+	}
+}
+
+func TestNewSecretBoxKey(t *testing.T) {
+	for _, key := range []string{"", "not base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := NewSecretBox(key); err == nil {
+			t.Errorf("NewSecretBox(%q) accepted an invalid key", key)
+		}
+	}
+}